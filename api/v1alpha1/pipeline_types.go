@@ -17,12 +17,44 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// PausedAnnotation stops the operator from syncing a Pipeline to Fleet Management
+// while it is set to "true". The Pipeline is neither updated nor deleted remotely.
+const PausedAnnotation = "fleetmanagement.grafana.com/paused"
+
+// PromoteAnnotation promotes the canary of a Pipeline rollout to the main
+// pipeline when set to the status.rollout.canaryHash of that canary
+const PromoteAnnotation = "fleetmanagement.grafana.com/promote"
+
+// ApproveAnnotation approves a change of a Pipeline that requires
+// approval when set to the hash in status.approval.pending.hash
+const ApproveAnnotation = "fleetmanagement.grafana.com/approve"
+
+// ChangedByAnnotation records the user who last changed the spec of a Pipeline,
+// PipelineTemplate, PipelineFragment or PipelineModule. It is set by the
+// admission webhook.
+const ChangedByAnnotation = "fleetmanagement.grafana.com/changed-by"
+
+// ApprovedByAnnotation records the user who set the approve annotation.
+// It is set by the admission webhook.
+const ApprovedByAnnotation = "fleetmanagement.grafana.com/approved-by"
+
+// AdoptLegacySourceAnnotation lets a Pipeline adopt a remote pipeline with the
+// legacy <namespace>/<name> source, synced before --cluster-name was set, when
+// set to "true". It only applies until the Pipeline was synced with its cluster.
+const AdoptLegacySourceAnnotation = "fleetmanagement.grafana.com/adopt-legacy-source"
+
+// GeneratedFromLabel is set to monitoring.coreos.com on the Pipelines generated
+// from Prometheus Operator monitors
+const GeneratedFromLabel = "fleetmanagement.grafana.com/generated-from"
+
 // ConfigType represents the type of collector configuration
 // +kubebuilder:validation:Enum=Alloy;OpenTelemetryCollector
 type ConfigType string
@@ -67,6 +99,55 @@ const (
 	fleetAPISourceTypeUnspecified = "SOURCE_TYPE_UNSPECIFIED"
 )
 
+// NamingStrategy determines how the remote pipeline name is derived when spec.name is not set
+// +kubebuilder:validation:Enum=MetadataName;NamespacedName;Template
+type NamingStrategy string
+
+const (
+	// NamingStrategyMetadataName uses metadata.name as is
+	NamingStrategyMetadataName NamingStrategy = "MetadataName"
+
+	// NamingStrategyNamespacedName joins metadata.namespace and metadata.name
+	NamingStrategyNamespacedName NamingStrategy = "NamespacedName"
+
+	// NamingStrategyTemplate renders spec.nameTemplate
+	NamingStrategyTemplate NamingStrategy = "Template"
+)
+
+// MatchOperator is the comparison operator of a structured matcher
+// +kubebuilder:validation:Enum="=";"!=";"=~";"!~"
+type MatchOperator string
+
+const (
+	// MatchOperatorEqual matches attributes equal to the value
+	MatchOperatorEqual MatchOperator = "="
+
+	// MatchOperatorNotEqual matches attributes not equal to the value
+	MatchOperatorNotEqual MatchOperator = "!="
+
+	// MatchOperatorRegexp matches attributes matching the regular expression
+	MatchOperatorRegexp MatchOperator = "=~"
+
+	// MatchOperatorNotRegexp matches attributes not matching the regular expression
+	MatchOperatorNotRegexp MatchOperator = "!~"
+)
+
+// Matcher is the structured form of a collector matcher
+type Matcher struct {
+	// Key is the collector attribute to match
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Op is the comparison operator (=, !=, =~, !~)
+	// +required
+	Op MatchOperator `json:"op"`
+
+	// Value to compare against. Regular expressions are fully anchored.
+	// +optional
+	Value string `json:"value"`
+}
+
 // PipelineSource defines the origin source of the pipeline
 type PipelineSource struct {
 	// Type specifies the source type (Git, Terraform, Kubernetes, Unspecified)
@@ -138,17 +219,150 @@ func SourceTypeFromFleetAPI(apiType string) SourceType {
 	}
 }
 
+// TemplateReference refers to a PipelineTemplate in the Pipeline's namespace
+type TemplateReference struct {
+	// Name of the PipelineTemplate
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ModuleReference refers to a PipelineModule in the Pipeline's namespace
+type ModuleReference struct {
+	// Name of the PipelineModule
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SecretReference refers to a Secret in the Pipeline's namespace
+type SecretReference struct {
+	// Name of the Secret
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ScheduleWindow is a recurring period during which the pipeline is enabled
+type ScheduleWindow struct {
+	// Start is a five-field cron expression (minute hour day-of-month month day-of-week)
+	// for the start of each window, e.g. "0 9 * * MON-FRI"
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+
+	// Duration of each window, e.g. "8h"
+	// +required
+	Duration metav1.Duration `json:"duration"`
+}
+
+// PipelineSchedule restricts when an enabled pipeline is enabled in Fleet Management
+type PipelineSchedule struct {
+	// Windows during which the pipeline is enabled. Outside of every window it is disabled.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	Windows []ScheduleWindow `json:"windows"`
+
+	// TimeZone the cron expressions are evaluated in, as an IANA name such as
+	// "Europe/Paris". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// DebugTarget sends a temporary pipeline to a single collector
+type DebugTarget struct {
+	// CollectorID is the ID of the only collector receiving the pipeline
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	CollectorID string `json:"collectorID"`
+
+	// TTL after which the remote pipeline and this Pipeline are deleted,
+	// counted from the creation of this Pipeline, e.g. "1h". The remote
+	// pipeline is deleted even if the Pipeline is paused.
+	// +required
+	TTL metav1.Duration `json:"ttl"`
+}
+
+// RolloutStrategy rolls contents changes out to canary collectors first
+type RolloutStrategy struct {
+	// CanaryMatchers are added to the pipeline's matchers to select the canary
+	// collectors, e.g. ["canary=true"]. The main pipeline gets the negated
+	// matcher while a canary is tried, so only one matcher is allowed: the
+	// negation of several could not be expressed as matchers that all match.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=1
+	CanaryMatchers []string `json:"canaryMatchers"`
+
+	// BakeTime after which a canary is promoted to the main pipeline. Without
+	// it, canaries are only promoted by the promote annotation.
+	// +optional
+	BakeTime *metav1.Duration `json:"bakeTime,omitempty"`
+}
+
 // PipelineSpec defines the desired state of Pipeline
+// +kubebuilder:validation:XValidation:rule="!(has(self.contents) && has(self.templateRef))",message="contents and templateRef are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="has(self.contents) || has(self.templateRef) || has(self.fragmentSelector)",message="one of contents, templateRef or fragmentSelector must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.parameters) || has(self.templateRef)",message="parameters require templateRef"
+// +kubebuilder:validation:XValidation:rule="has(self.debug) == has(oldSelf.debug) && (!has(self.debug) || self.debug == oldSelf.debug)",message="debug can only be set when the Pipeline is created"
 type PipelineSpec struct {
 	// Name of the pipeline (unique identifier in Fleet Management)
-	// If not specified, uses metadata.name
+	// If not specified, the name is generated according to namingStrategy
 	// +optional
 	Name string `json:"name,omitempty"`
 
-	// Contents of the pipeline configuration (Alloy or OpenTelemetry Collector config)
-	// +required
+	// NamingStrategy determines how the pipeline name is generated when name is not set
+	// (MetadataName, NamespacedName or Template). Names generated by NamespacedName and
+	// Template are sanitized into valid Alloy identifiers, metadata.name is used as is.
+	// Defaults to the operator's --default-naming-strategy.
+	// +optional
+	NamingStrategy NamingStrategy `json:"namingStrategy,omitempty"`
+
+	// NameTemplate is the Go template used by the Template naming strategy.
+	// Available fields: .Name, .Namespace, .Cluster and .Labels
+	// Defaults to the operator's --default-name-template.
+	// +optional
+	NameTemplate string `json:"nameTemplate,omitempty"`
+
+	// Contents of the pipeline configuration (Alloy or OpenTelemetry Collector config).
+	// At most one of contents or templateRef may be set; one of them is required
+	// unless fragmentSelector is set.
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	Contents string `json:"contents"`
+	Contents string `json:"contents,omitempty"`
+
+	// TemplateRef renders the contents from a PipelineTemplate in the same namespace
+	// +optional
+	TemplateRef *TemplateReference `json:"templateRef,omitempty"`
+
+	// Parameters passed to the template referenced by templateRef
+	// +optional
+	Parameters map[string]apiextensionsv1.JSON `json:"parameters,omitempty"`
+
+	// Modules are PipelineModules in the same namespace whose declarations are
+	// prepended to the contents, together with their dependencies. Alloy only.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=32
+	Modules []ModuleReference `json:"modules,omitempty"`
+
+	// FragmentSelector selects PipelineFragments in the same namespace whose
+	// contents are merged with the pipeline's own contents. Fragments are merged
+	// by ascending order, then name, before the pipeline's contents.
+	// +optional
+	FragmentSelector *metav1.LabelSelector `json:"fragmentSelector,omitempty"`
+
+	// SecretRefs lists the Secrets in the same namespace whose keys the contents
+	// may reference as ${secret:<name>/<key>}. References are only substituted
+	// when this list is set; $${secret:...} produces the reference literally.
+	// Substituted values are never written to status, events or logs.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	SecretRefs []SecretReference `json:"secretRefs,omitempty"`
 
 	// Matchers to assign pipeline to collectors
 	// Prometheus Alertmanager syntax: key=value, key!=value, key=~regex, key!~regex
@@ -156,28 +370,160 @@ type PipelineSpec struct {
 	// +kubebuilder:validation:MaxItems=100
 	Matchers []string `json:"matchers,omitempty"`
 
+	// StructuredMatchers are matchers in {key, op, value} form.
+	// They are combined with matchers; together they may not exceed 100 entries.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	StructuredMatchers []Matcher `json:"structuredMatchers,omitempty"`
+
 	// Enabled indicates whether the pipeline is enabled for collectors
 	// +optional
 	// +kubebuilder:default=true
 	Enabled bool `json:"enabled"`
 
+	// Schedule enables the pipeline only during recurring windows. It has no
+	// effect when enabled is false.
+	// +optional
+	Schedule *PipelineSchedule `json:"schedule,omitempty"`
+
+	// ExpiresAt disables the pipeline from this time on
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Debug turns the Pipeline into a temporary debug pipeline for a single
+	// collector. Its remote name is made unique and it is deleted after the TTL.
+	// It can only be set when the Pipeline is created.
+	// +optional
+	Debug *DebugTarget `json:"debug,omitempty"`
+
+	// Rollout sends contents changes to a canary copy of the pipeline before
+	// promoting them to every matched collector
+	// +optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
 	// ConfigType specifies the type of configuration (Alloy or OpenTelemetryCollector)
 	// +optional
 	// +kubebuilder:default=Alloy
 	ConfigType ConfigType `json:"configType,omitempty"`
 
+	// Format lays out the rendered contents canonically before they are synced,
+	// so that whitespace-only edits do not create new revisions. Alloy contents
+	// are formatted like alloy fmt, OpenTelemetry Collector YAML is re-emitted
+	// in block style with two-space indentation.
+	// +optional
+	Format bool `json:"format,omitempty"`
+
 	// Source specifies the origin of the pipeline (Git, Terraform, Kubernetes, etc.)
 	// Used for tracking and grouping pipelines by their source
 	// +optional
 	Source *PipelineSource `json:"source,omitempty"`
 }
 
+// MatchedCollectors summarizes the collectors a pipeline's matchers currently select
+type MatchedCollectors struct {
+	// Count is the number of active collectors matching the pipeline
+	Count int32 `json:"count"`
+
+	// IDs of the matching collectors, truncated when there are many
+	// +optional
+	IDs []string `json:"ids,omitempty"`
+
+	// Truncated is true when IDs does not list every matching collector
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+
+	// LastRefreshTime is when the collector list was last evaluated
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+}
+
+// ObservedResource identifies the version of a resource the contents were rendered from
+type ObservedResource struct {
+	// Name of the resource
+	Name string `json:"name"`
+
+	// UID of the resource
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+
+	// Generation of the resource
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// ResourceVersion of the resource, for resources without a generation
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// ChangedBy is the user who last changed the spec of the resource, as
+	// recorded by the admission webhook
+	// +optional
+	ChangedBy string `json:"changedBy,omitempty"`
+}
+
+// PendingApproval is a change waiting for approval
+type PendingApproval struct {
+	// Hash identifies the change to approve with the approve annotation
+	Hash string `json:"hash"`
+
+	// Generation of the spec to approve
+	Generation int64 `json:"generation"`
+
+	// Authors are the users who last changed the spec of the Pipeline or of
+	// the template, fragments and modules it is rendered from. None of them
+	// can approve the change.
+	// +optional
+	Authors []string `json:"authors,omitempty"`
+
+	// Diff of the spec against the last applied one
+	// +optional
+	Diff string `json:"diff,omitempty"`
+}
+
+// ApprovalStatus tracks the changes of a Pipeline requiring approval
+type ApprovalStatus struct {
+	// AppliedHash identifies the last change synced to Fleet Management. The
+	// change itself is kept in a ConfigMap to diff the next change against.
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+
+	// Pending is the change waiting for approval
+	// +optional
+	Pending *PendingApproval `json:"pending,omitempty"`
+}
+
+// RolloutStatus tracks a canary rollout
+type RolloutStatus struct {
+	// StableHash identifies the contents of the main pipeline
+	// +optional
+	StableHash string `json:"stableHash,omitempty"`
+
+	// CanaryHash identifies the contents being tried on the canary collectors
+	// +optional
+	CanaryHash string `json:"canaryHash,omitempty"`
+
+	// CanaryID is the Fleet Management ID of the canary pipeline
+	// +optional
+	CanaryID string `json:"canaryID,omitempty"`
+
+	// CanaryName is the name of the canary pipeline in Fleet Management
+	// +optional
+	CanaryName string `json:"canaryName,omitempty"`
+
+	// CanaryStartTime is when the canary was created
+	// +optional
+	CanaryStartTime *metav1.Time `json:"canaryStartTime,omitempty"`
+}
+
 // PipelineStatus defines the observed state of Pipeline.
 type PipelineStatus struct {
 	// ID is the server-assigned pipeline ID from Fleet Management
 	// +optional
 	ID string `json:"id,omitempty"`
 
+	// RemoteName is the pipeline name used in Fleet Management
+	// +optional
+	RemoteName string `json:"remoteName,omitempty"`
+
 	// ObservedGeneration reflects the generation of the most recently observed Pipeline spec
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -190,15 +536,79 @@ type PipelineStatus struct {
 	// +optional
 	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
 
-	// RevisionID is the current revision ID from Fleet Management
+	// RevisionID identifies the revision of the pipeline in Fleet Management
+	// after the last sync, by the time it was last updated
 	// +optional
 	RevisionID string `json:"revisionId,omitempty"`
 
+	// AppliedHash identifies the last request synced to Fleet Management,
+	// without Secret values. Syncs that would send the same request again
+	// are skipped while the remote pipeline is still at RevisionID.
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+
+	// RevisionCheckTime is when the remote pipeline was last found at
+	// RevisionID. It is checked again once the drift check interval passed.
+	// +optional
+	RevisionCheckTime *metav1.Time `json:"revisionCheckTime,omitempty"`
+
+	// SourceNamespace is the source namespace the pipeline was last synced
+	// with. While it is unchanged, the remote pipeline is known to belong to
+	// this Pipeline and its ownership is not checked again.
+	// +optional
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+
+	// ContentsBytes is the size of the contents last rendered for the pipeline
+	// from its template, fragments and modules, before Secret values are
+	// substituted. PipelineQuotas count it.
+	// +optional
+	ContentsBytes int64 `json:"contentsBytes,omitempty"`
+
+	// MatchedCollectors lists the collectors currently selected by the pipeline's matchers
+	// +optional
+	MatchedCollectors *MatchedCollectors `json:"matchedCollectors,omitempty"`
+
+	// Template records the PipelineTemplate the contents were last rendered from
+	// +optional
+	Template *ObservedResource `json:"template,omitempty"`
+
+	// Modules lists the PipelineModules prepended to the contents, in the order they were included
+	// +optional
+	Modules []ObservedResource `json:"modules,omitempty"`
+
+	// Fragments lists the PipelineFragments merged into the contents, in the order they were merged
+	// +optional
+	Fragments []ObservedResource `json:"fragments,omitempty"`
+
+	// Secrets lists the Secrets whose values were substituted into the contents.
+	// Only their versions are recorded, never their values.
+	// +optional
+	Secrets []ObservedResource `json:"secrets,omitempty"`
+
+	// NextTransitionTime is when the schedule or expiresAt next enables or
+	// disables the pipeline
+	// +optional
+	NextTransitionTime *metav1.Time `json:"nextTransitionTime,omitempty"`
+
+	// KillSwitch is the PipelineKillSwitch forcing the remote pipeline to be
+	// disabled, regardless of spec.enabled
+	// +optional
+	KillSwitch string `json:"killSwitch,omitempty"`
+
+	// Rollout tracks the canary of a contents change
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Approval tracks changes when they require approval
+	// +optional
+	Approval *ApprovalStatus `json:"approval,omitempty"`
+
 	// Conditions represent the current state of the Pipeline resource.
 	//
 	// Standard condition types:
 	// - "Ready": Pipeline is successfully synced to Fleet Management
 	// - "Synced": Last reconciliation succeeded
+	// - "NoMatchingCollectors": True when the matchers select no collectors
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -210,9 +620,11 @@ type PipelineStatus struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=fmp
+// +kubebuilder:printcolumn:name="Remote Name",type="string",JSONPath=".status.remoteName"
 // +kubebuilder:printcolumn:name="Enabled",type="boolean",JSONPath=".spec.enabled"
 // +kubebuilder:printcolumn:name="Config Type",type="string",JSONPath=".spec.configType"
 // +kubebuilder:printcolumn:name="Fleet ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Collectors",type="integer",JSONPath=".status.matchedCollectors.count"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineFragmentSpec defines the desired state of PipelineFragment
+type PipelineFragmentSpec struct {
+	// Contents is the part of the pipeline configuration owned by this fragment.
+	// It must use the configType of the Pipelines selecting it.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Contents string `json:"contents"`
+
+	// Order positions the fragment in the merged contents. Fragments are merged
+	// by ascending order, then name.
+	// +optional
+	Order int32 `json:"order,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fmpf
+// +kubebuilder:printcolumn:name="Order",type="integer",JSONPath=".spec.order"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelineFragment is the Schema for the pipelinefragments API.
+// It holds part of the contents of the Pipelines whose fragmentSelector matches its labels.
+type PipelineFragment struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of PipelineFragment
+	// +required
+	Spec PipelineFragmentSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineFragmentList contains a list of PipelineFragment
+type PipelineFragmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelineFragment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineFragment{}, &PipelineFragmentList{})
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineKillSwitchSpec defines the desired state of PipelineKillSwitch
+// +kubebuilder:validation:XValidation:rule="has(self.selector) || has(self.remoteNamePattern)",message="one of selector or remoteNamePattern must be set"
+type PipelineKillSwitchSpec struct {
+	// Selector selects Pipelines in any namespace by their labels
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// RemoteNamePattern selects Pipelines whose remote pipeline name fully
+	// matches this regular expression (RE2 syntax). When selector is also set,
+	// Pipelines must match both.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	RemoteNamePattern string `json:"remoteNamePattern,omitempty"`
+
+	// Reason is shown in the status of the disabled Pipelines
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=fmks
+// +kubebuilder:printcolumn:name="Pattern",type="string",JSONPath=".spec.remoteNamePattern"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelineKillSwitch is the Schema for the pipelinekillswitches API.
+// While it exists, every Pipeline it selects is synced with enabled set to false.
+type PipelineKillSwitch struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of PipelineKillSwitch
+	// +required
+	Spec PipelineKillSwitchSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineKillSwitchList contains a list of PipelineKillSwitch
+type PipelineKillSwitchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelineKillSwitch `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineKillSwitch{}, &PipelineKillSwitchList{})
+}
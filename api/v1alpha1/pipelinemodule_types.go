@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineModuleSpec defines the desired state of PipelineModule
+type PipelineModuleSpec struct {
+	// Contents holds Alloy declare blocks defining custom components
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Contents string `json:"contents"`
+
+	// DependsOn lists PipelineModules in the same namespace whose declarations
+	// this module uses. They are included before this module.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=32
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fmpm
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelineModule is the Schema for the pipelinemodules API.
+// It holds Alloy declarations shared by several Pipelines.
+type PipelineModule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of PipelineModule
+	// +required
+	Spec PipelineModuleSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineModuleList contains a list of PipelineModule
+type PipelineModuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelineModule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineModule{}, &PipelineModuleList{})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelinePolicySpec defines the restrictions a policy places on Pipelines
+type PipelinePolicySpec struct {
+	// PipelineSelector restricts the policy to Pipelines with matching labels.
+	// Empty applies it to every Pipeline in scope.
+	// +optional
+	PipelineSelector *metav1.LabelSelector `json:"pipelineSelector,omitempty"`
+
+	// RequiredMatchers must all be among the matchers of a Pipeline, e.g.
+	// "team=${namespace}". ${namespace} is replaced by the Pipeline namespace.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	RequiredMatchers []string `json:"requiredMatchers,omitempty"`
+
+	// ForbiddenMatcherKeys are attributes that Pipelines may not match on,
+	// other than through the required matchers, e.g. "team" or "collector.ID"
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	ForbiddenMatcherKeys []string `json:"forbiddenMatcherKeys,omitempty"`
+
+	// AllowedConfigTypes restricts spec.configType. Empty allows every type.
+	// +optional
+	AllowedConfigTypes []ConfigType `json:"allowedConfigTypes,omitempty"`
+
+	// AllowedSourceTypes restricts spec.source.type. Empty allows every type.
+	// +optional
+	AllowedSourceTypes []SourceType `json:"allowedSourceTypes,omitempty"`
+
+	// MaxMatchers caps the number of matchers of a Pipeline
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxMatchers *int32 `json:"maxMatchers,omitempty"`
+
+	// AlloyComponents restricts the components of Alloy contents by name,
+	// e.g. "local.file" or "remote.*". Components declared in declare blocks
+	// are checked too.
+	// +optional
+	AlloyComponents *ComponentLists `json:"alloyComponents,omitempty"`
+
+	// OTelReceivers restricts the receiver types of OpenTelemetry Collector
+	// contents, e.g. "otlp" or "filelog"
+	// +optional
+	OTelReceivers *ComponentLists `json:"otelReceivers,omitempty"`
+
+	// OTelProcessors restricts the processor types of OpenTelemetry Collector contents
+	// +optional
+	OTelProcessors *ComponentLists `json:"otelProcessors,omitempty"`
+
+	// OTelExporters restricts the exporter types of OpenTelemetry Collector contents
+	// +optional
+	OTelExporters *ComponentLists `json:"otelExporters,omitempty"`
+}
+
+// ComponentLists allows or denies components by name. Names may use shell
+// glob patterns, e.g. "discovery.*". A component must match the allow list,
+// when set, and must not match the deny list.
+type ComponentLists struct {
+	// Allowed lists the only components that may be used. Empty allows all.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Allowed []string `json:"allowed,omitempty"`
+
+	// Denied lists components that may not be used
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Denied []string `json:"denied,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fmpp
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelinePolicy is the Schema for the pipelinepolicies API.
+// It restricts the matchers, config type and source of Pipelines in its namespace.
+type PipelinePolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the restrictions of the PipelinePolicy
+	// +required
+	Spec PipelinePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelinePolicyList contains a list of PipelinePolicy
+type PipelinePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelinePolicy `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=fmcpp
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterPipelinePolicy is the Schema for the clusterpipelinepolicies API.
+// It restricts Pipelines in every namespace, like a PipelinePolicy.
+type ClusterPipelinePolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the restrictions of the ClusterPipelinePolicy
+	// +required
+	Spec PipelinePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterPipelinePolicyList contains a list of ClusterPipelinePolicy
+type ClusterPipelinePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterPipelinePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelinePolicy{}, &PipelinePolicyList{},
+		&ClusterPipelinePolicy{}, &ClusterPipelinePolicyList{})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineQuotaLimits are limits on the Pipelines of a namespace. Unset
+// limits are not enforced.
+type PipelineQuotaLimits struct {
+	// Pipelines is the number of Pipelines
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Pipelines *int64 `json:"pipelines,omitempty"`
+
+	// EnabledPipelines is the number of Pipelines with spec.enabled set
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	EnabledPipelines *int64 `json:"enabledPipelines,omitempty"`
+
+	// ContentsBytes is the total size of the rendered contents, e.g. "1Mi"
+	// +optional
+	ContentsBytes *resource.Quantity `json:"contentsBytes,omitempty"`
+}
+
+// PipelineQuotaUsage is the usage of the Pipelines of a namespace
+type PipelineQuotaUsage struct {
+	// Pipelines is the number of Pipelines
+	Pipelines int64 `json:"pipelines"`
+
+	// EnabledPipelines is the number of Pipelines with spec.enabled set
+	EnabledPipelines int64 `json:"enabledPipelines"`
+
+	// ContentsBytes is the total size of the rendered contents
+	ContentsBytes resource.Quantity `json:"contentsBytes"`
+}
+
+// PipelineQuotaSpec defines the limits of a PipelineQuota
+type PipelineQuotaSpec struct {
+	// Hard are the limits enforced when Pipelines are created or updated
+	// +required
+	Hard PipelineQuotaLimits `json:"hard"`
+}
+
+// PipelineQuotaStatus defines the observed usage of a PipelineQuota
+type PipelineQuotaStatus struct {
+	// Hard are the enforced limits
+	// +optional
+	Hard PipelineQuotaLimits `json:"hard,omitzero"`
+
+	// Used is the current usage of the namespace
+	// +optional
+	Used *PipelineQuotaUsage `json:"used,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=fmpq
+// +kubebuilder:printcolumn:name="Pipelines",type="integer",JSONPath=".status.used.pipelines"
+// +kubebuilder:printcolumn:name="Enabled",type="integer",JSONPath=".status.used.enabledPipelines"
+// +kubebuilder:printcolumn:name="Bytes",type="string",JSONPath=".status.used.contentsBytes"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelineQuota is the Schema for the pipelinequotas API.
+// It limits the number and size of the Pipelines in its namespace.
+type PipelineQuota struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the limits of the PipelineQuota
+	// +required
+	Spec PipelineQuotaSpec `json:"spec"`
+
+	// status defines the observed usage of the PipelineQuota
+	// +optional
+	Status PipelineQuotaStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineQuotaList contains a list of PipelineQuota
+type PipelineQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelineQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineQuota{}, &PipelineQuotaList{})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParameterType is the type of a template parameter
+// +kubebuilder:validation:Enum=String;Integer;Boolean;StringList;StringMap
+type ParameterType string
+
+const (
+	// ParameterTypeString is a string value
+	ParameterTypeString ParameterType = "String"
+
+	// ParameterTypeInteger is a whole number
+	ParameterTypeInteger ParameterType = "Integer"
+
+	// ParameterTypeBoolean is true or false
+	ParameterTypeBoolean ParameterType = "Boolean"
+
+	// ParameterTypeStringList is a list of strings
+	ParameterTypeStringList ParameterType = "StringList"
+
+	// ParameterTypeStringMap is a map of string keys to string values
+	ParameterTypeStringMap ParameterType = "StringMap"
+)
+
+// TemplateParameter declares a parameter accepted by a PipelineTemplate
+type TemplateParameter struct {
+	// Name of the parameter, referenced in contents as {{ .Params.<name> }}
+	// +required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Type of the parameter value
+	// +optional
+	// +kubebuilder:default=String
+	Type ParameterType `json:"type,omitempty"`
+
+	// Description of the parameter
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Required parameters must be set by every Pipeline using the template
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Default value used when a Pipeline does not set the parameter
+	// +optional
+	Default *apiextensionsv1.JSON `json:"default,omitempty"`
+}
+
+// PipelineTemplateSpec defines the desired state of PipelineTemplate
+type PipelineTemplateSpec struct {
+	// Contents is a Go template producing the pipeline configuration.
+	// Parameters are available as {{ .Params.<name> }}, and the Pipeline as
+	// {{ .Pipeline.Name }}, {{ .Pipeline.Namespace }} and {{ .Pipeline.Labels }}.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Contents string `json:"contents"`
+
+	// Parameters accepted by the template
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=64
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fmpt
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelineTemplate is the Schema for the pipelinetemplates API.
+// It holds parameterized pipeline contents shared by several Pipelines.
+type PipelineTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of PipelineTemplate
+	// +required
+	Spec PipelineTemplateSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineTemplateList contains a list of PipelineTemplate
+type PipelineTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelineTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineTemplate{}, &PipelineTemplateList{})
+}
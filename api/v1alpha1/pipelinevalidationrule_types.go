@@ -0,0 +1,98 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidationAction decides what happens to a Pipeline failing a rule
+// +kubebuilder:validation:Enum=Deny;Warn
+type ValidationAction string
+
+const (
+	// ValidationActionDeny rejects Pipelines failing the rule
+	ValidationActionDeny ValidationAction = "Deny"
+	// ValidationActionWarn admits Pipelines failing the rule with a warning
+	ValidationActionWarn ValidationAction = "Warn"
+)
+
+// PipelineValidationRuleSpec defines a CEL rule Pipelines must satisfy
+type PipelineValidationRuleSpec struct {
+	// Expression is a CEL expression that must evaluate to true for a Pipeline
+	// to pass. It can use object, the Pipeline, oldObject, the Pipeline before
+	// an update or null, and contents, its spec.contents, along with the
+	// helper functions lines, alloyBlocks, alloyComponents and otelComponents.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=4096
+	Expression string `json:"expression"`
+
+	// Message is returned when a Pipeline fails the rule. Defaults to the
+	// rule name and expression.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message,omitempty"`
+
+	// Action decides whether failing Pipelines are denied or only warned about
+	// +optional
+	// +kubebuilder:default=Deny
+	Action ValidationAction `json:"action,omitempty"`
+
+	// PipelineSelector restricts the rule to Pipelines with matching labels.
+	// Empty applies it to every Pipeline.
+	// +optional
+	PipelineSelector *metav1.LabelSelector `json:"pipelineSelector,omitempty"`
+
+	// Namespaces restricts the rule to Pipelines in these namespaces.
+	// Empty applies it to every namespace.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=fmvr
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action"
+// +kubebuilder:printcolumn:name="Expression",type="string",JSONPath=".spec.expression",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PipelineValidationRule is the Schema for the pipelinevalidationrules API.
+// The admission webhook evaluates its CEL expression against Pipelines.
+type PipelineValidationRule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the rule
+	// +required
+	Spec PipelineValidationRuleSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineValidationRuleList contains a list of PipelineValidationRule
+type PipelineValidationRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PipelineValidationRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineValidationRule{}, &PipelineValidationRuleList{})
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	yaml "go.yaml.in/yaml/v3"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/alloy"
+	"github.com/grafana/fleet-management-operator/pkg/otelcol"
+)
+
+type fmtOptions struct {
+	filenames []string
+	write     bool
+	check     bool
+}
+
+func newFmtCommand() *cobra.Command {
+	o := &fmtOptions{}
+	cmd := &cobra.Command{
+		Use:   "fmt -f pipeline.yaml",
+		Short: "Format the contents of Pipeline manifests",
+		Long: `Format spec.contents of every Pipeline in the given manifests with the formatter
+the operator applies to Pipelines with spec.format: Alloy contents are formatted
+like alloy fmt, OpenTelemetry Collector YAML is re-emitted in block style with
+two-space indentation.
+
+The formatted manifests are printed unless --write or --check is set. Other
+documents and fields are kept, re-indented with two spaces.`,
+		Example: `  # Format manifests in place
+  fmctl fmt -w -f metrics.yaml,logs.yaml
+
+  # Fail CI when a manifest is not formatted
+  fmctl fmt --check -f pipeline.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.run(cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&o.filenames, "filename", "f", nil, "Manifests to format, or - for stdin")
+	flags.BoolVarP(&o.write, "write", "w", false, "Write the formatted manifests back to their files")
+	flags.BoolVar(&o.check, "check", false, "Print the manifests that are not formatted and exit with status 1 if any")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+func (o *fmtOptions) run(out, errOut io.Writer) error {
+	changed := false
+	for _, path := range o.filenames {
+		if path == "-" && o.write {
+			return fmt.Errorf("--write cannot be used with stdin")
+		}
+
+		data, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := formatManifests(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		switch {
+		case o.check:
+			if !bytes.Equal(data, formatted) {
+				changed = true
+				_, _ = fmt.Fprintln(errOut, path)
+			}
+		case o.write:
+			if !bytes.Equal(data, formatted) {
+				if err := os.WriteFile(path, formatted, 0o644); err != nil {
+					return err
+				}
+			}
+		default:
+			if _, err := out.Write(formatted); err != nil {
+				return err
+			}
+		}
+	}
+
+	if changed {
+		return errChanged
+	}
+	return nil
+}
+
+// readFile reads path, or stdin when path is "-"
+func readFile(path string) ([]byte, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(f)
+}
+
+// formatManifests formats the contents of the Pipelines in a stream of YAML documents
+func formatManifests(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if err := formatPipeline(&doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// formatPipeline formats spec.contents of a Pipeline document. Other documents are left alone.
+func formatPipeline(doc *yaml.Node) error {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if kind := mappingValue(root, "kind"); kind == nil || kind.Value != "Pipeline" {
+		return nil
+	}
+	spec := mappingValue(root, "spec")
+	contents := mappingValue(spec, "contents")
+	if contents == nil || contents.Kind != yaml.ScalarNode || contents.Value == "" {
+		return nil
+	}
+
+	var formatted string
+	var err error
+	if configType := mappingValue(spec, "configType"); configType != nil &&
+		configType.Value == string(fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector) {
+		formatted, err = otelcol.Format(contents.Value)
+	} else {
+		formatted, err = alloy.Format(contents.Value)
+	}
+	if err != nil {
+		if name := mappingValue(mappingValue(root, "metadata"), "name"); name != nil {
+			return fmt.Errorf("pipeline %s: failed to format contents: %w", name.Value, err)
+		}
+		return fmt.Errorf("failed to format contents: %w", err)
+	}
+
+	contents.Value = formatted
+	contents.Style = yaml.LiteralStyle
+	return nil
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const unformattedManifests = `apiVersion: fleetmanagement.grafana.com/v1alpha1
+kind: Pipeline
+metadata:
+    name: metrics
+spec:
+    contents: "logging {\nlevel=\"info\"\n}"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+    name: settings
+data:
+    key: value
+---
+apiVersion: fleetmanagement.grafana.com/v1alpha1
+kind: Pipeline
+metadata:
+    name: traces
+spec:
+    configType: OpenTelemetryCollector
+    contents: "receivers: {otlp: {protocols: {grpc: {}}}}"
+`
+
+const formattedManifests = `apiVersion: fleetmanagement.grafana.com/v1alpha1
+kind: Pipeline
+metadata:
+  name: metrics
+spec:
+  contents: |
+    logging {
+    ` + "\t" + `level = "info"
+    }
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  key: value
+---
+apiVersion: fleetmanagement.grafana.com/v1alpha1
+kind: Pipeline
+metadata:
+  name: traces
+spec:
+  configType: OpenTelemetryCollector
+  contents: |
+    receivers:
+      otlp:
+        protocols:
+          grpc: {}
+`
+
+func TestFmtPrints(t *testing.T) {
+	path := writeFile(t, "pipeline.yaml", unformattedManifests)
+
+	code, out, errOut := runFmctl("fmt", "-f", path)
+	if code != 0 {
+		t.Fatalf("exit status = %d, want 0 (stderr %q)", code, errOut)
+	}
+	if out != formattedManifests {
+		t.Errorf("output = %q, want %q", out, formattedManifests)
+	}
+	if got := readTestFile(t, path); got != unformattedManifests {
+		t.Errorf("file = %q, want it unchanged", got)
+	}
+}
+
+func TestFmtWrite(t *testing.T) {
+	unformatted := writeFile(t, "pipeline.yaml", unformattedManifests)
+	formatted := writeFile(t, "formatted.yaml", formattedManifests)
+
+	for _, flag := range []string{"-w", "--write"} {
+		t.Run(flag, func(t *testing.T) {
+			if err := os.WriteFile(unformatted, []byte(unformattedManifests), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			code, out, errOut := runFmctl("fmt", flag, "-f", unformatted+","+formatted)
+			if code != 0 {
+				t.Fatalf("exit status = %d, want 0 (stderr %q)", code, errOut)
+			}
+			if out != "" || errOut != "" {
+				t.Errorf("output = %q, stderr = %q, want none", out, errOut)
+			}
+			for _, path := range []string{unformatted, formatted} {
+				if got := readTestFile(t, path); got != formattedManifests {
+					t.Errorf("%s = %q, want %q", path, got, formattedManifests)
+				}
+			}
+		})
+	}
+}
+
+func TestFmtCheck(t *testing.T) {
+	unformatted := writeFile(t, "pipeline.yaml", unformattedManifests)
+	formatted := writeFile(t, "formatted.yaml", formattedManifests)
+
+	tests := []struct {
+		name       string
+		filenames  []string
+		wantCode   int
+		wantErrOut string
+	}{
+		{"formatted", []string{formatted}, 0, ""},
+		{"unformatted", []string{unformatted}, 1, unformatted + "\n"},
+		{"some unformatted", []string{formatted, unformatted}, 1, unformatted + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out, errOut := runFmctl("fmt", "--check", "-f", strings.Join(tt.filenames, ","))
+			if code != tt.wantCode {
+				t.Errorf("exit status = %d, want %d", code, tt.wantCode)
+			}
+			if out != "" {
+				t.Errorf("output = %q, want none", out)
+			}
+			if errOut != tt.wantErrOut {
+				t.Errorf("stderr = %q, want %q", errOut, tt.wantErrOut)
+			}
+			if got := readTestFile(t, unformatted); got != unformattedManifests {
+				t.Errorf("file = %q, want it unchanged", got)
+			}
+		})
+	}
+}
+
+func TestFmtErrors(t *testing.T) {
+	path := writeFile(t, "pipeline.yaml", unformattedManifests)
+	invalid := writeFile(t, "invalid.yaml", "kind: Pipeline\nmetadata:\n  name: broken\nspec:\n  contents: \"logging {\"\n")
+	notYAML := writeFile(t, "not.yaml", "kind: [Pipeline\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing filename", []string{"fmt"}, `required flag(s) "filename" not set`},
+		{"unknown flag", []string{"fmt", "-f", path, "--diff"}, "unknown flag: --diff"},
+		{"unexpected argument", []string{"fmt", "-f", path, "extra"}, `unknown command "extra"`},
+		{"write to stdin", []string{"fmt", "-w", "-f", "-"}, "--write cannot be used with stdin"},
+		{"missing file", []string{"fmt", "-f", path + ".missing"}, "no such file"},
+		{"invalid YAML", []string{"fmt", "-f", notYAML}, "invalid YAML"},
+		{"invalid contents", []string{"fmt", "-w", "-f", invalid}, "pipeline broken: failed to format contents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, errOut := runFmctl(tt.args...)
+			if code != 1 {
+				t.Errorf("exit status = %d, want 1", code)
+			}
+			if !strings.HasPrefix(errOut, "Error: ") || !strings.Contains(errOut, tt.wantErr) {
+				t.Errorf("stderr = %q, want an error containing %q", errOut, tt.wantErr)
+			}
+		})
+	}
+
+	if got := readTestFile(t, invalid); !strings.Contains(got, `"logging {"`) {
+		t.Errorf("invalid file = %q, want it unchanged", got)
+	}
+}
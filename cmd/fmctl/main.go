@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// fmctl is a command line tool for working with Pipeline resources outside the cluster
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+)
+
+// errChanged makes fmctl exit with status 1 without printing an error
+var errChanged = errors.New("changes found")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes fmctl with args and returns its exit status
+func run(args []string, out, errOut io.Writer) int {
+	root := &cobra.Command{
+		Use:           "fmctl",
+		Short:         "Work with Fleet Management Pipeline resources",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(newFmtCommand())
+	root.AddCommand(newSimulateCommand())
+	root.SetArgs(args)
+	root.SetOut(out)
+	root.SetErr(errOut)
+
+	if err := root.Execute(); err != nil {
+		if !errors.Is(err, errChanged) {
+			_, _ = fmt.Fprintln(errOut, "Error:", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+// openFile opens path for reading, or stdin when path is "-"
+func openFile(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// loadPipeline reads a Pipeline manifest in YAML or JSON
+func loadPipeline(path string) (*fleetmanagementv1alpha1.Pipeline, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	pipeline := &fleetmanagementv1alpha1.Pipeline{}
+	if err := yaml.Unmarshal(data, pipeline); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if pipeline.Kind != "" && pipeline.Kind != "Pipeline" {
+		return nil, fmt.Errorf("%s: expected kind Pipeline, got %s", path, pipeline.Kind)
+	}
+	return pipeline, nil
+}
+
+// newFleetClient creates a Fleet Management client from the same environment
+// variables the operator uses
+func newFleetClient() (*fleetclient.Client, error) {
+	baseURL := os.Getenv("FLEET_MANAGEMENT_BASE_URL")
+	username := os.Getenv("FLEET_MANAGEMENT_USERNAME")
+	password := os.Getenv("FLEET_MANAGEMENT_PASSWORD")
+	if baseURL == "" || username == "" || password == "" {
+		return nil, fmt.Errorf("FLEET_MANAGEMENT_BASE_URL, FLEET_MANAGEMENT_USERNAME and FLEET_MANAGEMENT_PASSWORD are required to contact Fleet Management")
+	}
+	return fleetclient.NewClient(baseURL, username, password), nil
+}
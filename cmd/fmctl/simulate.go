@@ -0,0 +1,248 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/naming"
+	"github.com/grafana/fleet-management-operator/pkg/simulate"
+)
+
+type simulateOptions struct {
+	filename       string
+	collectorsFile string
+	previousFile   string
+	remoteName     string
+	clusterName    string
+	nameAffix      string
+	namingStrategy string
+	nameTemplate   string
+	showUnchanged  bool
+	exitCode       bool
+}
+
+func newSimulateCommand() *cobra.Command {
+	o := &simulateOptions{}
+	cmd := &cobra.Command{
+		Use:   "simulate -f pipeline.yaml",
+		Short: "Preview which collectors would gain or lose a pipeline",
+		Long: `Evaluate the matchers of a Pipeline manifest against the collectors registered in
+Fleet Management and print the collectors that would gain or lose the pipeline
+compared with the pipeline currently in Fleet Management.
+
+Collectors are listed from Fleet Management unless --collectors points at a local
+file. The current state is the remote pipeline unless --previous points at the
+previous version of the manifest, in which case Fleet Management is not needed
+when --collectors is also set.
+
+Fleet Management credentials are read from FLEET_MANAGEMENT_BASE_URL,
+FLEET_MANAGEMENT_USERNAME and FLEET_MANAGEMENT_PASSWORD.`,
+		Example: `  # Preview a change against the live fleet
+  fmctl simulate -f pipeline.yaml
+
+  # Review a change offline
+  git show HEAD:pipeline.yaml > /tmp/previous.yaml
+  fmctl simulate -f pipeline.yaml --previous /tmp/previous.yaml --collectors collectors.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&o.filename, "filename", "f", "", "Pipeline manifest to simulate, or - for stdin")
+	flags.StringVar(&o.collectorsFile, "collectors", "",
+		"YAML or JSON file listing collectors and their attributes instead of listing them from Fleet Management")
+	flags.StringVar(&o.previousFile, "previous", "",
+		"Previous Pipeline manifest to compare with instead of the pipeline in Fleet Management")
+	flags.StringVar(&o.remoteName, "remote-name", "",
+		"Name of the pipeline in Fleet Management. Defaults to status.remoteName or the name the operator would generate.")
+	flags.StringVar(&o.clusterName, "cluster-name", "", "Cluster name the operator runs with, used to generate the remote name")
+	flags.StringVar(&o.nameAffix, "pipeline-name-affix", "", "Pipeline name affix the operator runs with")
+	flags.StringVar(&o.namingStrategy, "default-naming-strategy", string(fleetmanagementv1alpha1.NamingStrategyMetadataName),
+		"Default naming strategy the operator runs with")
+	flags.StringVar(&o.nameTemplate, "default-name-template", "", "Default name template the operator runs with")
+	flags.BoolVar(&o.showUnchanged, "show-unchanged", false, "Also print collectors that keep the pipeline")
+	flags.BoolVar(&o.exitCode, "exit-code", false, "Exit with status 1 when any collector would gain or lose the pipeline")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+func (o *simulateOptions) run(ctx context.Context, out io.Writer) error {
+	pipeline, err := loadPipeline(o.filename)
+	if err != nil {
+		return err
+	}
+	desired, err := simulate.FromPipeline(pipeline)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline: %w", err)
+	}
+
+	var client *fleetclient.Client
+	if o.collectorsFile == "" || o.previousFile == "" {
+		if client, err = newFleetClient(); err != nil {
+			return err
+		}
+	}
+
+	remoteName, err := o.resolveRemoteName(pipeline)
+	if err != nil {
+		return err
+	}
+
+	var current *simulate.Selector
+	if o.previousFile != "" {
+		previous, err := loadPipeline(o.previousFile)
+		if err != nil {
+			return err
+		}
+		if current, err = simulate.FromPipeline(previous); err != nil {
+			return fmt.Errorf("invalid previous pipeline: %w", err)
+		}
+	} else {
+		remote, err := getRemotePipeline(ctx, client, remoteName)
+		if err != nil {
+			return err
+		}
+		if current, err = simulate.FromRemote(remote); err != nil {
+			return err
+		}
+	}
+
+	collectors, err := o.loadCollectors(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	result := simulate.Run(current, desired, collectors)
+	if err := o.print(out, remoteName, current == nil, result); err != nil {
+		return err
+	}
+
+	if o.exitCode && result.Changed() {
+		return errChanged
+	}
+	return nil
+}
+
+// resolveRemoteName returns the name of the pipeline in Fleet Management
+func (o *simulateOptions) resolveRemoteName(pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	if o.remoteName != "" {
+		return o.remoteName, nil
+	}
+	if pipeline.Status.RemoteName != "" {
+		return pipeline.Status.RemoteName, nil
+	}
+
+	affix, err := naming.ParseAffix(o.nameAffix)
+	if err != nil {
+		return "", err
+	}
+	strategy, err := naming.ParseStrategy(o.namingStrategy)
+	if err != nil {
+		return "", err
+	}
+	resolver := &naming.Resolver{
+		ClusterName:     o.clusterName,
+		Affix:           affix,
+		DefaultStrategy: strategy,
+		DefaultTemplate: o.nameTemplate,
+	}
+	return resolver.RemoteName(pipeline)
+}
+
+// loadCollectors reads collectors from the local file, or lists them from Fleet Management
+func (o *simulateOptions) loadCollectors(ctx context.Context, client *fleetclient.Client) ([]*fleetclient.Collector, error) {
+	if o.collectorsFile == "" {
+		collectors, err := client.ListCollectors(ctx, &fleetclient.ListCollectorsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collectors: %w", err)
+		}
+		return collectors, nil
+	}
+
+	f, err := openFile(o.collectorsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return simulate.LoadCollectors(f)
+}
+
+// getRemotePipeline returns the pipeline with the given name, or nil if it does not exist
+func getRemotePipeline(ctx context.Context, client *fleetclient.Client, name string) (*fleetclient.Pipeline, error) {
+	id, err := client.GetPipelineID(ctx, name)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up pipeline %q: %w", name, err)
+	}
+
+	remote, err := client.GetPipeline(ctx, id)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline %q: %w", name, err)
+	}
+	return remote, nil
+}
+
+// isNotFound returns true if err is a Fleet Management 404
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*fleetclient.FleetAPIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+func (o *simulateOptions) print(out io.Writer, remoteName string, isNew bool, result *simulate.Result) error {
+	if isNew {
+		fmt.Fprintf(out, "Pipeline %s does not exist yet\n", remoteName)
+	} else {
+		fmt.Fprintf(out, "Pipeline %s\n", remoteName)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, c := range result.Gained {
+		fmt.Fprintf(w, "+\t%s\t%s\n", c.ID, c.Name)
+	}
+	for _, c := range result.Lost {
+		fmt.Fprintf(w, "-\t%s\t%s\n", c.ID, c.Name)
+	}
+	if o.showUnchanged {
+		for _, c := range result.Kept {
+			fmt.Fprintf(w, " \t%s\t%s\n", c.ID, c.Name)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "%d gain, %d lose, %d unchanged, %d inactive skipped\n",
+		len(result.Gained), len(result.Lost), len(result.Kept), result.Inactive)
+	return err
+}
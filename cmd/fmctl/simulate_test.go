@@ -0,0 +1,145 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const simulateCollectors = `- id: eu-1
+  remoteAttributes:
+    region: eu
+- id: us-1
+  remoteAttributes:
+    region: us
+- id: us-2
+  remoteAttributes:
+    region: us
+`
+
+func simulatePipeline(matcher string) string {
+	return `apiVersion: fleetmanagement.grafana.com/v1alpha1
+kind: Pipeline
+metadata:
+  name: metrics
+  namespace: default
+spec:
+  contents: prometheus.exporter.self "alloy" { }
+  enabled: true
+  matchers:
+  - ` + matcher + `
+`
+}
+
+func TestSimulate(t *testing.T) {
+	collectors := writeFile(t, "collectors.yaml", simulateCollectors)
+	previous := writeFile(t, "previous.yaml", simulatePipeline("region=eu"))
+	pipeline := writeFile(t, "pipeline.yaml", simulatePipeline("region=~.+"))
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  []string
+	}{
+		{
+			name:     "changes",
+			args:     []string{"simulate", "-f", pipeline, "--previous", previous, "--collectors", collectors},
+			wantCode: 0,
+			wantOut:  []string{"Pipeline metrics\n", "+  us-1", "+  us-2", "2 gain, 0 lose, 1 unchanged, 0 inactive skipped\n"},
+		},
+		{
+			name:     "exit code on changes",
+			args:     []string{"simulate", "-f", pipeline, "--previous", previous, "--collectors", collectors, "--exit-code"},
+			wantCode: 1,
+			wantOut:  []string{"2 gain, 0 lose"},
+		},
+		{
+			name:     "exit code without changes",
+			args:     []string{"simulate", "-f", previous, "--previous", previous, "--collectors", collectors, "--exit-code"},
+			wantCode: 0,
+			wantOut:  []string{"0 gain, 0 lose, 1 unchanged"},
+		},
+		{
+			name:     "show unchanged",
+			args:     []string{"simulate", "-f", pipeline, "--previous", previous, "--collectors", collectors, "--show-unchanged"},
+			wantCode: 0,
+			wantOut:  []string{"   eu-1"},
+		},
+		{
+			name: "remote name",
+			args: []string{"simulate", "-f", pipeline, "--previous", previous, "--collectors", collectors,
+				"--default-naming-strategy", "NamespacedName", "--pipeline-name-affix", "ClusterPrefix", "--cluster-name", "prod"},
+			wantCode: 0,
+			wantOut:  []string{"Pipeline prod_default_metrics\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out, errOut := runFmctl(tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("exit status = %d, want %d (stderr %q)", code, tt.wantCode, errOut)
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(out, want) {
+					t.Errorf("output = %q, want it to contain %q", out, want)
+				}
+			}
+			if errOut != "" {
+				t.Errorf("stderr = %q, want none", errOut)
+			}
+		})
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	collectors := writeFile(t, "collectors.yaml", simulateCollectors)
+	pipeline := writeFile(t, "pipeline.yaml", simulatePipeline("region=us"))
+	invalid := writeFile(t, "invalid.yaml", simulatePipeline("region"))
+	other := writeFile(t, "other.yaml", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: metrics\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing filename", []string{"simulate"}, `required flag(s) "filename" not set`},
+		{"unknown flag", []string{"simulate", "-f", pipeline, "--collector", collectors}, "unknown flag: --collector"},
+		{"unexpected argument", []string{"simulate", "-f", pipeline, "extra"}, `unknown command "extra"`},
+		{"missing file", []string{"simulate", "-f", pipeline + ".missing", "--previous", pipeline, "--collectors", collectors}, "no such file"},
+		{"wrong kind", []string{"simulate", "-f", other, "--previous", pipeline, "--collectors", collectors}, "expected kind Pipeline, got ConfigMap"},
+		{"invalid matchers", []string{"simulate", "-f", invalid, "--previous", pipeline, "--collectors", collectors}, "invalid pipeline"},
+		{"invalid naming strategy", []string{"simulate", "-f", pipeline, "--previous", pipeline, "--collectors", collectors,
+			"--default-naming-strategy", "Random"}, `"Random"`},
+		{"invalid affix", []string{"simulate", "-f", pipeline, "--previous", pipeline, "--collectors", collectors,
+			"--pipeline-name-affix", "Random"}, `unknown pipeline name affix "Random"`},
+		{"credentials required", []string{"simulate", "-f", pipeline, "--collectors", collectors}, "FLEET_MANAGEMENT_BASE_URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FLEET_MANAGEMENT_BASE_URL", "")
+			code, _, errOut := runFmctl(tt.args...)
+			if code != 1 {
+				t.Errorf("exit status = %d, want 1", code)
+			}
+			if !strings.HasPrefix(errOut, "Error: ") || !strings.Contains(errOut, tt.wantErr) {
+				t.Errorf("stderr = %q, want an error containing %q", errOut, tt.wantErr)
+			}
+		})
+	}
+}
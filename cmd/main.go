@@ -20,24 +20,32 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
 	"github.com/grafana/fleet-management-operator/internal/controller"
+	webhookv1alpha1 "github.com/grafana/fleet-management-operator/internal/webhook/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/approval"
+	"github.com/grafana/fleet-management-operator/pkg/credscan"
 	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/monitors"
+	"github.com/grafana/fleet-management-operator/pkg/naming"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -62,6 +70,24 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var clusterName string
+	var pipelineNameAffix string
+	var defaultNamingStrategy string
+	var defaultNameTemplate string
+	var collectorRefreshInterval time.Duration
+	var driftCheckInterval time.Duration
+	var credentialScan string
+	var pausedSelector string
+	var approvalNamespaces string
+	var approvalSelector string
+	var fairQueueing bool
+	var syncBatchWindow time.Duration
+	var maxConcurrentReconciles int
+	var monitorPipelines bool
+	var monitorSelector string
+	var monitorMatchers []string
+	var monitorPipelineName string
+	var monitorOptions monitors.Options
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -80,6 +106,70 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&clusterName, "cluster-name", "",
+		"Name of the cluster the operator runs in. It is recorded in the source of every pipeline and used to "+
+			"avoid adopting or deleting pipelines managed by other clusters sharing the same Fleet Management stack.")
+	flag.StringVar(&pipelineNameAffix, "pipeline-name-affix", "",
+		"Qualify remote pipeline names with the cluster or namespace. "+
+			"One of ClusterPrefix, ClusterSuffix, NamespacePrefix, NamespaceSuffix. Leave empty to disable.")
+	flag.StringVar(&defaultNamingStrategy, "default-naming-strategy", string(fleetmanagementv1alpha1.NamingStrategyMetadataName),
+		"Naming strategy for Pipelines without spec.name and spec.namingStrategy. "+
+			"One of MetadataName, NamespacedName, Template.")
+	flag.StringVar(&defaultNameTemplate, "default-name-template", "",
+		"Go template used by the Template naming strategy when spec.nameTemplate is empty. "+
+			"Available fields: .Name, .Namespace, .Cluster, .Labels")
+	flag.DurationVar(&collectorRefreshInterval, "collector-refresh-interval", 5*time.Minute,
+		"How often the collectors matched by each pipeline are re-evaluated and written to status. "+
+			"Collectors are listed once per interval for all pipelines. Set to 0 to disable.")
+	flag.DurationVar(&driftCheckInterval, "drift-check-interval", time.Hour,
+		"How often the remote pipeline of each synced Pipeline is fetched to overwrite edits made in Fleet Management. "+
+			"Unchanged Pipelines are not fetched in between. Set to 0 to disable.")
+	flag.StringVar(&credentialScan, "credential-scan", string(credscan.ModeWarn),
+		"What to do with plaintext credentials found in pipeline contents: off, warn or deny. "+
+			"Pipelines can allow rules with the "+credscan.AllowAnnotation+" annotation.")
+	flag.StringVar(&pausedSelector, "paused-selector", "",
+		"Label selector of Pipelines that are not synced to Fleet Management, as if they had the "+
+			fleetmanagementv1alpha1.PausedAnnotation+" annotation. Empty pauses nothing.")
+	flag.StringVar(&approvalNamespaces, "approval-namespaces", "",
+		"Comma-separated namespaces whose Pipeline changes are only synced once approved by another user with the "+
+			fleetmanagementv1alpha1.ApproveAnnotation+" annotation. Requires the webhook.")
+	flag.StringVar(&approvalSelector, "approval-selector", "",
+		"Label selector of Pipelines whose changes require approval, like --approval-namespaces.")
+	flag.BoolVar(&fairQueueing, "fair-queueing", true,
+		"Share the Fleet Management API rate limit fairly between namespaces, serving deletes and Pipeline "+
+			"changes before periodic resyncs. If false, requests are served in arrival order.")
+	flag.DurationVar(&syncBatchWindow, "sync-batch-window", 0,
+		"How long the Pipelines of a namespace without spec.source wait for each other to be synced in one "+
+			"SyncPipelines call. Requires --cluster-name. Set to 0 to sync every Pipeline on its own.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 10,
+		"How many Pipelines are reconciled at once. Fair queueing only takes turns between the namespaces of "+
+			"Pipelines reconciled concurrently, and batches only fill up when their Pipelines are.")
+	flag.BoolVar(&monitorPipelines, "monitor-pipelines", false,
+		"Generate a Pipeline in each namespace scraping the targets of its Prometheus Operator ServiceMonitors "+
+			"and PodMonitors. Requires the monitoring.coreos.com CRDs, --monitor-matcher and --monitor-remote-write-url.")
+	flag.StringVar(&monitorPipelineName, "monitor-pipeline-name", "prometheus-monitors",
+		"Name of the Pipeline generated from the monitors of each namespace.")
+	flag.StringVar(&monitorSelector, "monitor-selector", "",
+		"Label selector of the ServiceMonitors and PodMonitors Pipelines are generated from. Empty selects all.")
+	flag.Func("monitor-matcher", "Matcher of the generated Pipelines, e.g. collector.os=linux. Can be repeated.",
+		func(s string) error {
+			monitorMatchers = append(monitorMatchers, s)
+			return nil
+		})
+	flag.StringVar(&monitorOptions.APIServer, "monitor-api-server", "",
+		"Kubernetes API server the collectors discover targets with. Empty uses their in-cluster configuration.")
+	flag.StringVar(&monitorOptions.KubeconfigFile, "monitor-kubeconfig-file", "",
+		"Kubeconfig file on the collectors to discover targets with, instead of --monitor-api-server.")
+	flag.StringVar(&monitorOptions.BearerTokenFile, "monitor-bearer-token-file", "",
+		"File on the collectors holding the token to authenticate to --monitor-api-server with.")
+	flag.StringVar(&monitorOptions.CAFile, "monitor-ca-file", "",
+		"File on the collectors holding the CA certificate of --monitor-api-server.")
+	flag.StringVar(&monitorOptions.RemoteWriteURL, "monitor-remote-write-url", "",
+		"Remote write endpoint the generated Pipelines send samples to.")
+	flag.StringVar(&monitorOptions.RemoteWriteUsername, "monitor-remote-write-username", "",
+		"Basic auth username of the remote write endpoint of the generated Pipelines.")
+	flag.StringVar(&monitorOptions.RemoteWritePasswordEnv, "monitor-remote-write-password-env", "",
+		"Environment variable of the collectors holding the basic auth password of the remote write endpoint.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -88,6 +178,71 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	nameAffix, err := naming.ParseAffix(pipelineNameAffix)
+	if err != nil {
+		setupLog.Error(err, "invalid --pipeline-name-affix")
+		os.Exit(1)
+	}
+	if clusterName == "" && (nameAffix == naming.AffixClusterPrefix || nameAffix == naming.AffixClusterSuffix) {
+		setupLog.Error(nil, "--cluster-name is required when --pipeline-name-affix uses the cluster name")
+		os.Exit(1)
+	}
+	namingStrategy, err := naming.ParseStrategy(defaultNamingStrategy)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-naming-strategy")
+		os.Exit(1)
+	}
+	credentialScanMode, err := credscan.ParseMode(credentialScan)
+	if err != nil {
+		setupLog.Error(err, "invalid --credential-scan")
+		os.Exit(1)
+	}
+	pausedPipelines, err := labels.Parse(pausedSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --paused-selector")
+		os.Exit(1)
+	}
+	monitorPipelineSelector, err := labels.Parse(monitorSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --monitor-selector")
+		os.Exit(1)
+	}
+	if syncBatchWindow > 0 && clusterName == "" {
+		setupLog.Error(nil, "--cluster-name is required when --sync-batch-window is set")
+		os.Exit(1)
+	}
+	if monitorPipelines && monitorOptions.RemoteWriteURL == "" {
+		setupLog.Error(nil, "--monitor-remote-write-url is required when --monitor-pipelines is set")
+		os.Exit(1)
+	}
+	// Without matchers the scrape configuration would be sent to every collector of the stack
+	if monitorPipelines && len(monitorMatchers) == 0 {
+		setupLog.Error(nil, "--monitor-matcher is required when --monitor-pipelines is set")
+		os.Exit(1)
+	}
+	if monitorOptions.KubeconfigFile != "" &&
+		(monitorOptions.APIServer != "" || monitorOptions.BearerTokenFile != "" || monitorOptions.CAFile != "") {
+		setupLog.Error(nil, "--monitor-kubeconfig-file cannot be combined with --monitor-api-server, "+
+			"--monitor-bearer-token-file or --monitor-ca-file")
+		os.Exit(1)
+	}
+	approvalGate, err := approval.ParseGate(approvalNamespaces, approvalSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --approval-selector")
+		os.Exit(1)
+	}
+	// Without the webhook, nothing records who made a change or keeps its author from approving it
+	if (approvalNamespaces != "" || approvalSelector != "") && os.Getenv("ENABLE_WEBHOOKS") == "false" {
+		setupLog.Error(nil, "--approval-namespaces and --approval-selector require the webhook, but ENABLE_WEBHOOKS is false")
+		os.Exit(1)
+	}
+	nameResolver := &naming.Resolver{
+		ClusterName:     clusterName,
+		Affix:           nameAffix,
+		DefaultStrategy: namingStrategy,
+		DefaultTemplate: defaultNameTemplate,
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -199,16 +354,92 @@ func main() {
 	}
 
 	setupLog.Info("initializing Fleet Management API client", "baseURL", fleetBaseURL, "username", fleetUsername)
-	fleetClient := fleetclient.NewClient(fleetBaseURL, fleetUsername, fleetPassword)
+	var fleetClientOpts []fleetclient.Option
+	if fairQueueing {
+		fleetClientOpts = append(fleetClientOpts, fleetclient.WithLimiter(fleetclient.NewFairLimiter(fleetclient.RateLimit, 1)))
+		metrics.Registry.MustRegister(fleetclient.Metrics()...)
+	}
+	fleetClient := fleetclient.NewClient(fleetBaseURL, fleetUsername, fleetPassword, fleetClientOpts...)
 
-	if err := (&controller.PipelineReconciler{
-		Client:      mgr.GetClient(),
-		Scheme:      mgr.GetScheme(),
-		FleetClient: fleetClient,
-	}).SetupWithManager(mgr); err != nil {
+	pipelineReconciler := &controller.PipelineReconciler{
+		Client:                   mgr.GetClient(),
+		APIReader:                mgr.GetAPIReader(),
+		Scheme:                   mgr.GetScheme(),
+		FleetClient:              fleetClient,
+		ClusterName:              clusterName,
+		Naming:                   nameResolver,
+		CollectorRefreshInterval: collectorRefreshInterval,
+		DriftCheckInterval:       driftCheckInterval,
+		CredentialScan:           credentialScanMode,
+		PausedSelector:           pausedPipelines,
+		Approval:                 approvalGate,
+		MaxConcurrentReconciles:  maxConcurrentReconciles,
+	}
+	if collectorRefreshInterval > 0 {
+		pipelineReconciler.CollectorClient = fleetClient
+	}
+	if syncBatchWindow > 0 {
+		pipelineReconciler.Batcher = fleetclient.NewBatcher(fleetClient, syncBatchWindow)
+	}
+	if err := pipelineReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pipeline")
 		os.Exit(1)
 	}
+	if err := (&controller.PipelineQuotaReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PipelineQuota")
+		os.Exit(1)
+	}
+	if monitorPipelines {
+		if err := (&controller.MonitorReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Name:     monitorPipelineName,
+			Selector: monitorPipelineSelector,
+			Options:  monitorOptions,
+			Matchers: monitorMatchers,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "monitors")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupPipelineWebhookWithManager(mgr, &webhookv1alpha1.PipelineCustomValidator{
+			Naming:         nameResolver,
+			Reader:         mgr.GetClient(),
+			CredentialScan: credentialScanMode,
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pipeline")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPipelineTemplateWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PipelineTemplate")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPipelineModuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PipelineModule")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPipelineFragmentWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PipelineFragment")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPipelineKillSwitchWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PipelineKillSwitch")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPipelinePolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PipelinePolicy")
+			os.Exit(1)
+		}
+		if err := webhookv1alpha1.SetupPipelineValidationRuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PipelineValidationRule")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
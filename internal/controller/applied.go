@@ -0,0 +1,135 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+)
+
+// requestHash identifies an UpsertPipelineRequest. Requests are hashed as
+// JSON, whose field order is fixed, so equal requests have equal hashes. The
+// contents are hashed as rendered, before Secret values are substituted, along
+// with the versions of the Secrets, so the hash changes with the Secrets but
+// reveals nothing about their values.
+func requestHash(req *fleetclient.UpsertPipelineRequest, rendered string, secrets []fleetmanagementv1alpha1.ObservedResource) (string, error) {
+	pipeline := *req.Pipeline
+	pipeline.Contents = rendered
+	data, err := json.Marshal(struct {
+		Request *fleetclient.UpsertPipelineRequest         `json:"request"`
+		Secrets []fleetmanagementv1alpha1.ObservedResource `json:"secrets,omitempty"`
+	}{
+		Request: &fleetclient.UpsertPipelineRequest{Pipeline: &pipeline, ValidateOnly: req.ValidateOnly},
+		Secrets: secrets,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// pipelineRevision identifies the revision of a remote pipeline by the time it
+// was last updated. Pipelines without one have no known revision.
+func pipelineRevision(pipeline *fleetclient.Pipeline) string {
+	if pipeline.UpdatedAt == nil {
+		return ""
+	}
+	return pipeline.UpdatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// driftCheckDue reports whether the remote pipeline of a synced pipeline must
+// be compared with status.revisionId again
+func (r *PipelineReconciler) driftCheckDue(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if r.DriftCheckInterval <= 0 || pipeline.Status.AppliedHash == "" {
+		return false
+	}
+	checked := pipeline.Status.RevisionCheckTime
+	return checked == nil || !r.now().Before(checked.Add(r.DriftCheckInterval))
+}
+
+// requeueForDriftCheck makes sure a synced pipeline is reconciled again when
+// its next drift check is due
+func (r *PipelineReconciler) requeueForDriftCheck(pipeline *fleetmanagementv1alpha1.Pipeline, result ctrl.Result) ctrl.Result {
+	checked := pipeline.Status.RevisionCheckTime
+	if r.DriftCheckInterval <= 0 || pipeline.Status.AppliedHash == "" || checked == nil {
+		return result
+	}
+	after := checked.Add(r.DriftCheckInterval).Sub(r.now())
+	if after > 0 && (result.RequeueAfter == 0 || after < result.RequeueAfter) {
+		result.RequeueAfter = after
+	}
+	return result
+}
+
+// upsertUnlessApplied upserts the pipeline unless the same request was the
+// last one synced, as recorded in status.appliedHash. The remote pipeline is
+// only compared with status.revisionId when it is known already or a drift
+// check is due, so that edits made in Fleet Management are overwritten without
+// fetching it on every sync. Skipped syncs return the remote pipeline.
+func (r *PipelineReconciler) upsertUnlessApplied(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, req *fleetclient.UpsertPipelineRequest, rendered string, known *fleetclient.Pipeline) (*fleetclient.Pipeline, error) {
+	log := logf.FromContext(ctx)
+
+	hash, err := requestHash(req, rendered, pipeline.Status.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	if pipeline.Status.ID != "" && pipeline.Status.RevisionID != "" && hash == pipeline.Status.AppliedHash {
+		remote := known
+		if remote != nil && remote.ID != pipeline.Status.ID {
+			remote = nil
+		}
+		if remote == nil && !r.driftCheckDue(pipeline) {
+			log.V(1).Info("request unchanged since the last sync, skipping UpsertPipeline", "hash", hash)
+			r.leaveBatch(pipeline)
+			return &fleetclient.Pipeline{ID: pipeline.Status.ID, Name: pipeline.Status.RemoteName}, nil
+		}
+		if remote == nil {
+			remote, err = r.FleetClient.GetPipeline(ctx, pipeline.Status.ID)
+			if err != nil && !isNotFound(err) {
+				return nil, err
+			}
+		}
+		if remote != nil && pipelineRevision(remote) == pipeline.Status.RevisionID {
+			log.V(1).Info("request and remote pipeline unchanged since the last sync, skipping UpsertPipeline",
+				"hash", hash, "revision", pipeline.Status.RevisionID)
+			pipeline.Status.RevisionCheckTime = &metav1.Time{Time: r.now()}
+			pipeline.Status.SourceNamespace = r.pipelineSource(pipeline).Namespace
+			r.leaveBatch(pipeline)
+			return remote, nil
+		}
+	}
+
+	apiPipeline, err := r.upsertPipeline(ctx, pipeline, req)
+	if err != nil {
+		return nil, err
+	}
+	pipeline.Status.AppliedHash = hash
+	pipeline.Status.RevisionCheckTime = &metav1.Time{Time: r.now()}
+	pipeline.Status.SourceNamespace = r.pipelineSource(pipeline).Namespace
+	return apiPipeline, nil
+}
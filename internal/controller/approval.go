@@ -0,0 +1,194 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/approval"
+)
+
+// appliedChangeKey is the key of the ConfigMap data holding the last applied change
+const appliedChangeKey = "change.yaml"
+
+// awaitingApproval reports whether a change of a gated pipeline must wait for
+// approval, recording the pending change, its authors and its diff in status.
+// Changes of the spec, of the contents rendered from its template, fragments
+// and modules, and of its Secrets all need approval.
+func (r *PipelineReconciler) awaitingApproval(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, change *approval.Change) (bool, error) {
+	if !r.Approval.Gated(pipeline) {
+		pipeline.Status.Approval = nil
+		meta.RemoveStatusCondition(&pipeline.Status.Conditions, conditionTypePendingApproval)
+		return false, nil
+	}
+
+	hash, err := change.Hash()
+	if err != nil {
+		return false, fmt.Errorf("failed to hash change: %w", err)
+	}
+	if pipeline.Status.Approval == nil {
+		pipeline.Status.Approval = &fleetmanagementv1alpha1.ApprovalStatus{}
+	}
+	status := pipeline.Status.Approval
+
+	if hash == status.AppliedHash {
+		status.Pending = nil
+		return false, nil
+	}
+	if approval.Approved(pipeline, hash) {
+		status.Pending = nil
+		meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+			Type:   conditionTypePendingApproval,
+			Status: metav1.ConditionFalse,
+			Reason: reasonApproved,
+			Message: fmt.Sprintf("Change %s was approved by %q", hash,
+				pipeline.Annotations[fleetmanagementv1alpha1.ApprovedByAnnotation]),
+			ObservedGeneration: pipeline.Generation,
+		})
+		return false, nil
+	}
+
+	// The diff is only computed once per change, since the last applied one
+	// is read from its ConfigMap
+	authors := approval.Authors(pipeline)
+	if pending := status.Pending; pending == nil || pending.Hash != hash {
+		spec, err := change.YAML()
+		if err != nil {
+			return false, fmt.Errorf("failed to render change: %w", err)
+		}
+		applied, err := r.appliedChange(ctx, pipeline)
+		if err != nil {
+			return false, err
+		}
+		status.Pending = &fleetmanagementv1alpha1.PendingApproval{
+			Hash: hash,
+			Diff: approval.Diff(applied, spec),
+		}
+	}
+	status.Pending.Generation = pipeline.Generation
+	status.Pending.Authors = authors
+
+	message := fmt.Sprintf("Change %s awaits approval: annotate the Pipeline with %s=%s", hash,
+		fleetmanagementv1alpha1.ApproveAnnotation, hash)
+	if len(authors) > 0 {
+		message += fmt.Sprintf(" as another user than %s", quoteAll(authors))
+	}
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypePendingApproval,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonAwaitingApproval,
+		Message:            message,
+		ObservedGeneration: pipeline.Generation,
+	})
+	return true, nil
+}
+
+// approvalDue reports whether the pending change of a gated pipeline has
+// been approved since the last reconciliation
+func approvalDue(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	status := pipeline.Status.Approval
+	return status != nil && status.Pending != nil && approval.Approved(pipeline, status.Pending.Hash)
+}
+
+// recordAppliedChange remembers the change of a gated pipeline synced to
+// Fleet Management: its hash in status, and the change itself in a ConfigMap
+// owned by the Pipeline to diff the next change against
+func (r *PipelineReconciler) recordAppliedChange(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, change *approval.Change) error {
+	status := pipeline.Status.Approval
+	if status == nil {
+		return nil
+	}
+	hash, err := change.Hash()
+	if err != nil {
+		return fmt.Errorf("failed to hash change: %w", err)
+	}
+	if hash == status.AppliedHash {
+		return nil
+	}
+	spec, err := change.YAML()
+	if err != nil {
+		return fmt.Errorf("failed to render change: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: pipeline.Namespace, Name: appliedChangeName(pipeline)}
+	err = r.uncachedReader().Get(ctx, key, configMap)
+	switch {
+	case apierrors.IsNotFound(err):
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: fleetmanagementv1alpha1.GroupVersion.String(),
+					Kind:       "Pipeline",
+					Name:       pipeline.Name,
+					UID:        pipeline.UID,
+				}},
+			},
+			Data: map[string]string{appliedChangeKey: spec},
+		}
+		err = r.Create(ctx, configMap)
+	case err == nil:
+		configMap.Data = map[string]string{appliedChangeKey: spec}
+		err = r.Update(ctx, configMap)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record applied change in ConfigMap %q: %w", key.Name, err)
+	}
+
+	status.AppliedHash = hash
+	return nil
+}
+
+// appliedChange returns the last change of a gated pipeline synced to Fleet
+// Management, in YAML, or nothing if none was recorded
+func (r *PipelineReconciler) appliedChange(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: pipeline.Namespace, Name: appliedChangeName(pipeline)}
+	if err := r.uncachedReader().Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get ConfigMap %q: %w", key.Name, err)
+	}
+	return configMap.Data[appliedChangeKey], nil
+}
+
+// appliedChangeName names the ConfigMap holding the last applied change of a
+// pipeline. Pipeline names may not be valid ConfigMap names, so the UID is used.
+func appliedChangeName(pipeline *fleetmanagementv1alpha1.Pipeline) string {
+	return "pipeline-approval-" + string(pipeline.UID)
+}
+
+// quoteAll quotes and joins users for messages
+func quoteAll(users []string) string {
+	quoted := make([]string, len(users))
+	for i, user := range users {
+		quoted[i] = fmt.Sprintf("%q", user)
+	}
+	return strings.Join(quoted, ", ")
+}
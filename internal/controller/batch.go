@@ -0,0 +1,91 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+)
+
+// upsertPipeline sends the pipeline to Fleet Management. With batching
+// enabled, the Pipelines of a namespace are synced together.
+func (r *PipelineReconciler) upsertPipeline(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, req *fleetclient.UpsertPipelineRequest) (*fleetclient.Pipeline, error) {
+	if !r.batched(pipeline) {
+		return r.FleetClient.UpsertPipeline(ctx, req)
+	}
+
+	members, err := r.sourceMembers(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	return r.Batcher.Upsert(ctx, members, req.Pipeline)
+}
+
+// batched reports whether a Pipeline is synced in a batch. Only Pipelines
+// without spec.source are: their <cluster>/<namespace> source holds nothing
+// but the Pipelines of that namespace, while a spec.source may be shared with
+// other clusters, Terraform or Git.
+func (r *PipelineReconciler) batched(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	return r.Batcher != nil && r.ClusterName != "" && pipeline.Spec.Source == nil
+}
+
+// sourceMembers returns how many Pipelines share the source of the pipeline,
+// or 0 when the source cannot be synced as a whole. SyncPipelines would delete
+// canaries and the remote pipelines of paused or deleted Pipelines, as well as
+// those of synced Pipelines that are unchanged and skip the sync, so sources
+// with any of them are upserted one pipeline at a time.
+func (r *PipelineReconciler) sourceMembers(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (int, error) {
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := r.List(ctx, pipelines, client.InNamespace(pipeline.Namespace)); err != nil {
+		return 0, err
+	}
+
+	members := 0
+	for i := range pipelines.Items {
+		p := &pipelines.Items[i]
+		if !r.batched(p) {
+			continue
+		}
+		if !p.DeletionTimestamp.IsZero() || r.pauseReason(p) != "" ||
+			p.Spec.Rollout != nil || p.Status.Rollout != nil {
+			return 0, nil
+		}
+		if p.Name != pipeline.Name && !syncPending(p) {
+			return 0, nil
+		}
+		members++
+	}
+	return members, nil
+}
+
+// syncPending reports whether a Pipeline was never synced or its spec changed
+// since, so that it is expected to join the batch of its source
+func syncPending(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	return pipeline.Status.AppliedHash == "" || pipeline.Status.ObservedGeneration != pipeline.Generation
+}
+
+// leaveBatch tells the batch of the pipeline's source that the pipeline skips
+// the sync, so the batch does not wait for it
+func (r *PipelineReconciler) leaveBatch(pipeline *fleetmanagementv1alpha1.Pipeline) {
+	if r.batched(pipeline) {
+		r.Batcher.Leave(*r.pipelineSource(pipeline))
+	}
+}
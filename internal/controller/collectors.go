@@ -0,0 +1,181 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+)
+
+const (
+	// maxMatchedCollectorIDs is the number of collector IDs kept in status
+	maxMatchedCollectorIDs = 20
+
+	// defaultCollectorRefreshInterval is used when CollectorRefreshInterval is not set
+	defaultCollectorRefreshInterval = 5 * time.Minute
+)
+
+// FleetCollectorClient defines the interface for listing collectors in Fleet Management
+type FleetCollectorClient interface {
+	ListCollectors(ctx context.Context, req *fleetclient.ListCollectorsRequest) ([]*fleetclient.Collector, error)
+}
+
+// collectorCache shares one collector list between all Pipelines so that
+// reconciling many Pipelines costs a single ListCollectors call per interval
+type collectorCache struct {
+	client   FleetCollectorClient
+	interval time.Duration
+
+	mu         sync.Mutex
+	collectors []*fleetclient.Collector
+	fetchedAt  time.Time
+}
+
+// list returns the cached collectors, refreshing them when they are older than the interval
+func (c *collectorCache) list(ctx context.Context) ([]*fleetclient.Collector, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < c.interval {
+		return c.collectors, c.fetchedAt, nil
+	}
+
+	collectors, err := c.client.ListCollectors(ctx, &fleetclient.ListCollectorsRequest{})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	c.collectors = collectors
+	c.fetchedAt = time.Now()
+	return c.collectors, c.fetchedAt, nil
+}
+
+// collectorRefreshInterval returns how often matched collectors are re-evaluated
+func (r *PipelineReconciler) collectorRefreshInterval() time.Duration {
+	if r.CollectorRefreshInterval > 0 {
+		return r.CollectorRefreshInterval
+	}
+	return defaultCollectorRefreshInterval
+}
+
+// matchCollectors returns the IDs of active collectors selected by ms, sorted
+func matchCollectors(collectors []*fleetclient.Collector, ms matchers.Matchers) []string {
+	var ids []string
+	for _, c := range collectors {
+		if c.MarkedInactiveAt != nil {
+			continue
+		}
+		if ms.Matches(c.Attributes()) {
+			ids = append(ids, c.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// setMatchedCollectors evaluates the pipeline's matchers against the collector
+// list and records the result in status. It does not write the status.
+func (r *PipelineReconciler) setMatchedCollectors(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) error {
+	if r.collectors == nil {
+		return nil
+	}
+
+	ms, errs := matchers.FromSpec(&pipeline.Spec, field.NewPath("spec"))
+	if len(errs) > 0 {
+		// Invalid matchers are reported by the Ready condition
+		return nil
+	}
+
+	collectors, fetchedAt, err := r.collectors.list(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list collectors: %w", err)
+	}
+
+	ids := matchCollectors(collectors, ms)
+	matched := &fleetmanagementv1alpha1.MatchedCollectors{
+		Count:           int32(len(ids)),
+		LastRefreshTime: &metav1.Time{Time: fetchedAt},
+	}
+	if len(ids) > maxMatchedCollectorIDs {
+		ids = ids[:maxMatchedCollectorIDs]
+		matched.Truncated = true
+	}
+	matched.IDs = ids
+	pipeline.Status.MatchedCollectors = matched
+
+	condition := metav1.Condition{
+		Type:               conditionTypeNoMatchingCollectors,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonCollectorsMatched,
+		Message:            fmt.Sprintf("Pipeline matches %d collectors", matched.Count),
+		ObservedGeneration: pipeline.Generation,
+	}
+	if matched.Count == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonNoMatchingCollectors
+		condition.Message = "Pipeline matchers do not select any active collector"
+	}
+	meta.SetStatusCondition(&pipeline.Status.Conditions, condition)
+
+	return nil
+}
+
+// refreshMatchedCollectors re-evaluates matched collectors of an already synced
+// pipeline once the refresh interval has passed, and requeues for the next refresh
+func (r *PipelineReconciler) refreshMatchedCollectors(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if r.collectors == nil {
+		return ctrl.Result{}, nil
+	}
+
+	interval := r.collectorRefreshInterval()
+	if matched := pipeline.Status.MatchedCollectors; matched != nil && matched.LastRefreshTime != nil {
+		if age := time.Since(matched.LastRefreshTime.Time); age < interval {
+			return ctrl.Result{RequeueAfter: interval - age}, nil
+		}
+	}
+
+	if err := r.setMatchedCollectors(ctx, pipeline); err != nil {
+		log.Error(err, "failed to refresh matched collectors")
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+
+	if err := r.Status().Update(ctx, pipeline); err != nil {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("status update conflict, requeueing")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/credscan"
+)
+
+// scanCredentials scans the rendered contents, before Secret values are
+// substituted, and records the findings in the CredentialsDetected condition.
+// It returns a renderError when the pipeline must not be synced.
+func (r *PipelineReconciler) scanCredentials(pipeline *fleetmanagementv1alpha1.Pipeline, contents string) error {
+	if r.CredentialScan == "" || r.CredentialScan == credscan.ModeOff {
+		meta.RemoveStatusCondition(&pipeline.Status.Conditions, conditionTypeCredentialsDetected)
+		return nil
+	}
+
+	findings := credscan.ScanPipeline(pipeline, contents)
+	if len(findings) == 0 {
+		meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+			Type:               conditionTypeCredentialsDetected,
+			Status:             metav1.ConditionFalse,
+			Reason:             reasonNoCredentialsDetected,
+			Message:            "No plaintext credentials found in contents",
+			ObservedGeneration: pipeline.Generation,
+		})
+		return nil
+	}
+
+	summary := credscan.Summary(findings)
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeCredentialsDetected,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonCredentialsDetected,
+		Message:            summary,
+		ObservedGeneration: pipeline.Generation,
+	})
+	if r.CredentialScan == credscan.ModeDeny {
+		return &renderError{reasonCredentialsDetected, errors.New(summary)}
+	}
+	return nil
+}
+
+// credentialsBlocked reports whether the last sync was refused because of
+// credential findings. Such pipelines are rescanned on every reconcile, since
+// the allow-list annotation does not change the generation.
+func credentialsBlocked(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	ready := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeReady)
+	return ready != nil && ready.Reason == reasonCredentialsDetected
+}
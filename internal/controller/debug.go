@@ -0,0 +1,56 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// debugExpiry returns when a debug pipeline is deleted. spec.debug can only be
+// set on creation, so the TTL is counted from the creation of the Pipeline.
+func debugExpiry(pipeline *fleetmanagementv1alpha1.Pipeline) (time.Time, bool) {
+	if pipeline.Spec.Debug == nil {
+		return time.Time{}, false
+	}
+	return pipeline.CreationTimestamp.Add(pipeline.Spec.Debug.TTL.Duration), true
+}
+
+// debugExpired reports whether the pipeline is a debug pipeline past its TTL
+func (r *PipelineReconciler) debugExpired(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	expiry, ok := debugExpiry(pipeline)
+	return ok && !r.now().Before(expiry)
+}
+
+// deleteExpired deletes a debug pipeline whose TTL expired. The finalizer
+// then removes the remote pipeline in reconcileDelete, even when paused.
+func (r *PipelineReconciler) deleteExpired(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	log.Info("debug pipeline TTL expired, deleting it", "ttl", pipeline.Spec.Debug.TTL.Duration)
+	if err := r.Delete(ctx, pipeline); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "failed to delete expired debug pipeline")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
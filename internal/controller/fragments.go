@@ -0,0 +1,155 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fragments"
+)
+
+// listFragments returns the PipelineFragments selected by the pipeline, in merge order
+func (r *PipelineReconciler) listFragments(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) ([]fleetmanagementv1alpha1.PipelineFragment, error) {
+	selector, err := metav1.LabelSelectorAsSelector(pipeline.Spec.FragmentSelector)
+	if err != nil {
+		return nil, &renderError{reasonFragmentError, fmt.Errorf("invalid fragmentSelector: %w", err)}
+	}
+
+	list := &fleetmanagementv1alpha1.PipelineFragmentList{}
+	if err := r.List(ctx, list,
+		client.InNamespace(pipeline.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list PipelineFragments: %w", err)
+	}
+
+	fragments.Sort(list.Items)
+	return list.Items, nil
+}
+
+// applyFragments merges the contents of the selected PipelineFragments with
+// contents. The fragments merged are recorded in status.
+func (r *PipelineReconciler) applyFragments(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, contents string) (string, error) {
+	if pipeline.Spec.FragmentSelector == nil {
+		pipeline.Status.Fragments = nil
+		return contents, nil
+	}
+
+	selected, err := r.listFragments(ctx, pipeline)
+	if err != nil {
+		return "", err
+	}
+
+	pipeline.Status.Fragments = make([]fleetmanagementv1alpha1.ObservedResource, 0, len(selected))
+	for i := range selected {
+		pipeline.Status.Fragments = append(pipeline.Status.Fragments, *observedResource(&selected[i]))
+	}
+
+	if len(selected) == 0 && contents == "" {
+		return "", &renderError{reasonFragmentError, fmt.Errorf("fragmentSelector matches no PipelineFragments")}
+	}
+
+	configType := pipeline.Spec.ConfigType
+	if configType == "" {
+		configType = fleetmanagementv1alpha1.ConfigTypeAlloy
+	}
+	merged, err := fragments.Merge(configType, selected, contents)
+	if err != nil {
+		return "", &renderError{reasonFragmentError, err}
+	}
+	return merged, nil
+}
+
+// fragmentsChanged reports whether the set of selected PipelineFragments, or
+// any of their generations, differs from the one last merged
+func (r *PipelineReconciler) fragmentsChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if pipeline.Spec.FragmentSelector == nil {
+		return false
+	}
+
+	selected, err := r.listFragments(ctx, pipeline)
+	if err != nil {
+		// Invalid selectors are reported once; list errors are retried by reconciling
+		var renderErr *renderError
+		return !errors.As(err, &renderErr)
+	}
+
+	observed := pipeline.Status.Fragments
+	if len(selected) != len(observed) {
+		return true
+	}
+	for i := range selected {
+		if selected[i].Name != observed[i].Name ||
+			selected[i].UID != observed[i].UID ||
+			selected[i].Generation != observed[i].Generation {
+			return true
+		}
+	}
+	return false
+}
+
+// pipelinesForFragment maps a PipelineFragment to the Pipelines in its namespace
+// that select it now or merged it last time
+func (r *PipelineReconciler) pipelinesForFragment(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := r.List(ctx, pipelines, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list Pipelines for PipelineFragment", "name", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, p := range pipelines.Items {
+		if p.Spec.FragmentSelector == nil && len(p.Status.Fragments) == 0 {
+			continue
+		}
+		if selectsFragment(&p, obj) || mergedFragment(&p, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+			})
+		}
+	}
+	return requests
+}
+
+func selectsFragment(pipeline *fleetmanagementv1alpha1.Pipeline, fragment client.Object) bool {
+	if pipeline.Spec.FragmentSelector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pipeline.Spec.FragmentSelector)
+	return err == nil && selector.Matches(labels.Set(fragment.GetLabels()))
+}
+
+func mergedFragment(pipeline *fleetmanagementv1alpha1.Pipeline, name string) bool {
+	for _, observed := range pipeline.Status.Fragments {
+		if observed.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+)
+
+// killSwitchSelects reports whether the kill switch selects the pipeline,
+// whose remote pipeline is named remoteName
+func killSwitchSelects(ks *fleetmanagementv1alpha1.PipelineKillSwitch, pipeline *fleetmanagementv1alpha1.Pipeline, remoteName string) (bool, error) {
+	if ks.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(ks.Spec.Selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid selector: %w", err)
+		}
+		if !selector.Matches(labels.Set(pipeline.Labels)) {
+			return false, nil
+		}
+	}
+	if ks.Spec.RemoteNamePattern != "" {
+		pattern, err := regexp.Compile("^(?:" + ks.Spec.RemoteNamePattern + ")$")
+		if err != nil {
+			return false, fmt.Errorf("invalid remoteNamePattern: %w", err)
+		}
+		if !pattern.MatchString(remoteName) {
+			return false, nil
+		}
+	}
+	return ks.Spec.Selector != nil || ks.Spec.RemoteNamePattern != "", nil
+}
+
+// activeKillSwitch returns the first kill switch, by name, selecting the
+// pipeline, or nil when none does. Kill switches that cannot be evaluated
+// are skipped.
+func (r *PipelineReconciler) activeKillSwitch(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, remoteName string) (*fleetmanagementv1alpha1.PipelineKillSwitch, error) {
+	log := logf.FromContext(ctx)
+
+	switches := &fleetmanagementv1alpha1.PipelineKillSwitchList{}
+	if err := r.List(ctx, switches); err != nil {
+		return nil, fmt.Errorf("failed to list PipelineKillSwitches: %w", err)
+	}
+	slices.SortFunc(switches.Items, func(a, b fleetmanagementv1alpha1.PipelineKillSwitch) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i := range switches.Items {
+		ks := &switches.Items[i]
+		selected, err := killSwitchSelects(ks, pipeline, remoteName)
+		if err != nil {
+			log.Error(err, "skipping PipelineKillSwitch", "killSwitch", ks.Name)
+			continue
+		}
+		if selected {
+			return ks, nil
+		}
+	}
+	return nil, nil
+}
+
+// applyKillSwitch disables the remote pipeline while a kill switch selects it
+// and records the override in status
+func (r *PipelineReconciler) applyKillSwitch(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, remote *fleetclient.Pipeline) error {
+	ks, err := r.activeKillSwitch(ctx, pipeline, remote.Name)
+	if err != nil {
+		return err
+	}
+
+	if ks == nil {
+		pipeline.Status.KillSwitch = ""
+		meta.RemoveStatusCondition(&pipeline.Status.Conditions, conditionTypeKillSwitchEngaged)
+		return nil
+	}
+
+	message := fmt.Sprintf("Disabled by PipelineKillSwitch %q", ks.Name)
+	if ks.Spec.Reason != "" {
+		message += ": " + ks.Spec.Reason
+	}
+	remote.Enabled = false
+	pipeline.Status.KillSwitch = ks.Name
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeKillSwitchEngaged,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonKillSwitchEngaged,
+		Message:            message,
+		ObservedGeneration: pipeline.Generation,
+	})
+	return nil
+}
+
+// killSwitchChanged reports whether the kill switch selecting an already
+// synced pipeline differs from the one applied last time
+func (r *PipelineReconciler) killSwitchChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	ks, err := r.activeKillSwitch(ctx, pipeline, pipeline.Status.RemoteName)
+	if err != nil {
+		return true
+	}
+
+	active := ""
+	if ks != nil {
+		active = ks.Name
+	}
+	return active != pipeline.Status.KillSwitch
+}
+
+// pipelinesForKillSwitch maps a PipelineKillSwitch to the Pipelines it selects
+// or disabled last time
+func (r *PipelineReconciler) pipelinesForKillSwitch(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	ks, ok := obj.(*fleetmanagementv1alpha1.PipelineKillSwitch)
+	if !ok {
+		return nil
+	}
+
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := r.List(ctx, pipelines); err != nil {
+		log.Error(err, "failed to list Pipelines for PipelineKillSwitch", "name", ks.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range pipelines.Items {
+		p := &pipelines.Items[i]
+		selected, _ := killSwitchSelects(ks, p, p.Status.RemoteName)
+		if selected || p.Status.KillSwitch == ks.Name {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+			})
+		}
+	}
+	return requests
+}
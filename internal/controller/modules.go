@@ -0,0 +1,131 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/modules"
+)
+
+// modulesIndex indexes Pipelines by the names of the PipelineModules they are rendered from,
+// including dependencies of the modules they reference directly
+const modulesIndex = "spec.modules.name"
+
+// applyModules prepends the declarations of the pipeline's modules and their
+// dependencies to contents. Every module looked up is recorded in status, so
+// that edits to any of them, or the creation of a missing one, re-sync the pipeline.
+func (r *PipelineReconciler) applyModules(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, contents string) (string, error) {
+	if len(pipeline.Spec.Modules) == 0 {
+		pipeline.Status.Modules = nil
+		return contents, nil
+	}
+
+	if pipeline.Spec.ConfigType != "" && pipeline.Spec.ConfigType != fleetmanagementv1alpha1.ConfigTypeAlloy {
+		pipeline.Status.Modules = nil
+		return "", &renderError{reasonModuleError, fmt.Errorf("modules are only supported for Alloy pipelines")}
+	}
+
+	names := make([]string, 0, len(pipeline.Spec.Modules))
+	for _, ref := range pipeline.Spec.Modules {
+		names = append(names, ref.Name)
+	}
+
+	var observed []fleetmanagementv1alpha1.ObservedResource
+	var getErr error
+	ordered, err := modules.Resolve(names, func(name string) (*fleetmanagementv1alpha1.PipelineModule, error) {
+		module := &fleetmanagementv1alpha1.PipelineModule{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: name}, module); err != nil {
+			if apierrors.IsNotFound(err) {
+				observed = append(observed, fleetmanagementv1alpha1.ObservedResource{Name: name})
+				return nil, nil
+			}
+			getErr = fmt.Errorf("failed to get PipelineModule %q: %w", name, err)
+			return nil, getErr
+		}
+		observed = append(observed, *observedResource(module))
+		return module, nil
+	})
+	pipeline.Status.Modules = observed
+	if getErr != nil {
+		return "", getErr
+	}
+	if err != nil {
+		// Missing modules and dependency cycles need a change to a module
+		return "", &renderError{reasonModuleError, err}
+	}
+
+	// Record modules in the order they are included
+	pipeline.Status.Modules = make([]fleetmanagementv1alpha1.ObservedResource, 0, len(ordered))
+	for _, module := range ordered {
+		pipeline.Status.Modules = append(pipeline.Status.Modules, *observedResource(module))
+	}
+
+	assembled, err := modules.Assemble(ordered, contents)
+	if err != nil {
+		return "", &renderError{reasonModuleError, err}
+	}
+	return assembled, nil
+}
+
+// modulesChanged reports whether any PipelineModule the pipeline was rendered
+// from changed, was deleted or, if it was missing, was created
+func (r *PipelineReconciler) modulesChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	for _, observed := range pipeline.Status.Modules {
+		module := &fleetmanagementv1alpha1.PipelineModule{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: observed.Name}, module); err != nil {
+			if apierrors.IsNotFound(err) && observed.UID == "" {
+				continue
+			}
+			return true
+		}
+		if module.UID != observed.UID || module.Generation != observed.Generation {
+			return true
+		}
+	}
+	return false
+}
+
+// indexModules returns the module names a Pipeline references directly or was rendered from, for modulesIndex
+func indexModules(obj client.Object) []string {
+	pipeline, ok := obj.(*fleetmanagementv1alpha1.Pipeline)
+	if !ok {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, ref := range pipeline.Spec.Modules {
+		add(ref.Name)
+	}
+	for _, observed := range pipeline.Status.Modules {
+		add(observed.Name)
+	}
+	return names
+}
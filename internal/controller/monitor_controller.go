@@ -0,0 +1,222 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/monitors"
+)
+
+// MonitorReconciler generates a Pipeline in each namespace scraping the
+// targets of the selected Prometheus Operator ServiceMonitors and PodMonitors
+// of the namespace. The monitors share the prometheus.remote_write of the
+// Pipeline, so collectors open one remote write queue per namespace however
+// many monitors there are. Every monitor owns the Pipeline, which is deleted
+// with the last of them.
+type MonitorReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Name of the Pipeline generated in each namespace
+	Name string
+
+	// Selector selects the monitors the Pipelines are generated from. A nil
+	// Selector selects every monitor.
+	Selector labels.Selector
+
+	// Options configure the discovery and remote write of the generated Alloy
+	Options monitors.Options
+
+	// Matchers are the matchers of the generated Pipelines
+	Matchers []string
+}
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch
+
+// Reconcile creates, updates or deletes the Pipeline generated from the
+// monitors of a namespace
+func (r *MonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var selected []*monitors.Monitor
+	var owners []metav1.OwnerReference
+	for _, kind := range []string{monitors.ServiceMonitorKind, monitors.PodMonitorKind} {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(monitors.GroupVersion.WithKind(kind + "List"))
+		if err := r.List(ctx, list, client.InNamespace(req.Namespace)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list %ss: %w", kind, err)
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !obj.GetDeletionTimestamp().IsZero() ||
+				(r.Selector != nil && !r.Selector.Matches(labels.Set(obj.GetLabels()))) {
+				continue
+			}
+			m, err := monitors.FromUnstructured(obj.Object)
+			if err == nil {
+				err = monitors.Check(m)
+			}
+			if err != nil {
+				// Retrying does not help until the monitor changes
+				log.Info("leaving monitor out of the generated Pipeline", "kind", kind,
+					"name", obj.GetName(), "error", err.Error())
+				continue
+			}
+			selected = append(selected, m)
+			owners = append(owners, metav1.OwnerReference{
+				APIVersion: monitors.GroupVersion.String(),
+				Kind:       kind,
+				Name:       obj.GetName(),
+				UID:        obj.GetUID(),
+			})
+		}
+	}
+
+	pipeline := &fleetmanagementv1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: r.Name, Namespace: req.Namespace},
+	}
+	if len(selected) == 0 {
+		return ctrl.Result{}, r.deleteGenerated(ctx, pipeline)
+	}
+
+	// Sorted so the contents only change when the monitors do
+	slices.SortFunc(selected, func(a, b *monitors.Monitor) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(owners, func(a, b metav1.OwnerReference) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Name, b.Name))
+	})
+	contents, err := monitors.Generate(selected, r.Options)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, pipeline, func() error {
+		if pipeline.ResourceVersion != "" && !generated(pipeline) {
+			return fmt.Errorf("pipeline %s/%s already exists and was not generated from monitors",
+				pipeline.Namespace, pipeline.Name)
+		}
+		if pipeline.Labels == nil {
+			pipeline.Labels = map[string]string{}
+		}
+		pipeline.Labels[fleetmanagementv1alpha1.GeneratedFromLabel] = monitors.GroupVersion.Group
+		// Owned by every monitor, so the garbage collector deletes the
+		// Pipeline once all of them are deleted
+		pipeline.OwnerReferences = owners
+		pipeline.Spec.Contents = contents
+		pipeline.Spec.Matchers = r.Matchers
+		pipeline.Spec.Enabled = true
+		pipeline.Spec.ConfigType = fleetmanagementv1alpha1.ConfigTypeAlloy
+		pipeline.Spec.NamingStrategy = fleetmanagementv1alpha1.NamingStrategyNamespacedName
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("generated Pipeline from monitors", "pipeline", pipeline.Name, "monitors", len(selected), "operation", result)
+	}
+	return ctrl.Result{}, r.reportIgnored(ctx, pipeline, selected)
+}
+
+// reportIgnored sets the MonitorSettingsIgnored condition of the generated
+// Pipeline to the monitor settings left out of its contents
+func (r *MonitorReconciler) reportIgnored(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, selected []*monitors.Monitor) error {
+	var ignored []string
+	for _, m := range selected {
+		if settings := monitors.Ignored(m); len(settings) > 0 {
+			ignored = append(ignored, fmt.Sprintf("%s %s: %s", m.Kind, m.Name, strings.Join(settings, ", ")))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionTypeMonitorSettingsIgnored,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonMonitorSettingsSupported,
+		Message:            "Every setting of the monitors is generated",
+		ObservedGeneration: pipeline.Generation,
+	}
+	if len(ignored) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonMonitorSettingsIgnored
+		condition.Message = "TLS and authentication settings need Secrets of the cluster and are not generated: " +
+			strings.Join(ignored, "; ")
+	}
+	if !meta.SetStatusCondition(&pipeline.Status.Conditions, condition) {
+		return nil
+	}
+	return r.Status().Update(ctx, pipeline)
+}
+
+// deleteGenerated deletes the generated Pipeline once no monitor of its
+// namespace is selected. Deleted monitors are left to the garbage collector.
+func (r *MonitorReconciler) deleteGenerated(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pipeline), pipeline); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !generated(pipeline) {
+		return nil
+	}
+	logf.FromContext(ctx).Info("no monitor is selected, deleting generated Pipeline", "pipeline", pipeline.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, pipeline))
+}
+
+// generated reports whether a Pipeline was generated from monitors
+func generated(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	return pipeline.Labels[fleetmanagementv1alpha1.GeneratedFromLabel] == monitors.GroupVersion.Group
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Every change regenerates the Pipeline of the namespace
+	generate := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: r.Name}}}
+	})
+	isGenerated := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == r.Name
+	})
+
+	b := ctrl.NewControllerManagedBy(mgr).Named("monitors")
+	for _, kind := range []string{monitors.ServiceMonitorKind, monitors.PodMonitorKind} {
+		monitor := &unstructured.Unstructured{}
+		monitor.SetGroupVersionKind(monitors.GroupVersion.WithKind(kind))
+		b = b.Watches(monitor, generate)
+	}
+	return b.Watches(&fleetmanagementv1alpha1.Pipeline{}, generate, builder.WithPredicates(isGenerated)).
+		Complete(r)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/monitors"
+)
+
+var _ = Describe("Monitor Controller", func() {
+	Context("When monitors are reconciled", func() {
+		ctx := context.Background()
+		key := types.NamespacedName{Namespace: "shop", Name: "prometheus-monitors"}
+
+		newMonitor := func(kind string, labels map[string]string) *unstructured.Unstructured {
+			endpoints := "endpoints"
+			if kind == monitors.PodMonitorKind {
+				endpoints = "podMetricsEndpoints"
+			}
+			monitor := &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{
+					"selector": map[string]any{"matchLabels": map[string]any{"app": "api"}},
+					endpoints:  []any{map[string]any{"port": "metrics"}},
+				},
+			}}
+			monitor.SetGroupVersionKind(monitors.GroupVersion.WithKind(kind))
+			monitor.SetName("api")
+			monitor.SetNamespace("shop")
+			monitor.SetUID(types.UID(kind + "-uid"))
+			monitor.SetLabels(labels)
+			return monitor
+		}
+
+		newReconciler := func(selector labels.Selector, objs ...client.Object) *MonitorReconciler {
+			return &MonitorReconciler{
+				Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(objs...).
+					WithStatusSubresource(&fleetmanagementv1alpha1.Pipeline{}).Build(),
+				Scheme:   k8sClient.Scheme(),
+				Name:     key.Name,
+				Selector: selector,
+				Options:  monitors.Options{RemoteWriteURL: "https://prom.example.com/api/v1/write"},
+				Matchers: []string{"collector.os=linux"},
+			}
+		}
+		request := ctrl.Request{NamespacedName: key}
+
+		It("should generate a Pipeline owned by the monitors of the namespace", func() {
+			r := newReconciler(nil, newMonitor(monitors.ServiceMonitorKind, nil), newMonitor(monitors.PodMonitorKind, nil))
+
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).ToNot(HaveOccurred())
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{}
+			Expect(r.Get(ctx, key, pipeline)).To(Succeed())
+			Expect(pipeline.Spec.Contents).To(ContainSubstring(`prometheus.scrape "servicemonitor_shop_api_0"`))
+			Expect(pipeline.Spec.Contents).To(ContainSubstring(`prometheus.scrape "podmonitor_shop_api_0"`))
+			Expect(strings.Count(pipeline.Spec.Contents, "prometheus.remote_write ")).To(Equal(1))
+			Expect(pipeline.Spec.Matchers).To(Equal([]string{"collector.os=linux"}))
+			Expect(pipeline.Spec.Enabled).To(BeTrue())
+			Expect(pipeline.Spec.ConfigType).To(Equal(fleetmanagementv1alpha1.ConfigTypeAlloy))
+			Expect(pipeline.Labels).To(HaveKeyWithValue(fleetmanagementv1alpha1.GeneratedFromLabel, monitors.GroupVersion.Group))
+			Expect(pipeline.OwnerReferences).To(HaveLen(2))
+			for _, kind := range []string{monitors.ServiceMonitorKind, monitors.PodMonitorKind} {
+				Expect(metav1.IsControlledBy(pipeline, newMonitor(kind, nil))).To(BeFalse())
+				Expect(pipeline.OwnerReferences).To(ContainElement(HaveField("UID", types.UID(kind+"-uid"))))
+			}
+			condition := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeMonitorSettingsIgnored)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("should report monitor settings left out of the Pipeline", func() {
+			monitor := newMonitor(monitors.ServiceMonitorKind, nil)
+			Expect(unstructured.SetNestedSlice(monitor.Object, []any{map[string]any{
+				"port":      "https",
+				"tlsConfig": map[string]any{"insecureSkipVerify": true},
+			}}, "spec", "endpoints")).To(Succeed())
+			r := newReconciler(nil, monitor)
+
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).ToNot(HaveOccurred())
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{}
+			Expect(r.Get(ctx, key, pipeline)).To(Succeed())
+			condition := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeMonitorSettingsIgnored)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("ServiceMonitor api: endpoints[0].tlsConfig"))
+		})
+
+		It("should delete the Pipeline when no monitor is selected", func() {
+			selector := labels.SelectorFromSet(labels.Set{"team": "shop"})
+			r := newReconciler(selector, newMonitor(monitors.ServiceMonitorKind, map[string]string{"team": "shop"}))
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Get(ctx, key, &fleetmanagementv1alpha1.Pipeline{})).To(Succeed())
+
+			monitor := newMonitor(monitors.ServiceMonitorKind, nil)
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(monitor), monitor)).To(Succeed())
+			monitor.SetLabels(nil)
+			Expect(r.Update(ctx, monitor)).To(Succeed())
+
+			_, err = r.Reconcile(ctx, request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apierrors.IsNotFound(r.Get(ctx, key, &fleetmanagementv1alpha1.Pipeline{}))).To(BeTrue())
+		})
+
+		It("should not overwrite or delete a Pipeline that was not generated", func() {
+			existing := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Contents: "logging { }"},
+			}
+			r := newReconciler(nil, newMonitor(monitors.ServiceMonitorKind, nil), existing)
+
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).To(HaveOccurred())
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{}
+			Expect(r.Get(ctx, key, pipeline)).To(Succeed())
+			Expect(pipeline.Spec.Contents).To(Equal("logging { }"))
+
+			r = newReconciler(nil, existing)
+			_, err = r.Reconcile(ctx, request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Get(ctx, key, pipeline)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+)
+
+// pipelineSource returns the source recorded for a pipeline in Fleet Management
+func (r *PipelineReconciler) pipelineSource(pipeline *fleetmanagementv1alpha1.Pipeline) *fleetclient.Source {
+	if pipeline.Spec.Source != nil {
+		return &fleetclient.Source{
+			Type:      pipeline.Spec.Source.Type.ToFleetAPI(),
+			Namespace: pipeline.Spec.Source.Namespace,
+		}
+	}
+
+	// Default to Kubernetes source
+	return &fleetclient.Source{
+		Type:      fleetmanagementv1alpha1.SourceTypeKubernetes.ToFleetAPI(),
+		Namespace: r.defaultSourceNamespace(pipeline),
+	}
+}
+
+// defaultSourceNamespace returns the source namespace recorded for pipelines
+// that do not specify spec.source. It includes the cluster name when one is
+// configured so that pipelines from different clusters can be told apart.
+// Batched Pipelines share the source of their namespace, which is synced as
+// a whole.
+func (r *PipelineReconciler) defaultSourceNamespace(pipeline *fleetmanagementv1alpha1.Pipeline) string {
+	if r.ClusterName == "" {
+		return fmt.Sprintf("%s/%s", pipeline.Namespace, pipeline.Name)
+	}
+	if r.batched(pipeline) {
+		return fmt.Sprintf("%s/%s", r.ClusterName, pipeline.Namespace)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.ClusterName, pipeline.Namespace, pipeline.Name)
+}
+
+// ownsRemotePipeline reports whether a remote pipeline may be adopted, updated
+// or deleted by this Pipeline. Pipelines created from Kubernetes are owned only
+// when their source is one this Pipeline writes or wrote, which keeps one
+// cluster from touching pipelines managed by another. Pipelines from other
+// sources carry no cluster identity and are left to the user.
+func (r *PipelineReconciler) ownsRemotePipeline(pipeline *fleetmanagementv1alpha1.Pipeline, remote *fleetclient.Pipeline) bool {
+	if remote.Source == nil || remote.Source.Type != fleetmanagementv1alpha1.SourceTypeKubernetes.ToFleetAPI() {
+		return true
+	}
+	return slices.Contains(r.ownedSourceNamespaces(pipeline), remote.Source.Namespace)
+}
+
+// ownedSourceNamespaces returns the Kubernetes source namespaces of the remote
+// pipelines owned by a Pipeline, synced with batching turned on or off.
+// Pipelines synced before --cluster-name was set carry the legacy
+// <namespace>/<name> source, which another cluster may have written as well.
+// They are only owned by Pipelines with the adopt-legacy-source annotation
+// that were not synced with the cluster yet, and the next sync records the
+// cluster in their source.
+func (r *PipelineReconciler) ownedSourceNamespaces(pipeline *fleetmanagementv1alpha1.Pipeline) []string {
+	if pipeline.Spec.Source != nil || r.ClusterName == "" {
+		return []string{r.pipelineSource(pipeline).Namespace}
+	}
+	namespaces := []string{
+		fmt.Sprintf("%s/%s/%s", r.ClusterName, pipeline.Namespace, pipeline.Name),
+		fmt.Sprintf("%s/%s", r.ClusterName, pipeline.Namespace),
+	}
+	legacy := fmt.Sprintf("%s/%s", pipeline.Namespace, pipeline.Name)
+	if pipeline.Annotations[fleetmanagementv1alpha1.AdoptLegacySourceAnnotation] == "true" &&
+		(pipeline.Status.SourceNamespace == "" || pipeline.Status.SourceNamespace == legacy) {
+		namespaces = append(namespaces, legacy)
+	}
+	return namespaces
+}
+
+// ownershipKnown reports whether the remote pipeline the Pipeline writes to
+// under name was synced with its current source, so it belongs to the
+// Pipeline without fetching it
+func (r *PipelineReconciler) ownershipKnown(pipeline *fleetmanagementv1alpha1.Pipeline, name string) bool {
+	return pipeline.Status.ID != "" && pipeline.Status.RemoteName == name &&
+		pipeline.Status.SourceNamespace == r.pipelineSource(pipeline).Namespace
+}
+
+// adoptionDue reports whether the last sync was refused because of an
+// ownership conflict and the Pipeline was annotated to adopt a legacy source
+// since. The annotation does not change the generation.
+func adoptionDue(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	ready := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeReady)
+	return ready != nil && ready.Reason == reasonOwnershipConflict &&
+		pipeline.Annotations[fleetmanagementv1alpha1.AdoptLegacySourceAnnotation] == "true"
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// pauseReason returns the reason the pipeline is paused, or "" when it is not
+func (r *PipelineReconciler) pauseReason(pipeline *fleetmanagementv1alpha1.Pipeline) string {
+	if pipeline.Annotations[fleetmanagementv1alpha1.PausedAnnotation] == "true" {
+		return reasonPausedByAnnotation
+	}
+	if r.PausedSelector != nil && !r.PausedSelector.Empty() && r.PausedSelector.Matches(labels.Set(pipeline.Labels)) {
+		return reasonPausedBySelector
+	}
+	return ""
+}
+
+// reconcilePaused records that the pipeline is paused without syncing it
+func (r *PipelineReconciler) reconcilePaused(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, reason string) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	message := "Reconciliation is paused by the " + fleetmanagementv1alpha1.PausedAnnotation + " annotation"
+	if reason == reasonPausedBySelector {
+		message = "Reconciliation is paused by the operator's paused Pipeline selector"
+	}
+
+	changed := meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypePaused,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: pipeline.Generation,
+	})
+	if !changed {
+		log.V(1).Info("pipeline is paused, skipping", "reason", reason)
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("pipeline is paused, not syncing to Fleet Management", "reason", reason)
+	// Edits made in Fleet Management while paused are overwritten on resume
+	pipeline.Status.AppliedHash = ""
+	if err := r.Status().Update(ctx, pipeline); err != nil {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("status update conflict, requeueing")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// markResumed sets the Paused condition of a pipeline that was paused to
+// False. It reports whether the pipeline was paused, in which case it must be
+// synced again.
+func markResumed(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if !meta.IsStatusConditionTrue(pipeline.Status.Conditions, conditionTypePaused) {
+		return false
+	}
+
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypePaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonResumed,
+		Message:            "Reconciliation resumed",
+		ObservedGeneration: pipeline.Generation,
+	})
+	return true
+}
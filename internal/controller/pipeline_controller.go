@@ -18,22 +18,35 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/approval"
+	"github.com/grafana/fleet-management-operator/pkg/credscan"
 	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+	"github.com/grafana/fleet-management-operator/pkg/naming"
 )
 
 const (
@@ -45,16 +58,69 @@ const (
 	conditionTypeSynced = "Synced"
 
 	// Condition reasons
-	reasonSynced          = "Synced"
-	reasonSyncFailed      = "SyncFailed"
-	reasonValidationError = "ValidationError"
-	reasonDeleting        = "Deleting"
-	reasonDeleteFailed    = "DeleteFailed"
+	reasonSynced            = "Synced"
+	reasonSyncFailed        = "SyncFailed"
+	reasonValidationError   = "ValidationError"
+	reasonDeleting          = "Deleting"
+	reasonDeleteFailed      = "DeleteFailed"
+	reasonOwnershipConflict = "OwnershipConflict"
+	reasonTemplateError     = "TemplateError"
+	reasonModuleError       = "ModuleError"
+	reasonFragmentError     = "FragmentError"
+	reasonSecretError       = "SecretError"
+	reasonFormatError       = "FormatError"
+	reasonQuotaExceeded     = "QuotaExceeded"
+
+	// NoMatchingCollectors condition
+	conditionTypeNoMatchingCollectors = "NoMatchingCollectors"
+	reasonNoMatchingCollectors        = "NoMatchingCollectors"
+	reasonCollectorsMatched           = "CollectorsMatched"
+
+	// CredentialsDetected condition
+	conditionTypeCredentialsDetected = "CredentialsDetected"
+	reasonCredentialsDetected        = "CredentialsDetected"
+	reasonNoCredentialsDetected      = "NoCredentialsDetected"
+
+	// Paused condition
+	conditionTypePaused      = "Paused"
+	reasonPausedByAnnotation = "PausedByAnnotation"
+	reasonPausedBySelector   = "PausedBySelector"
+	reasonResumed            = "Resumed"
+
+	// KillSwitchEngaged condition
+	conditionTypeKillSwitchEngaged = "KillSwitchEngaged"
+	reasonKillSwitchEngaged        = "KillSwitchEngaged"
+
+	// Scheduled condition, with the reasons of pkg/schedule
+	conditionTypeScheduled = "Scheduled"
+
+	// RolloutProgressing condition
+	conditionTypeRolloutProgressing = "RolloutProgressing"
+	reasonCanaryDeployed            = "CanaryDeployed"
+	reasonPromoted                  = "Promoted"
+	reasonCanaryReverted            = "CanaryReverted"
+
+	// PendingApproval condition
+	conditionTypePendingApproval = "PendingApproval"
+	reasonAwaitingApproval       = "AwaitingApproval"
+	reasonApproved               = "Approved"
+
+	// PolicyViolation condition
+	conditionTypePolicyViolation = "PolicyViolation"
+	reasonPolicyViolation        = "PolicyViolation"
+	reasonPolicyCompliant        = "PolicyCompliant"
+
+	// MonitorSettingsIgnored condition of Pipelines generated from monitors
+	conditionTypeMonitorSettingsIgnored = "MonitorSettingsIgnored"
+	reasonMonitorSettingsIgnored        = "MonitorSettingsIgnored"
+	reasonMonitorSettingsSupported      = "MonitorSettingsSupported"
 )
 
 // FleetPipelineClient defines the interface for interacting with Fleet Management API
 type FleetPipelineClient interface {
 	UpsertPipeline(ctx context.Context, req *fleetclient.UpsertPipelineRequest) (*fleetclient.Pipeline, error)
+	GetPipeline(ctx context.Context, id string) (*fleetclient.Pipeline, error)
+	GetPipelineID(ctx context.Context, name string) (string, error)
 	DeletePipeline(ctx context.Context, id string) error
 }
 
@@ -63,6 +129,58 @@ type PipelineReconciler struct {
 	client.Client
 	Scheme      *runtime.Scheme
 	FleetClient FleetPipelineClient
+
+	// ClusterName identifies the cluster this operator runs in. When set, it is
+	// recorded in the pipeline source and remote pipelines are only adopted or
+	// deleted when their source matches.
+	ClusterName string
+
+	// Naming computes remote pipeline names. A nil Naming uses metadata.name or spec.name.
+	Naming *naming.Resolver
+
+	// CollectorClient lists collectors to report which ones each pipeline matches.
+	// A nil CollectorClient disables matched collector reporting.
+	CollectorClient FleetCollectorClient
+
+	// CollectorRefreshInterval is how often matched collectors are re-evaluated
+	CollectorRefreshInterval time.Duration
+
+	// DriftCheckInterval is how often the remote pipeline of a synced Pipeline
+	// is checked for edits made in Fleet Management. Zero disables the check.
+	DriftCheckInterval time.Duration
+
+	// CredentialScan decides whether plaintext credentials in the rendered
+	// contents are reported (warn) or block the sync (deny). Empty disables it.
+	CredentialScan credscan.Mode
+
+	// PausedSelector pauses every Pipeline whose labels it matches, like the
+	// paused annotation. A nil selector pauses nothing.
+	PausedSelector labels.Selector
+
+	// Approval selects the Pipelines whose changes are only synced once
+	// another user approves them. A nil Approval gates nothing.
+	Approval *approval.Gate
+
+	// APIReader reads the Secrets substituted into contents and the applied
+	// changes of gated Pipelines without caching them. A nil APIReader reads
+	// them through the client.
+	APIReader client.Reader
+
+	// Batcher syncs the Pipelines of a namespace in one SyncPipelines call. It
+	// requires ClusterName and leaves Pipelines with a spec.source out. A nil
+	// Batcher upserts every Pipeline on its own.
+	Batcher *fleetclient.Batcher
+
+	// MaxConcurrentReconciles is how many Pipelines are reconciled at once.
+	// The fair-queueing limiter only takes turns between the namespaces of
+	// Pipelines reconciled concurrently, and batches only fill up when their
+	// Pipelines are.
+	MaxConcurrentReconciles int
+
+	collectors *collectorCache
+
+	// clock returns the current time. A nil clock uses time.Now.
+	clock func() time.Time
 }
 
 // Ensure PipelineReconciler implements reconcile.Reconciler at compile time
@@ -71,6 +189,15 @@ var _ reconcile.Reconciler = &PipelineReconciler{}
 // +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelines/finalizers,verbs=update
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinemodules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinefragments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinekillswitches,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=clusterpipelinepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -82,6 +209,9 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	log.Info("reconciling Pipeline", "namespace", req.Namespace, "name", req.Name)
 
+	// Fleet Management API requests of a namespace share a lane of the rate limiter
+	ctx = fleetclient.WithLane(ctx, req.Namespace)
+
 	// 1. Fetch the Pipeline resource
 	pipeline := &fleetmanagementv1alpha1.Pipeline{}
 	if err := r.Get(ctx, req.NamespacedName, pipeline); err != nil {
@@ -96,7 +226,7 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	// 2. Handle deletion
 	if !pipeline.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, pipeline)
+		return r.reconcileDelete(fleetclient.WithPriority(ctx, fleetclient.PriorityHigh), pipeline)
 	}
 
 	// 3. Add finalizer if not present
@@ -110,25 +240,164 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
-	// 4. Check if reconciliation is needed (observedGeneration pattern)
-	if pipeline.Status.ObservedGeneration == pipeline.Generation {
+	// 4. Delete debug pipelines whose TTL expired. The deletion goes through
+	// reconcileDelete like any other.
+	if r.debugExpired(pipeline) {
+		return r.deleteExpired(ctx, pipeline)
+	}
+
+	// 5. Leave paused pipelines alone. Resuming syncs the spec again, even if
+	// it did not change, to overwrite edits made in Fleet Management meanwhile.
+	if reason := r.pauseReason(pipeline); reason != "" {
+		result, err := r.reconcilePaused(ctx, pipeline, reason)
+		return r.requeueForDeadlines(pipeline, result, err)
+	}
+	resumed := markResumed(pipeline)
+
+	// 6. Check if reconciliation is needed (observedGeneration pattern).
+	// Pipelines rendered from templates, fragments or modules are also re-synced
+	// when those change, when a kill switch starts or stops selecting them, and
+	// when their schedule enables or disables them, their canary is promoted,
+	// their pending change is approved, policies start or stop blocking them or
+	// they are blocked by a quota, when their remote pipeline is due for a
+	// drift check, or when they were annotated to adopt a legacy source.
+	if pipeline.Status.ObservedGeneration == pipeline.Generation && !resumed &&
+		!r.dependenciesChanged(ctx, pipeline) && !credentialsBlocked(pipeline) && !quotaBlocked(pipeline) &&
+		!r.killSwitchChanged(ctx, pipeline) && !r.scheduleDue(pipeline) &&
+		!r.promotionDue(pipeline) && !approvalDue(pipeline) && !r.policyChanged(ctx, pipeline) &&
+		!r.driftCheckDue(pipeline) && !adoptionDue(pipeline) {
 		log.V(1).Info("pipeline already reconciled, skipping", "generation", pipeline.Generation)
-		return ctrl.Result{}, nil
+		result, err := r.refreshMatchedCollectors(fleetclient.WithPriority(ctx, fleetclient.PriorityLow), pipeline)
+		return r.requeueForDeadlines(pipeline, r.requeueForDriftCheck(pipeline, result), err)
 	}
 
-	// 5. Reconcile normal case
-	return r.reconcileNormal(ctx, pipeline)
+	// 7. Reconcile normal case
+	result, err := r.reconcileNormal(fleetclient.WithPriority(ctx, r.syncPriority(pipeline, resumed)), pipeline)
+	return r.requeueForDeadlines(pipeline, r.requeueForDriftCheck(pipeline, result), err)
+}
+
+// syncPriority returns the rate limiter priority of a sync. Changes made by
+// users to the Pipeline go ahead of re-syncs caused by other resources or time.
+func (r *PipelineReconciler) syncPriority(pipeline *fleetmanagementv1alpha1.Pipeline, resumed bool) fleetclient.Priority {
+	if pipeline.Status.ObservedGeneration != pipeline.Generation || resumed ||
+		r.promotionDue(pipeline) || approvalDue(pipeline) {
+		return fleetclient.PriorityHigh
+	}
+	return fleetclient.PriorityNormal
 }
 
 // reconcileNormal handles normal reconciliation (create/update)
 func (r *PipelineReconciler) reconcileNormal(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Render the contents from the referenced template, fragments and modules, if
+	// any, check them against policies, scan them for plaintext credentials, then
+	// substitute Secret values. The values must not reach status or logs.
+	contents, err := r.renderContents(ctx, pipeline)
+	if err == nil {
+		err = r.checkPolicies(ctx, pipeline, contents)
+	}
+	if err == nil {
+		err = r.scanCredentials(pipeline, contents)
+	}
+	if err == nil {
+		err = r.checkQuotas(ctx, pipeline, contents)
+	}
+	var secretValues []string
+	rendered := contents
+	if err == nil {
+		contents, secretValues, err = r.substituteSecrets(ctx, pipeline, contents)
+	}
+	if err != nil {
+		var renderErr *renderError
+		if errors.As(err, &renderErr) {
+			log.Info("failed to render pipeline contents", "error", err.Error())
+			return r.updateStatusError(ctx, pipeline, renderErr.reason, err)
+		}
+		log.Error(err, "failed to render pipeline contents")
+		return ctrl.Result{}, err
+	}
+
+	// Changes of gated pipelines wait for another user's approval
+	change := &approval.Change{Spec: &pipeline.Spec, Contents: rendered, Secrets: pipeline.Status.Secrets}
+	waiting, err := r.awaitingApproval(ctx, pipeline, change)
+	if err != nil {
+		log.Error(err, "failed to check approval")
+		return ctrl.Result{}, err
+	}
+	if waiting {
+		log.Info("change awaits approval", "hash", pipeline.Status.Approval.Pending.Hash)
+		return r.updateStatusWaiting(ctx, pipeline)
+	}
+
 	// Build the upsert request
-	req := r.buildUpsertRequest(pipeline)
+	req, err := r.buildUpsertRequest(pipeline, contents)
+	if err != nil {
+		log.Info("failed to build pipeline", "error", err.Error())
+		return r.updateStatusError(ctx, pipeline, reasonValidationError, err)
+	}
+
+	// The schedule and expiry narrow spec.enabled down to the current window
+	if err := r.applySchedule(pipeline, req.Pipeline); err != nil {
+		log.Info("failed to evaluate schedule", "error", err.Error())
+		return r.updateStatusError(ctx, pipeline, reasonValidationError, err)
+	}
+
+	// An engaged kill switch overrides spec.enabled and the schedule
+	if err := r.applyKillSwitch(ctx, pipeline, req.Pipeline); err != nil {
+		log.Error(err, "failed to evaluate kill switches")
+		return ctrl.Result{}, err
+	}
 
-	// Call Fleet Management API
-	apiPipeline, err := r.FleetClient.UpsertPipeline(ctx, req)
+	// Refuse to adopt or overwrite a remote pipeline that belongs to another
+	// cluster. Once synced with its source, the remote pipeline is not checked again.
+	var remote *fleetclient.Pipeline
+	if r.ClusterName != "" && !r.ownershipKnown(pipeline, req.Pipeline.Name) {
+		remote, err = r.remotePipeline(ctx, pipeline, req.Pipeline.Name)
+		if err != nil {
+			return r.handleAPIError(ctx, pipeline, err)
+		}
+		if remote != nil && !r.ownsRemotePipeline(pipeline, remote) {
+			log.Info("remote pipeline is owned by another source, not adopting",
+				"name", req.Pipeline.Name, "source", remote.Source.Namespace)
+			return r.updateStatusError(ctx, pipeline, reasonOwnershipConflict,
+				fmt.Errorf("pipeline %q already exists in Fleet Management with source %q", req.Pipeline.Name, remote.Source.Namespace))
+		}
+	}
+
+	// Contents changes go to the canary collectors first when a rollout
+	// strategy is set, leaving the main pipeline as is until promotion
+	canary, err := r.rolloutCanary(ctx, pipeline, req.Pipeline, rendered)
+	if err != nil {
+		return r.handleAPIError(ctx, pipeline, redactError(err, secretValues))
+	}
+	if canary {
+		return r.updateStatusWaiting(ctx, pipeline)
+	}
+
+	// Call Fleet Management API, unless it already holds this exact request
+	apiPipeline, err := r.upsertUnlessApplied(ctx, pipeline, req, rendered, remote)
 	if err != nil {
-		return r.handleAPIError(ctx, pipeline, err)
+		return r.handleAPIError(ctx, pipeline, redactError(err, secretValues))
+	}
+
+	if err := r.recordAppliedChange(ctx, pipeline, change); err != nil {
+		log.Error(err, "failed to record applied change")
+		return ctrl.Result{}, err
+	}
+
+	// The main pipeline now holds the contents of any canary
+	if err := r.deleteCanary(ctx, pipeline); err != nil {
+		log.Error(err, "failed to delete canary from Fleet Management", "canaryID", pipeline.Status.Rollout.CanaryID)
+	}
+
+	// A renamed pipeline is created under a new ID, so remove the one stored under the old name
+	if previousID := pipeline.Status.ID; previousID != "" && previousID != apiPipeline.ID {
+		log.Info("pipeline was renamed, deleting previous pipeline from Fleet Management",
+			"previousName", pipeline.Status.RemoteName, "previousID", previousID)
+		if err := r.FleetClient.DeletePipeline(ctx, previousID); err != nil && !isNotFound(err) {
+			log.Error(err, "failed to delete previous pipeline from Fleet Management", "previousID", previousID)
+		}
 	}
 
 	// Update status with successful sync
@@ -146,8 +415,27 @@ func (r *PipelineReconciler) reconcileDelete(ctx context.Context, pipeline *flee
 
 	log.Info("deleting Pipeline from Fleet Management", "id", pipeline.Status.ID)
 
+	// Never delete a remote pipeline that is paused or that another cluster has
+	// taken over. Expired debug pipelines are removed even when paused, since
+	// nobody may be left to clean them up.
+	deleteRemote := true
+	if reason := r.pauseReason(pipeline); reason != "" && !r.debugExpired(pipeline) {
+		log.Info("pipeline is paused, leaving it in Fleet Management", "reason", reason, "id", pipeline.Status.ID)
+		deleteRemote = false
+	} else if pipeline.Status.ID != "" && r.ClusterName != "" && !r.ownershipKnown(pipeline, pipeline.Status.RemoteName) {
+		owned, err := r.ownsPipelineID(ctx, pipeline)
+		if err != nil {
+			log.Error(err, "failed to check ownership of pipeline in Fleet Management")
+			return r.updateStatusError(ctx, pipeline, reasonDeleteFailed, err)
+		}
+		if !owned {
+			log.Info("remote pipeline is owned by another source, leaving it in place", "id", pipeline.Status.ID)
+			deleteRemote = false
+		}
+	}
+
 	// Delete from Fleet Management if we have an ID
-	if pipeline.Status.ID != "" {
+	if pipeline.Status.ID != "" && deleteRemote {
 		if err := r.FleetClient.DeletePipeline(ctx, pipeline.Status.ID); err != nil {
 			// Check if it's a 404 (already deleted)
 			if apiErr, ok := err.(*fleetclient.FleetAPIError); ok && apiErr.StatusCode == http.StatusNotFound {
@@ -160,6 +448,12 @@ func (r *PipelineReconciler) reconcileDelete(ctx context.Context, pipeline *flee
 			log.Info("successfully deleted pipeline from Fleet Management")
 		}
 	}
+	if deleteRemote {
+		if err := r.deleteCanary(ctx, pipeline); err != nil {
+			log.Error(err, "failed to delete canary from Fleet Management")
+			return r.updateStatusError(ctx, pipeline, reasonDeleteFailed, err)
+		}
+	}
 
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(pipeline, pipelineFinalizer)
@@ -172,36 +466,37 @@ func (r *PipelineReconciler) reconcileDelete(ctx context.Context, pipeline *flee
 	return ctrl.Result{}, nil
 }
 
-// buildUpsertRequest builds an UpsertPipelineRequest from a Pipeline CRD
-func (r *PipelineReconciler) buildUpsertRequest(pipeline *fleetmanagementv1alpha1.Pipeline) *fleetclient.UpsertPipelineRequest {
+// buildUpsertRequest builds an UpsertPipelineRequest from a Pipeline CRD and
+// the contents rendered for it
+func (r *PipelineReconciler) buildUpsertRequest(pipeline *fleetmanagementv1alpha1.Pipeline, contents string) (*fleetclient.UpsertPipelineRequest, error) {
 	// Determine pipeline name
-	pipelineName := pipeline.Spec.Name
-	if pipelineName == "" {
-		pipelineName = pipeline.Name
+	resolver := r.Naming
+	if resolver == nil {
+		resolver = &naming.Resolver{}
+	}
+	pipelineName, err := resolver.RemoteName(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate matchers before they reach Fleet Management, where a typo may
+	// be rejected or silently never match
+	pipelineMatchers, errs := matchers.FromSpec(&pipeline.Spec, field.NewPath("spec"))
+	if len(errs) > 0 {
+		return nil, errs.ToAggregate()
 	}
 
 	// Build the pipeline object
 	fleetPipeline := &fleetclient.Pipeline{
 		Name:       pipelineName,
-		Contents:   pipeline.Spec.Contents,
-		Matchers:   pipeline.Spec.Matchers,
+		Contents:   contents,
+		Matchers:   remoteMatchers(pipeline, pipelineMatchers),
 		Enabled:    pipeline.Spec.Enabled,
 		ConfigType: pipeline.Spec.ConfigType.ToFleetAPI(),
 	}
 
 	// Add source if specified, otherwise default to Kubernetes
-	if pipeline.Spec.Source != nil {
-		fleetPipeline.Source = &fleetclient.Source{
-			Type:      pipeline.Spec.Source.Type.ToFleetAPI(),
-			Namespace: pipeline.Spec.Source.Namespace,
-		}
-	} else {
-		// Default to Kubernetes source
-		fleetPipeline.Source = &fleetclient.Source{
-			Type:      fleetmanagementv1alpha1.SourceTypeKubernetes.ToFleetAPI(),
-			Namespace: fmt.Sprintf("%s/%s", pipeline.Namespace, pipeline.Name),
-		}
-	}
+	fleetPipeline.Source = r.pipelineSource(pipeline)
 
 	// Note: ID should NOT be included in UpsertPipeline requests.
 	// The API uses pipeline name for idempotency and assigns/returns the ID.
@@ -209,7 +504,71 @@ func (r *PipelineReconciler) buildUpsertRequest(pipeline *fleetmanagementv1alpha
 	return &fleetclient.UpsertPipelineRequest{
 		Pipeline:     fleetPipeline,
 		ValidateOnly: false,
+	}, nil
+}
+
+// findRemotePipeline looks up a pipeline in Fleet Management by name.
+// It returns nil if no pipeline with that name exists.
+func (r *PipelineReconciler) findRemotePipeline(ctx context.Context, name string) (*fleetclient.Pipeline, error) {
+	id, err := r.FleetClient.GetPipelineID(ctx, name)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := r.FleetClient.GetPipeline(ctx, id)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return remote, err
+}
+
+// remotePipeline returns the remote pipeline an upsert under name writes to:
+// the one recorded in status, unless it was deleted or renamed, or else the one
+// with that name. It returns nil if there is none.
+func (r *PipelineReconciler) remotePipeline(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, name string) (*fleetclient.Pipeline, error) {
+	if pipeline.Status.ID != "" && pipeline.Status.RemoteName == name {
+		remote, err := r.FleetClient.GetPipeline(ctx, pipeline.Status.ID)
+		if err == nil {
+			return remote, nil
+		}
+		if !isNotFound(err) {
+			return nil, err
+		}
+	}
+	return r.findRemotePipeline(ctx, name)
+}
+
+// ownsPipelineID reports whether the remote pipeline recorded in status still
+// belongs to this Pipeline. A pipeline that no longer exists counts as owned.
+func (r *PipelineReconciler) ownsPipelineID(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (bool, error) {
+	remote, err := r.FleetClient.GetPipeline(ctx, pipeline.Status.ID)
+	if isNotFound(err) {
+		return true, nil
 	}
+	if err != nil {
+		return false, err
+	}
+	return r.ownsRemotePipeline(pipeline, remote), nil
+}
+
+// isNotFound reports whether err is a 404 from the Fleet Management API
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*fleetclient.FleetAPIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// remoteMatchers returns the matchers sent to Fleet Management: spec.matchers
+// verbatim followed by spec.structuredMatchers and the debug collector matcher
+// in string form
+func remoteMatchers(pipeline *fleetmanagementv1alpha1.Pipeline, parsed matchers.Matchers) []string {
+	if len(parsed) == len(pipeline.Spec.Matchers) {
+		return pipeline.Spec.Matchers
+	}
+	structured := parsed[len(pipeline.Spec.Matchers):]
+	return append(slices.Clone(pipeline.Spec.Matchers), structured.Strings()...)
 }
 
 // handleAPIError handles errors from Fleet Management API
@@ -257,13 +616,17 @@ func (r *PipelineReconciler) updateStatusSuccess(ctx context.Context, pipeline *
 
 	// Update status fields
 	pipeline.Status.ID = apiPipeline.ID
+	pipeline.Status.RemoteName = apiPipeline.Name
 	pipeline.Status.ObservedGeneration = pipeline.Generation
 
 	if apiPipeline.CreatedAt != nil {
 		pipeline.Status.CreatedAt = &metav1.Time{Time: *apiPipeline.CreatedAt}
 	}
+	// Skipped syncs return the remote pipeline as last recorded in status,
+	// without its timestamps
 	if apiPipeline.UpdatedAt != nil {
 		pipeline.Status.UpdatedAt = &metav1.Time{Time: *apiPipeline.UpdatedAt}
+		pipeline.Status.RevisionID = pipelineRevision(apiPipeline)
 	}
 
 	// Set Ready condition
@@ -284,6 +647,11 @@ func (r *PipelineReconciler) updateStatusSuccess(ctx context.Context, pipeline *
 		ObservedGeneration: pipeline.Generation,
 	})
 
+	// Record matched collectors. Listing failures must not fail the sync.
+	if err := r.setMatchedCollectors(ctx, pipeline); err != nil {
+		log.Error(err, "failed to evaluate matched collectors")
+	}
+
 	// Update status
 	if err := r.Status().Update(ctx, pipeline); err != nil {
 		if apierrors.IsConflict(err) {
@@ -296,6 +664,9 @@ func (r *PipelineReconciler) updateStatusSuccess(ctx context.Context, pipeline *
 	}
 
 	log.Info("successfully synced pipeline", "id", apiPipeline.ID, "generation", pipeline.Generation)
+	if r.collectors != nil {
+		return ctrl.Result{RequeueAfter: r.collectorRefreshInterval()}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -334,9 +705,10 @@ func (r *PipelineReconciler) updateStatusError(ctx context.Context, pipeline *fl
 		return ctrl.Result{}, updateErr
 	}
 
-	// For validation errors, don't retry immediately
-	if reason == reasonValidationError {
-		log.Info("validation error, not requeueing", "error", err.Error())
+	// For validation, ownership and render errors, don't retry immediately
+	switch reason {
+	case reasonValidationError, reasonOwnershipConflict, reasonTemplateError, reasonModuleError, reasonFragmentError, reasonSecretError, reasonCredentialsDetected, reasonPolicyViolation:
+		log.Info("pipeline cannot be synced, not requeueing", "reason", reason, "error", err.Error())
 		return ctrl.Result{}, nil
 	}
 
@@ -344,10 +716,64 @@ func (r *PipelineReconciler) updateStatusError(ctx context.Context, pipeline *fl
 	return ctrl.Result{}, err
 }
 
+// updateStatusWaiting records a change held back by a canary or an approval.
+// The main pipeline, and so the Ready and Synced conditions, are unchanged.
+func (r *PipelineReconciler) updateStatusWaiting(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	pipeline.Status.ObservedGeneration = pipeline.Generation
+	if err := r.Status().Update(ctx, pipeline); err != nil {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("status update conflict, requeueing")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *PipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.CollectorClient != nil {
+		r.collectors = &collectorCache{client: r.CollectorClient, interval: r.collectorRefreshInterval()}
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
+		&fleetmanagementv1alpha1.Pipeline{}, templateRefIndex, indexTemplateRef); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
+		&fleetmanagementv1alpha1.Pipeline{}, modulesIndex, indexModules); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
+		&fleetmanagementv1alpha1.Pipeline{}, secretRefsIndex, indexSecretRefs); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fleetmanagementv1alpha1.Pipeline{}).
+		Watches(&fleetmanagementv1alpha1.PipelineTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesReferencing(templateRefIndex))).
+		Watches(&fleetmanagementv1alpha1.PipelineModule{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesReferencing(modulesIndex))).
+		Watches(&fleetmanagementv1alpha1.PipelineFragment{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesForFragment)).
+		Watches(&fleetmanagementv1alpha1.PipelineKillSwitch{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesForKillSwitch)).
+		Watches(&fleetmanagementv1alpha1.PipelinePolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesForPolicy)).
+		Watches(&fleetmanagementv1alpha1.ClusterPipelinePolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesForPolicy)).
+		// Raised limits unblock Pipelines; usage updates in status do not
+		Watches(&fleetmanagementv1alpha1.PipelineQuota{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesForQuota),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Only Secret metadata is cached; the data is read when substituting
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.pipelinesReferencing(secretRefsIndex)),
+			builder.OnlyMetadata).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Named("pipeline").
 		Complete(r)
 }
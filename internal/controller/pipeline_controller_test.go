@@ -18,26 +18,69 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/approval"
+	"github.com/grafana/fleet-management-operator/pkg/credscan"
 	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+	"github.com/grafana/fleet-management-operator/pkg/naming"
+	"github.com/grafana/fleet-management-operator/pkg/schedule"
 )
 
+// laneRecordingClient upserts through a rate limiter, recording the namespace
+// of each pipeline in the order the limiter serves them
+type laneRecordingClient struct {
+	*mockFleetClient
+	limiter fleetclient.Limiter
+
+	mu         sync.Mutex
+	namespaces []string
+}
+
+func (c *laneRecordingClient) UpsertPipeline(ctx context.Context, req *fleetclient.UpsertPipelineRequest) (*fleetclient.Pipeline, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	namespace, _, _ := strings.Cut(req.Pipeline.Source.Namespace, "/")
+	c.namespaces = append(c.namespaces, namespace)
+	return &fleetclient.Pipeline{ID: "id-" + req.Pipeline.Source.Namespace, Name: req.Pipeline.Name}, nil
+}
+
+func (c *laneRecordingClient) served() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.namespaces)
+}
+
 // Mock Fleet Management API client
 type mockFleetClient struct {
 	pipelines         map[string]*fleetclient.Pipeline
 	upsertError       error
 	deleteError       error
 	callCount         int
+	getCount          int
 	lastUpsertRequest *fleetclient.UpsertPipelineRequest
 	shouldReturn404   bool
 	shouldReturn400   bool
@@ -96,6 +139,31 @@ func (m *mockFleetClient) UpsertPipeline(ctx context.Context, req *fleetclient.U
 	return req.Pipeline, nil
 }
 
+func (m *mockFleetClient) GetPipeline(ctx context.Context, id string) (*fleetclient.Pipeline, error) {
+	m.getCount++
+	if p, ok := m.pipelines[id]; ok {
+		return p, nil
+	}
+	return nil, &fleetclient.FleetAPIError{
+		StatusCode: http.StatusNotFound,
+		Operation:  "GetPipeline",
+		Message:    "pipeline not found",
+	}
+}
+
+func (m *mockFleetClient) GetPipelineID(ctx context.Context, name string) (string, error) {
+	for id, p := range m.pipelines {
+		if p.Name == name {
+			return id, nil
+		}
+	}
+	return "", &fleetclient.FleetAPIError{
+		StatusCode: http.StatusNotFound,
+		Operation:  "GetPipelineID",
+		Message:    "pipeline not found",
+	}
+}
+
 func (m *mockFleetClient) DeletePipeline(ctx context.Context, id string) error {
 	if m.shouldReturn404 {
 		return &fleetclient.FleetAPIError{
@@ -113,6 +181,17 @@ func (m *mockFleetClient) DeletePipeline(ctx context.Context, id string) error {
 	return nil
 }
 
+// mockCollectorClient is a mock implementation of FleetCollectorClient for testing
+type mockCollectorClient struct {
+	collectors []*fleetclient.Collector
+	calls      int
+}
+
+func (m *mockCollectorClient) ListCollectors(ctx context.Context, req *fleetclient.ListCollectorsRequest) ([]*fleetclient.Collector, error) {
+	m.calls++
+	return m.collectors, nil
+}
+
 var _ = Describe("Pipeline Controller", func() {
 	Context("When reconciling a Pipeline", func() {
 		const (
@@ -301,7 +380,8 @@ var _ = Describe("Pipeline Controller", func() {
 				},
 			}
 
-			req := reconciler.buildUpsertRequest(pipeline)
+			req, err := reconciler.buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
 			Expect(req.Pipeline.Name).To(Equal("test-pipeline"))
 		})
 
@@ -320,7 +400,8 @@ var _ = Describe("Pipeline Controller", func() {
 				},
 			}
 
-			req := reconciler.buildUpsertRequest(pipeline)
+			req, err := reconciler.buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
 			Expect(req.Pipeline.Name).To(Equal("custom-pipeline-name"))
 		})
 
@@ -337,9 +418,1359 @@ var _ = Describe("Pipeline Controller", func() {
 				},
 			}
 
-			req := reconciler.buildUpsertRequest(pipeline)
+			req, err := reconciler.buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
 			Expect(req.Pipeline.ConfigType).To(Equal("CONFIG_TYPE_OTEL"))
 		})
+
+		It("should include the cluster name in the default source namespace", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "test",
+				},
+			}
+
+			req, err := (&PipelineReconciler{}).buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(req.Pipeline.Source.Namespace).To(Equal("default/test"))
+
+			req, err = (&PipelineReconciler{ClusterName: "prod-eu"}).buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(req.Pipeline.Source.Namespace).To(Equal("prod-eu/default/test"))
+		})
+
+		It("should use the configured naming strategy", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "k8s-pipeline",
+					Namespace: "team-a",
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "test",
+				},
+			}
+
+			reconciler := &PipelineReconciler{
+				ClusterName: "prod",
+				Naming: &naming.Resolver{
+					ClusterName:     "prod",
+					Affix:           naming.AffixClusterPrefix,
+					DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyNamespacedName,
+				},
+			}
+			req, err := reconciler.buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(req.Pipeline.Name).To(Equal("prod_team_a_k8s_pipeline_51cd112e"))
+		})
+
+		It("should fail to build a request when the name template is invalid", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents:       "test",
+					NamingStrategy: fleetmanagementv1alpha1.NamingStrategyTemplate,
+					NameTemplate:   "{{ .Missing",
+				},
+			}
+
+			_, err := (&PipelineReconciler{}).buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should append structured matchers to string matchers", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "test",
+					Matchers: []string{"collector.os=linux"},
+					StructuredMatchers: []fleetmanagementv1alpha1.Matcher{
+						{Key: "owner", Op: fleetmanagementv1alpha1.MatchOperatorEqual, Value: "platform team"},
+						{Key: "region", Op: fleetmanagementv1alpha1.MatchOperatorRegexp, Value: "us-.*"},
+					},
+				},
+			}
+
+			req, err := (&PipelineReconciler{}).buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(req.Pipeline.Matchers).To(Equal([]string{"collector.os=linux", `owner="platform team"`, "region=~us-.*"}))
+		})
+
+		It("should reject invalid matchers", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "test",
+					Matchers: []string{"env=~[prod"},
+				},
+			}
+
+			_, err := (&PipelineReconciler{}).buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).To(MatchError(ContainSubstring("spec.matchers[0]")))
+		})
+
+		It("should only own remote pipelines with a matching Kubernetes source", func() {
+			r := &PipelineReconciler{ClusterName: "prod"}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			}
+
+			Expect(r.ownsRemotePipeline(pipeline, &fleetclient.Pipeline{
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_KUBERNETES", Namespace: "prod/default/test"},
+			})).To(BeTrue())
+			Expect(r.ownsRemotePipeline(pipeline, &fleetclient.Pipeline{
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_KUBERNETES", Namespace: "staging/default/test"},
+			})).To(BeFalse())
+			Expect(r.ownsRemotePipeline(pipeline, &fleetclient.Pipeline{
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_TERRAFORM", Namespace: "workspace"},
+			})).To(BeTrue())
+			Expect(r.ownsRemotePipeline(pipeline, &fleetclient.Pipeline{})).To(BeTrue())
+		})
+
+		It("should only adopt remote pipelines synced before the cluster name was set when asked to", func() {
+			r := &PipelineReconciler{ClusterName: "prod"}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			}
+			legacy := &fleetclient.Pipeline{
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_KUBERNETES", Namespace: "default/test"},
+			}
+
+			Expect(r.ownsRemotePipeline(pipeline, legacy)).To(BeFalse())
+
+			pipeline.Annotations = map[string]string{fleetmanagementv1alpha1.AdoptLegacySourceAnnotation: "true"}
+			Expect(r.ownsRemotePipeline(pipeline, legacy)).To(BeTrue())
+			Expect(r.ownsRemotePipeline(pipeline, &fleetclient.Pipeline{
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_KUBERNETES", Namespace: "default/other"},
+			})).To(BeFalse())
+
+			By("reporting the conflict before the annotation was set")
+			pipeline.Status.Conditions = []metav1.Condition{{Type: conditionTypeReady, Status: metav1.ConditionFalse, Reason: reasonOwnershipConflict}}
+			Expect(adoptionDue(pipeline)).To(BeTrue())
+
+			By("syncing the pipeline with the cluster")
+			pipeline.Status.SourceNamespace = "prod/default/test"
+			Expect(r.ownsRemotePipeline(pipeline, legacy)).To(BeFalse())
+		})
+
+		It("should not check the ownership of pipelines synced with their source again", func() {
+			mock := newMockFleetClient()
+			mock.pipelines["remote-id"] = &fleetclient.Pipeline{ID: "remote-id", Name: "test"}
+			r := &PipelineReconciler{
+				Client:      fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).Build(),
+				FleetClient: mock,
+				ClusterName: "prod",
+			}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: fleetmanagementv1alpha1.PipelineStatus{
+					ID: "remote-id", RemoteName: "test", SourceNamespace: "prod/default/test",
+				},
+			}
+
+			Expect(r.ownershipKnown(pipeline, "test")).To(BeTrue())
+			Expect(r.ownershipKnown(pipeline, "renamed")).To(BeFalse())
+
+			By("deleting the Pipeline")
+			pipeline.Finalizers = []string{pipelineFinalizer}
+			Expect(r.Create(context.Background(), pipeline)).To(Succeed())
+			_, err := r.reconcileDelete(context.Background(), pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.getCount).To(BeZero())
+			Expect(mock.pipelines).ToNot(HaveKey("remote-id"))
+
+			By("changing the source of the Pipeline")
+			pipeline.Spec.Source = &fleetmanagementv1alpha1.PipelineSource{Type: fleetmanagementv1alpha1.SourceTypeGit, Namespace: "repo"}
+			Expect(r.ownershipKnown(pipeline, "test")).To(BeFalse())
+		})
+
+		It("should check the ownership of pipelines already synced", func() {
+			mock := newMockFleetClient()
+			mock.pipelines["remote-id"] = &fleetclient.Pipeline{
+				ID:     "remote-id",
+				Name:   "test",
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_KUBERNETES", Namespace: "staging/default/test"},
+			}
+			r := &PipelineReconciler{FleetClient: mock, ClusterName: "prod"}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status:     fleetmanagementv1alpha1.PipelineStatus{ID: "remote-id", RemoteName: "test"},
+			}
+
+			remote, err := r.remotePipeline(context.Background(), pipeline, "test")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(remote).ToNot(BeNil())
+			Expect(r.ownsRemotePipeline(pipeline, remote)).To(BeFalse())
+		})
+	})
+
+	Context("When rendering contents from a PipelineTemplate", func() {
+		ctx := context.Background()
+
+		var tmpl *fleetmanagementv1alpha1.PipelineTemplate
+
+		BeforeEach(func() {
+			tmpl = &fleetmanagementv1alpha1.PipelineTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-write", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineTemplateSpec{
+					Contents: "prometheus.remote_write \"{{ .Pipeline.Name }}\" { endpoint { url = {{ quote .Params.url }} } }",
+					Parameters: []fleetmanagementv1alpha1.TemplateParameter{
+						{Name: "url", Type: fleetmanagementv1alpha1.ParameterTypeString, Required: true},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, tmpl)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, tmpl))).To(Succeed())
+		})
+
+		newPipeline := func(params map[string]apiextensionsv1.JSON) *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "team_a", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					TemplateRef: &fleetmanagementv1alpha1.TemplateReference{Name: "remote-write"},
+					Parameters:  params,
+				},
+			}
+		}
+
+		It("should render the template and record it in status", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline(map[string]apiextensionsv1.JSON{"url": {Raw: []byte(`"https://prom.example.com"`)}})
+
+			contents, err := r.renderContents(ctx, pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal(`prometheus.remote_write "team_a" { endpoint { url = "https://prom.example.com" } }`))
+			Expect(pipeline.Status.Template).ToNot(BeNil())
+			Expect(pipeline.Status.Template.Generation).To(Equal(tmpl.Generation))
+			Expect(r.templateChanged(ctx, pipeline)).To(BeFalse())
+
+			By("editing the template")
+			tmpl.Spec.Contents += "\n"
+			Expect(k8sClient.Update(ctx, tmpl)).To(Succeed())
+			Eventually(func() bool { return r.templateChanged(ctx, pipeline) }).Should(BeTrue())
+		})
+
+		It("should return a render error for invalid parameters", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline(nil)
+
+			_, err := r.renderContents(ctx, pipeline)
+			var renderErr *renderError
+			Expect(err).To(BeAssignableToTypeOf(renderErr))
+			Expect(err).To(MatchError(ContainSubstring("spec.parameters[url]")))
+		})
+
+		It("should return a render error for a missing template", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline(nil)
+			pipeline.Spec.TemplateRef.Name = "missing"
+
+			_, err := r.renderContents(ctx, pipeline)
+			Expect(err).To(MatchError(ContainSubstring("not found")))
+			Expect(pipeline.Status.Template).To(BeNil())
+		})
+	})
+
+	Context("When merging PipelineFragments", func() {
+		ctx := context.Background()
+
+		var exporters, scrape *fleetmanagementv1alpha1.PipelineFragment
+
+		BeforeEach(func() {
+			exporters = &fleetmanagementv1alpha1.PipelineFragment{
+				ObjectMeta: metav1.ObjectMeta{Name: "exporters", Namespace: "default", Labels: map[string]string{"pipeline": "shared"}},
+				Spec:       fleetmanagementv1alpha1.PipelineFragmentSpec{Contents: "prometheus.remote_write \"default\" { }"},
+			}
+			scrape = &fleetmanagementv1alpha1.PipelineFragment{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-scrape", Namespace: "default", Labels: map[string]string{"pipeline": "shared"}},
+				Spec:       fleetmanagementv1alpha1.PipelineFragmentSpec{Contents: "prometheus.scrape \"app\" { }", Order: 10},
+			}
+			Expect(k8sClient.Create(ctx, exporters)).To(Succeed())
+			Expect(k8sClient.Create(ctx, scrape)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, exporters))).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, scrape))).To(Succeed())
+		})
+
+		newPipeline := func() *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					FragmentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pipeline": "shared"}},
+				},
+			}
+		}
+
+		It("should merge fragments by order and record them in status", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline()
+
+			contents, err := r.renderContents(ctx, pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal("// Fragment: exporters\nprometheus.remote_write \"default\" { }\n\n" +
+				"// Fragment: app-scrape\nprometheus.scrape \"app\" { }\n"))
+			Expect(pipeline.Status.Fragments).To(HaveLen(2))
+			Expect(pipeline.Status.Fragments[0].Name).To(Equal("exporters"))
+			Expect(r.fragmentsChanged(ctx, pipeline)).To(BeFalse())
+
+			By("editing a fragment")
+			scrape.Spec.Contents = "prometheus.scrape \"other\" { }"
+			Expect(k8sClient.Update(ctx, scrape)).To(Succeed())
+			Eventually(func() bool { return r.fragmentsChanged(ctx, pipeline) }).Should(BeTrue())
+		})
+
+		It("should return a render error when no fragment is selected", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline()
+			pipeline.Spec.FragmentSelector.MatchLabels["pipeline"] = "none"
+
+			_, err := r.renderContents(ctx, pipeline)
+			var renderErr *renderError
+			Expect(err).To(BeAssignableToTypeOf(renderErr))
+			Expect(err).To(MatchError(ContainSubstring("matches no PipelineFragments")))
+		})
+	})
+
+	Context("When substituting Secret values", func() {
+		ctx := context.Background()
+
+		var secret *corev1.Secret
+
+		BeforeEach(func() {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "prom", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("s3cr3t")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+		})
+
+		newPipeline := func() *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "secret_pipeline", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents:   `password = "${secret:prom/password}"`,
+					SecretRefs: []fleetmanagementv1alpha1.SecretReference{{Name: "prom"}},
+				},
+			}
+		}
+
+		It("should substitute values and record only the Secret version", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline()
+
+			contents, values, err := r.substituteSecrets(ctx, pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal(`password = "s3cr3t"`))
+			Expect(values).To(ConsistOf("s3cr3t"))
+			Expect(pipeline.Status.Secrets).To(HaveLen(1))
+			Expect(pipeline.Status.Secrets[0].ResourceVersion).To(Equal(secret.ResourceVersion))
+			Expect(r.secretsChanged(ctx, pipeline)).To(BeFalse())
+
+			By("rotating the Secret")
+			secret.Data["password"] = []byte("rotated")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			Eventually(func() bool { return r.secretsChanged(ctx, pipeline) }).Should(BeTrue())
+		})
+
+		It("should escape values for the config type", func() {
+			quoted := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "quoted", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte(`a"b$c`)},
+			}
+			r := &PipelineReconciler{Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(quoted).Build()}
+			pipeline := newPipeline()
+			pipeline.Spec.Contents = `password: "${secret:quoted/password}"`
+			pipeline.Spec.SecretRefs = []fleetmanagementv1alpha1.SecretReference{{Name: "quoted"}}
+			pipeline.Spec.ConfigType = fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector
+
+			contents, values, err := r.substituteSecrets(ctx, pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal(`password: "a\"b$$c"`))
+			Expect(values).To(ConsistOf(`a\"b$$c`, `a"b$c`))
+
+			pipeline.Spec.ConfigType = fleetmanagementv1alpha1.ConfigTypeAlloy
+			contents, _, err = r.substituteSecrets(ctx, pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal(`password: "a\"b$c"`))
+		})
+
+		It("should not leak values in errors", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline()
+			pipeline.Spec.Contents += ` token = "${secret:prom/token}"`
+
+			_, _, err := r.substituteSecrets(ctx, pipeline, pipeline.Spec.Contents)
+			var renderErr *renderError
+			Expect(err).To(BeAssignableToTypeOf(renderErr))
+			Expect(err).To(MatchError("unresolved Secret references: prom/token"))
+
+			apiErr := redactError(&fleetclient.FleetAPIError{StatusCode: 400, Message: `invalid value "s3cr3t"`}, []string{"s3cr3t"})
+			Expect(apiErr).To(BeAssignableToTypeOf(&fleetclient.FleetAPIError{}))
+			Expect(apiErr).To(MatchError(`invalid value "[REDACTED]"`))
+		})
+	})
+
+	Context("When scanning rendered contents for credentials", func() {
+		newPipeline := func() *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "leaky", Namespace: "default", Generation: 2},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Contents: `password = "hunter2"`},
+			}
+		}
+
+		It("should only report findings in warn mode", func() {
+			r := &PipelineReconciler{CredentialScan: credscan.ModeWarn}
+			pipeline := newPipeline()
+
+			Expect(r.scanCredentials(pipeline, pipeline.Spec.Contents)).To(Succeed())
+			condition := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeCredentialsDetected)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("line 1"))
+			Expect(condition.Message).ToNot(ContainSubstring("hunter2"))
+		})
+
+		It("should block the sync in deny mode unless allowed", func() {
+			r := &PipelineReconciler{CredentialScan: credscan.ModeDeny}
+			pipeline := newPipeline()
+
+			err := r.scanCredentials(pipeline, pipeline.Spec.Contents)
+			var renderErr *renderError
+			Expect(err).To(BeAssignableToTypeOf(renderErr))
+
+			pipeline.Annotations = map[string]string{credscan.AllowAnnotation: "*"}
+			Expect(r.scanCredentials(pipeline, pipeline.Spec.Contents)).To(Succeed())
+		})
+	})
+
+	Context("When a Pipeline is paused", func() {
+		newPipeline := func() *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "default", Labels: map[string]string{"team": "a"}},
+			}
+		}
+
+		It("should be paused by the annotation or the operator selector", func() {
+			r := &PipelineReconciler{}
+			pipeline := newPipeline()
+			Expect(r.pauseReason(pipeline)).To(BeEmpty())
+
+			pipeline.Annotations = map[string]string{fleetmanagementv1alpha1.PausedAnnotation: "true"}
+			Expect(r.pauseReason(pipeline)).To(Equal(reasonPausedByAnnotation))
+
+			selector, err := labels.Parse("team=a")
+			Expect(err).ToNot(HaveOccurred())
+			r.PausedSelector = selector
+			pipeline.Annotations = nil
+			Expect(r.pauseReason(pipeline)).To(Equal(reasonPausedBySelector))
+		})
+
+		It("should be synced again once resumed", func() {
+			pipeline := newPipeline()
+			Expect(markResumed(pipeline)).To(BeFalse())
+
+			meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+				Type: conditionTypePaused, Status: metav1.ConditionTrue, Reason: reasonPausedByAnnotation,
+			})
+			Expect(markResumed(pipeline)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(pipeline.Status.Conditions, conditionTypePaused)).To(BeTrue())
+			Expect(markResumed(pipeline)).To(BeFalse())
+		})
+	})
+
+	Context("When a PipelineKillSwitch is engaged", func() {
+		ctx := context.Background()
+
+		var ks *fleetmanagementv1alpha1.PipelineKillSwitch
+
+		BeforeEach(func() {
+			ks = &fleetmanagementv1alpha1.PipelineKillSwitch{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics-flood"},
+				Spec: fleetmanagementv1alpha1.PipelineKillSwitchSpec{
+					Selector:          &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					RemoteNamePattern: "team_a_.*",
+					Reason:            "INC-42",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ks)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, ks))).To(Succeed())
+		})
+
+		newPipeline := func(labels map[string]string) *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default", Labels: labels},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Enabled: true},
+			}
+		}
+
+		It("should disable selected pipelines and restore them once removed", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline(map[string]string{"team": "a"})
+			remote := &fleetclient.Pipeline{Name: "team_a_metrics", Enabled: true}
+
+			Eventually(func() bool {
+				Expect(r.applyKillSwitch(ctx, pipeline, remote)).To(Succeed())
+				return remote.Enabled
+			}).Should(BeFalse())
+			Expect(pipeline.Status.KillSwitch).To(Equal("metrics-flood"))
+			condition := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeKillSwitchEngaged)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).To(ContainSubstring("INC-42"))
+
+			By("removing the kill switch")
+			Expect(k8sClient.Delete(ctx, ks)).To(Succeed())
+			pipeline.Status.RemoteName = remote.Name
+			Eventually(func() bool { return r.killSwitchChanged(ctx, pipeline) }).Should(BeTrue())
+
+			remote.Enabled = pipeline.Spec.Enabled
+			Expect(r.applyKillSwitch(ctx, pipeline, remote)).To(Succeed())
+			Expect(remote.Enabled).To(BeTrue())
+			Expect(pipeline.Status.KillSwitch).To(BeEmpty())
+		})
+
+		It("should require both the selector and the pattern to match", func() {
+			matched, err := killSwitchSelects(ks, newPipeline(map[string]string{"team": "b"}), "team_a_metrics")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matched).To(BeFalse())
+
+			matched, err = killSwitchSelects(ks, newPipeline(map[string]string{"team": "a"}), "team_b_metrics")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(matched).To(BeFalse())
+		})
+	})
+
+	Context("When a debug Pipeline targets one collector", func() {
+		ctx := context.Background()
+
+		It("should target the collector under a unique name and be deleted after the TTL", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "debug-host", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "logging { level = \"debug\" }",
+					Enabled:  true,
+					Matchers: []string{"env=prod"},
+					Debug: &fleetmanagementv1alpha1.DebugTarget{
+						CollectorID: "host-1",
+						TTL:         metav1.Duration{Duration: time.Hour},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pipeline)).To(Succeed())
+
+			created := pipeline.CreationTimestamp.Time
+			r := &PipelineReconciler{Client: k8sClient, clock: func() time.Time { return created.Add(time.Minute) }}
+			req, err := r.buildUpsertRequest(pipeline, pipeline.Spec.Contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(req.Pipeline.Name).To(HavePrefix("debug_host_debug_"))
+			Expect(req.Pipeline.Matchers).To(Equal([]string{"env=prod", "collector.ID=host-1"}))
+			Expect(r.debugExpired(pipeline)).To(BeFalse())
+
+			result, err := r.requeueForDeadlines(pipeline, ctrl.Result{}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(59 * time.Minute))
+
+			By("expiring the TTL")
+			r.clock = func() time.Time { return created.Add(time.Hour) }
+			Expect(r.debugExpired(pipeline)).To(BeTrue())
+			_, err = r.deleteExpired(ctx, pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(pipeline), pipeline))
+			}).Should(BeTrue())
+		})
+
+		It("should delete the remote pipeline of an expired debug pipeline even when paused", func() {
+			created := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+			deleted := metav1.NewTime(created.Add(time.Hour))
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "debug-paused",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(created),
+					DeletionTimestamp: &deleted,
+					Finalizers:        []string{pipelineFinalizer},
+					Annotations:       map[string]string{fleetmanagementv1alpha1.PausedAnnotation: "true"},
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "logging { }",
+					Debug: &fleetmanagementv1alpha1.DebugTarget{
+						CollectorID: "host-1",
+						TTL:         metav1.Duration{Duration: time.Hour},
+					},
+				},
+				Status: fleetmanagementv1alpha1.PipelineStatus{ID: "debug-id"},
+			}
+			fleet := newMockFleetClient()
+			fleet.pipelines["debug-id"] = &fleetclient.Pipeline{ID: "debug-id"}
+			r := &PipelineReconciler{
+				Client:      fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pipeline).Build(),
+				FleetClient: fleet,
+				clock:       func() time.Time { return deleted.Time },
+			}
+
+			_, err := r.reconcileDelete(ctx, pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fleet.pipelines).ToNot(HaveKey("debug-id"))
+
+			By("deleting a paused debug pipeline before its TTL")
+			r.clock = func() time.Time { return created.Add(time.Minute) }
+			fleet.pipelines["debug-id"] = &fleetclient.Pipeline{ID: "debug-id"}
+			pipeline.Finalizers = []string{pipelineFinalizer}
+			pipeline.ResourceVersion = ""
+			r.Client = fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pipeline).Build()
+			_, err = r.reconcileDelete(ctx, pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fleet.pipelines).To(HaveKey("debug-id"))
+		})
+	})
+
+	Context("When rolling out contents through a canary", func() {
+		ctx := context.Background()
+
+		It("should deploy a canary and promote it after the bake time", func() {
+			start := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+			fleet := newMockFleetClient()
+			fleet.pipelines["main"] = &fleetclient.Pipeline{ID: "main", Name: "metrics", Contents: "old", Matchers: []string{"env=prod"}}
+			r := &PipelineReconciler{FleetClient: fleet, clock: func() time.Time { return start }}
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "new",
+					Enabled:  true,
+					Rollout: &fleetmanagementv1alpha1.RolloutStrategy{
+						CanaryMatchers: []string{"canary=true"},
+						BakeTime:       &metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+				Status: fleetmanagementv1alpha1.PipelineStatus{ID: "main"},
+			}
+			remote := func() *fleetclient.Pipeline {
+				return &fleetclient.Pipeline{Name: "metrics", Contents: "new", Matchers: []string{"env=prod"}, Enabled: true}
+			}
+
+			canary, err := r.rolloutCanary(ctx, pipeline, remote(), "new")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(canary).To(BeTrue())
+			Expect(fleet.pipelines["mock-id-123"].Name).To(Equal("metrics_canary"))
+			Expect(fleet.pipelines["mock-id-123"].Matchers).To(Equal([]string{"env=prod", "canary=true"}))
+			Expect(fleet.pipelines["main"].Contents).To(Equal("old"))
+			Expect(fleet.pipelines["main"].Matchers).To(Equal([]string{"env=prod", "canary!=true"}))
+			Expect(pipeline.Status.Rollout.StableHash).ToNot(Equal(contentsHash("old", nil)))
+			Expect(pipeline.Status.Rollout.CanaryHash).To(Equal(contentsHash("new", nil)))
+			condition := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeRolloutProgressing)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Reason).To(Equal(reasonCanaryDeployed))
+			Expect(r.promotionDue(pipeline)).To(BeFalse())
+
+			deadline, ok := nextDeadline(pipeline)
+			Expect(ok).To(BeTrue())
+			Expect(deadline).To(Equal(start.Add(30 * time.Minute)))
+
+			By("waiting for the bake time")
+			r.clock = func() time.Time { return start.Add(30 * time.Minute) }
+			Expect(r.promotionDue(pipeline)).To(BeTrue())
+			canary, err = r.rolloutCanary(ctx, pipeline, remote(), "new")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(canary).To(BeFalse())
+			Expect(pipeline.Status.Rollout.StableHash).To(Equal(contentsHash("new", nil)))
+			condition = meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeRolloutProgressing)
+			Expect(condition.Reason).To(Equal(reasonPromoted))
+
+			Expect(r.deleteCanary(ctx, pipeline)).To(Succeed())
+			Expect(fleet.pipelines).ToNot(HaveKey("mock-id-123"))
+			Expect(pipeline.Status.Rollout.CanaryID).To(BeEmpty())
+		})
+
+		It("should promote a canary approved by annotation", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Rollout: &fleetmanagementv1alpha1.RolloutStrategy{CanaryMatchers: []string{"canary=true"}},
+				},
+				Status: fleetmanagementv1alpha1.PipelineStatus{
+					Rollout: &fleetmanagementv1alpha1.RolloutStatus{StableHash: "a", CanaryHash: "b"},
+				},
+			}
+			r := &PipelineReconciler{}
+			Expect(r.promotionDue(pipeline)).To(BeFalse())
+
+			pipeline.Annotations = map[string]string{fleetmanagementv1alpha1.PromoteAnnotation: "a"}
+			Expect(r.promotionDue(pipeline)).To(BeFalse())
+			pipeline.Annotations[fleetmanagementv1alpha1.PromoteAnnotation] = "b"
+			Expect(r.promotionDue(pipeline)).To(BeTrue())
+		})
+	})
+
+	Context("When Pipeline changes require approval", func() {
+		ctx := context.Background()
+
+		It("should hold a change until another user approves it", func() {
+			gate, err := approval.ParseGate("prod", "")
+			Expect(err).ToNot(HaveOccurred())
+			r := &PipelineReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).Build(),
+				Approval: gate,
+			}
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "metrics",
+					Namespace:   "prod",
+					UID:         "uid-1",
+					Generation:  2,
+					Annotations: map[string]string{fleetmanagementv1alpha1.ChangedByAnnotation: "alice"},
+				},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{Contents: "old", Enabled: true},
+			}
+			pipeline.Status.Approval = &fleetmanagementv1alpha1.ApprovalStatus{}
+			Expect(r.recordAppliedChange(ctx, pipeline, &approval.Change{Spec: pipeline.Spec.DeepCopy(), Contents: "old"})).To(Succeed())
+			pipeline.Spec.Contents = "new"
+			change := &approval.Change{Spec: &pipeline.Spec, Contents: "new"}
+
+			waiting, err := r.awaitingApproval(ctx, pipeline, change)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waiting).To(BeTrue())
+			pending := pipeline.Status.Approval.Pending
+			Expect(pending).ToNot(BeNil())
+			Expect(pending.Authors).To(Equal([]string{"alice"}))
+			Expect(pending.Diff).To(ContainSubstring("-   contents: old\n+   contents: new"))
+			Expect(meta.IsStatusConditionTrue(pipeline.Status.Conditions, conditionTypePendingApproval)).To(BeTrue())
+
+			By("approving as the author")
+			pipeline.Annotations[fleetmanagementv1alpha1.ApproveAnnotation] = pending.Hash
+			pipeline.Annotations[fleetmanagementv1alpha1.ApprovedByAnnotation] = "alice"
+			Expect(approvalDue(pipeline)).To(BeFalse())
+
+			By("approving as another user")
+			pipeline.Annotations[fleetmanagementv1alpha1.ApprovedByAnnotation] = "bob"
+			Expect(approvalDue(pipeline)).To(BeTrue())
+			waiting, err = r.awaitingApproval(ctx, pipeline, change)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waiting).To(BeFalse())
+			Expect(pipeline.Status.Approval.Pending).To(BeNil())
+
+			Expect(r.recordAppliedChange(ctx, pipeline, change)).To(Succeed())
+			waiting, err = r.awaitingApproval(ctx, pipeline, change)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waiting).To(BeFalse())
+
+			By("keeping the applied change outside of status")
+			configMap := &corev1.ConfigMap{}
+			Expect(r.Get(ctx, types.NamespacedName{Namespace: "prod", Name: "pipeline-approval-uid-1"}, configMap)).To(Succeed())
+			Expect(configMap.Data[appliedChangeKey]).To(ContainSubstring("contents: new"))
+			Expect(configMap.OwnerReferences).To(ConsistOf(HaveField("UID", pipeline.UID)))
+		})
+
+		It("should hold changes of the rendered contents and Secrets", func() {
+			gate, err := approval.ParseGate("prod", "")
+			Expect(err).ToNot(HaveOccurred())
+			r := &PipelineReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).Build(),
+				Approval: gate,
+			}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "prod", UID: "uid-1"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					TemplateRef: &fleetmanagementv1alpha1.TemplateReference{Name: "metrics"},
+				},
+			}
+			pipeline.Status.Approval = &fleetmanagementv1alpha1.ApprovalStatus{}
+			Expect(r.recordAppliedChange(ctx, pipeline, &approval.Change{Spec: &pipeline.Spec, Contents: "old"})).To(Succeed())
+
+			By("changing the template")
+			pipeline.Status.Template = &fleetmanagementv1alpha1.ObservedResource{Name: "metrics", Generation: 2, ChangedBy: "carol"}
+			waiting, err := r.awaitingApproval(ctx, pipeline, &approval.Change{Spec: &pipeline.Spec, Contents: "new"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waiting).To(BeTrue())
+			Expect(pipeline.Status.Approval.Pending.Diff).To(ContainSubstring("- contents: old\n+ contents: new"))
+			Expect(pipeline.Status.Approval.Pending.Authors).To(Equal([]string{"carol"}))
+
+			By("approving as the template author")
+			pipeline.Annotations = map[string]string{
+				fleetmanagementv1alpha1.ApproveAnnotation:    pipeline.Status.Approval.Pending.Hash,
+				fleetmanagementv1alpha1.ApprovedByAnnotation: "carol",
+			}
+			Expect(approvalDue(pipeline)).To(BeFalse())
+
+			By("rotating a Secret")
+			waiting, err = r.awaitingApproval(ctx, pipeline, &approval.Change{Spec: &pipeline.Spec, Contents: "old",
+				Secrets: []fleetmanagementv1alpha1.ObservedResource{{Name: "creds", ResourceVersion: "2"}}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waiting).To(BeTrue())
+		})
+
+		It("should not gate other namespaces", func() {
+			gate, err := approval.ParseGate("prod", "")
+			Expect(err).ToNot(HaveOccurred())
+			r := &PipelineReconciler{Approval: gate}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "dev"}}
+
+			waiting, err := r.awaitingApproval(ctx, pipeline, &approval.Change{Spec: &pipeline.Spec})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(waiting).To(BeFalse())
+			Expect(pipeline.Status.Approval).To(BeNil())
+		})
+	})
+
+	Context("When a PipelinePolicy restricts a namespace", func() {
+		ctx := context.Background()
+
+		var pp *fleetmanagementv1alpha1.PipelinePolicy
+
+		BeforeEach(func() {
+			pp = &fleetmanagementv1alpha1.PipelinePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenancy", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelinePolicySpec{
+					RequiredMatchers: []string{"team=${namespace}"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pp)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pp))).To(Succeed())
+		})
+
+		It("should block violating pipelines until they comply", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Enabled: true, Matchers: []string{"env=prod"}},
+			}
+
+			Eventually(func() error { return r.checkPolicies(ctx, pipeline, "") }).Should(HaveOccurred())
+			err := r.checkPolicies(ctx, pipeline, "")
+			Expect(err).To(MatchError(ContainSubstring("requires the matcher team=default")))
+			renderErr, ok := err.(*renderError)
+			Expect(ok).To(BeTrue())
+			Expect(renderErr.reason).To(Equal(reasonPolicyViolation))
+			Expect(meta.IsStatusConditionTrue(pipeline.Status.Conditions, conditionTypePolicyViolation)).To(BeTrue())
+			Expect(r.policyChanged(ctx, pipeline)).To(BeTrue())
+
+			By("removing the policy")
+			Expect(k8sClient.Delete(ctx, pp)).To(Succeed())
+			Eventually(func() error { return r.checkPolicies(ctx, pipeline, "") }).Should(Succeed())
+			Expect(meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypePolicyViolation)).To(BeNil())
+			Expect(r.policyChanged(ctx, pipeline)).To(BeFalse())
+		})
+
+		It("should block rendered contents using denied components", func() {
+			pp.Spec.AlloyComponents = &fleetmanagementv1alpha1.ComponentLists{Denied: []string{"local.file"}}
+			Expect(k8sClient.Update(ctx, pp)).To(Succeed())
+
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Enabled: true, Matchers: []string{"team=default"}},
+			}
+			contents := "local.file \"token\" {\n  filename = \"/token\"\n}\n"
+			Eventually(func() error { return r.checkPolicies(ctx, pipeline, contents) }).
+				Should(MatchError(ContainSubstring(`denies component local.file "token" at line 1`)))
+		})
+	})
+
+	Context("When a Pipeline has a schedule", func() {
+		monday := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+
+		newPipeline := func() *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "business-hours", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Enabled: true,
+					Schedule: &fleetmanagementv1alpha1.PipelineSchedule{
+						Windows: []fleetmanagementv1alpha1.ScheduleWindow{
+							{Start: "0 9 * * MON-FRI", Duration: metav1.Duration{Duration: 8 * time.Hour}},
+						},
+					},
+				},
+			}
+		}
+
+		It("should enable the pipeline inside a window and requeue at its end", func() {
+			r := &PipelineReconciler{clock: func() time.Time { return monday }}
+			pipeline := newPipeline()
+			remote := &fleetclient.Pipeline{Enabled: true}
+
+			Expect(r.applySchedule(pipeline, remote)).To(Succeed())
+			Expect(remote.Enabled).To(BeTrue())
+			Expect(pipeline.Status.NextTransitionTime.Time).To(Equal(monday.Add(7 * time.Hour)))
+			Expect(meta.IsStatusConditionTrue(pipeline.Status.Conditions, conditionTypeScheduled)).To(BeTrue())
+
+			result, err := r.requeueForDeadlines(pipeline, ctrl.Result{RequeueAfter: 24 * time.Hour}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(7 * time.Hour))
+			Expect(r.scheduleDue(pipeline)).To(BeFalse())
+		})
+
+		It("should disable the pipeline outside of windows and after expiry", func() {
+			evening := monday.Add(8 * time.Hour)
+			r := &PipelineReconciler{clock: func() time.Time { return evening }}
+			pipeline := newPipeline()
+			pipeline.Status.NextTransitionTime = &metav1.Time{Time: monday.Add(7 * time.Hour)}
+			Expect(r.scheduleDue(pipeline)).To(BeTrue())
+
+			remote := &fleetclient.Pipeline{Enabled: true}
+			Expect(r.applySchedule(pipeline, remote)).To(Succeed())
+			Expect(remote.Enabled).To(BeFalse())
+			condition := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeScheduled)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Reason).To(Equal(schedule.ReasonOutsideWindow))
+
+			By("expiring the pipeline")
+			pipeline.Spec.ExpiresAt = &metav1.Time{Time: monday}
+			remote.Enabled = true
+			Expect(r.applySchedule(pipeline, remote)).To(Succeed())
+			Expect(remote.Enabled).To(BeFalse())
+			Expect(pipeline.Status.NextTransitionTime).To(BeNil())
+			condition = meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeScheduled)
+			Expect(condition.Reason).To(Equal(schedule.ReasonExpired))
+		})
+	})
+
+	Context("When prepending PipelineModules", func() {
+		ctx := context.Background()
+
+		var base, scrape *fleetmanagementv1alpha1.PipelineModule
+
+		BeforeEach(func() {
+			base = &fleetmanagementv1alpha1.PipelineModule{
+				ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "default"},
+				Spec:       fleetmanagementv1alpha1.PipelineModuleSpec{Contents: "declare \"write\" { }"},
+			}
+			scrape = &fleetmanagementv1alpha1.PipelineModule{
+				ObjectMeta: metav1.ObjectMeta{Name: "scrape", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineModuleSpec{
+					Contents:  "declare \"scrape\" { }",
+					DependsOn: []string{"base"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, base)).To(Succeed())
+			Expect(k8sClient.Create(ctx, scrape)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, base))).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, scrape))).To(Succeed())
+		})
+
+		newPipeline := func(modules ...string) *fleetmanagementv1alpha1.Pipeline {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "team_a", Namespace: "default"},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Contents: "scrape \"default\" { }"},
+			}
+			for _, name := range modules {
+				pipeline.Spec.Modules = append(pipeline.Spec.Modules, fleetmanagementv1alpha1.ModuleReference{Name: name})
+			}
+			return pipeline
+		}
+
+		It("should include dependencies first and record them in status", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline("scrape")
+
+			contents, err := r.renderContents(ctx, pipeline)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal("// Module: base\ndeclare \"write\" { }\n\n" +
+				"// Module: scrape\ndeclare \"scrape\" { }\n\n" +
+				"scrape \"default\" { }"))
+			Expect(pipeline.Status.Modules).To(HaveLen(2))
+			Expect(pipeline.Status.Modules[0].Name).To(Equal("base"))
+			Expect(r.modulesChanged(ctx, pipeline)).To(BeFalse())
+
+			By("editing a dependency")
+			base.Spec.Contents += "\n"
+			Expect(k8sClient.Update(ctx, base)).To(Succeed())
+			Eventually(func() bool { return r.modulesChanged(ctx, pipeline) }).Should(BeTrue())
+		})
+
+		It("should return a render error for a missing module", func() {
+			r := &PipelineReconciler{Client: k8sClient}
+			pipeline := newPipeline("missing")
+
+			_, err := r.renderContents(ctx, pipeline)
+			var renderErr *renderError
+			Expect(err).To(BeAssignableToTypeOf(renderErr))
+			Expect(err).To(MatchError(ContainSubstring(`PipelineModule "missing" not found`)))
+			Expect(r.modulesChanged(ctx, pipeline)).To(BeFalse())
+		})
+	})
+
+	Context("When evaluating matched collectors", func() {
+		newCollector := func(id string, attrs map[string]string) *fleetclient.Collector {
+			return &fleetclient.Collector{ID: id, RemoteAttributes: attrs}
+		}
+
+		It("should select active collectors whose attributes match", func() {
+			inactive := time.Now()
+			collectors := []*fleetclient.Collector{
+				newCollector("b", map[string]string{"env": "prod"}),
+				newCollector("a", map[string]string{"env": "prod"}),
+				newCollector("c", map[string]string{"env": "dev"}),
+				{ID: "d", RemoteAttributes: map[string]string{"env": "prod"}, MarkedInactiveAt: &inactive},
+			}
+
+			ms, errs := matchers.FromSpec(&fleetmanagementv1alpha1.PipelineSpec{
+				Matchers: []string{"env=prod"},
+			}, nil)
+			Expect(errs).To(BeEmpty())
+			Expect(matchCollectors(collectors, ms)).To(Equal([]string{"a", "b"}))
+		})
+
+		It("should match on collector.ID", func() {
+			ms, errs := matchers.FromSpec(&fleetmanagementv1alpha1.PipelineSpec{
+				Matchers: []string{"collector.ID=c"},
+			}, nil)
+			Expect(errs).To(BeEmpty())
+			Expect(matchCollectors([]*fleetclient.Collector{
+				newCollector("a", nil),
+				newCollector("c", nil),
+			}, ms)).To(Equal([]string{"c"}))
+		})
+
+		It("should record the count, truncate IDs and set the NoMatchingCollectors condition", func() {
+			var collectors []*fleetclient.Collector
+			for i := range maxMatchedCollectorIDs + 5 {
+				collectors = append(collectors, newCollector(fmt.Sprintf("collector-%02d", i), map[string]string{"env": "prod"}))
+			}
+			r := &PipelineReconciler{
+				collectors: &collectorCache{client: &mockCollectorClient{collectors: collectors}, interval: time.Minute},
+			}
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				Spec: fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"env=prod"}},
+			}
+			Expect(r.setMatchedCollectors(context.Background(), pipeline)).To(Succeed())
+			Expect(pipeline.Status.MatchedCollectors.Count).To(BeEquivalentTo(maxMatchedCollectorIDs + 5))
+			Expect(pipeline.Status.MatchedCollectors.IDs).To(HaveLen(maxMatchedCollectorIDs))
+			Expect(pipeline.Status.MatchedCollectors.Truncated).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(pipeline.Status.Conditions, conditionTypeNoMatchingCollectors)).To(BeTrue())
+
+			pipeline.Spec.Matchers = []string{"env=dev"}
+			Expect(r.setMatchedCollectors(context.Background(), pipeline)).To(Succeed())
+			Expect(pipeline.Status.MatchedCollectors.Count).To(BeZero())
+			Expect(meta.IsStatusConditionTrue(pipeline.Status.Conditions, conditionTypeNoMatchingCollectors)).To(BeTrue())
+		})
+
+		It("should list collectors once per refresh interval", func() {
+			mock := &mockCollectorClient{}
+			cache := &collectorCache{client: mock, interval: time.Hour}
+
+			_, _, err := cache.list(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			_, _, err = cache.list(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.calls).To(Equal(1))
+		})
+	})
+
+	Context("When formatting contents", func() {
+		It("should format contents only when spec.format is set", func() {
+			contents := "logging {\n  level = \"info\"\n}\n"
+			pipeline := &fleetmanagementv1alpha1.Pipeline{}
+
+			formatted, err := formatContents(pipeline, contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(formatted).To(Equal(contents))
+
+			pipeline.Spec.Format = true
+			formatted, err = formatContents(pipeline, contents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(formatted).To(Equal("logging {\n\tlevel = \"info\"\n}\n"))
+		})
+
+		It("should report contents that cannot be parsed", func() {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{
+				Format:     true,
+				ConfigType: fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector,
+			}}
+
+			_, err := formatContents(pipeline, "receivers: [otlp")
+			var renderErr *renderError
+			Expect(err).To(BeAssignableToTypeOf(renderErr))
+			Expect(err.(*renderError).reason).To(Equal(reasonFormatError))
+		})
+	})
+
+	Context("When the request was already applied", func() {
+		newRequest := func() *fleetclient.UpsertPipelineRequest {
+			return &fleetclient.UpsertPipelineRequest{Pipeline: &fleetclient.Pipeline{
+				Name: "metrics", Contents: "x", Enabled: true, Matchers: []string{"env=prod"},
+			}}
+		}
+
+		It("should hash equal requests equally", func() {
+			a, err := requestHash(newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			b, err := requestHash(newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(a).To(Equal(b))
+
+			changed := newRequest()
+			changed.Pipeline.Enabled = false
+			c, err := requestHash(changed, "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(c).ToNot(Equal(a))
+		})
+
+		It("should hash Secret versions instead of Secret values", func() {
+			secrets := []fleetmanagementv1alpha1.ObservedResource{{Name: "creds", UID: "uid", ResourceVersion: "1"}}
+			substituted := newRequest()
+			substituted.Pipeline.Contents = "password = \"hunter2\""
+			rotated := newRequest()
+			rotated.Pipeline.Contents = "password = \"hunter3\""
+
+			a, err := requestHash(substituted, "password = secret(\"creds\")", secrets)
+			Expect(err).ToNot(HaveOccurred())
+			b, err := requestHash(rotated, "password = secret(\"creds\")", secrets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(Equal(a))
+
+			secrets[0].ResourceVersion = "2"
+			c, err := requestHash(rotated, "password = secret(\"creds\")", secrets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(c).ToNot(Equal(a))
+		})
+
+		It("should skip UpsertPipeline until the request or the remote pipeline changes", func() {
+			mock := newMockFleetClient()
+			now := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+			r := &PipelineReconciler{FleetClient: mock, DriftCheckInterval: time.Hour, clock: func() time.Time { return now }}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{}
+			synced := func(apiPipeline *fleetclient.Pipeline) {
+				pipeline.Status.ID = apiPipeline.ID
+				pipeline.Status.RemoteName = apiPipeline.Name
+				pipeline.Status.RevisionID = pipelineRevision(apiPipeline)
+			}
+
+			apiPipeline, err := r.upsertUnlessApplied(context.Background(), pipeline, newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pipeline.Status.AppliedHash).ToNot(BeEmpty())
+			synced(apiPipeline)
+			Expect(pipeline.Status.RevisionID).ToNot(BeEmpty())
+
+			By("sending the same request again")
+			skipped, err := r.upsertUnlessApplied(context.Background(), pipeline, newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.callCount).To(Equal(1))
+			Expect(mock.getCount).To(BeZero())
+			Expect(skipped.ID).To(Equal(apiPipeline.ID))
+			Expect(skipped.Name).To(Equal("metrics"))
+
+			By("editing the pipeline in Fleet Management")
+			edited := *mock.pipelines[apiPipeline.ID]
+			updatedAt := edited.UpdatedAt.Add(time.Minute)
+			edited.UpdatedAt = &updatedAt
+			mock.pipelines[apiPipeline.ID] = &edited
+			_, err = r.upsertUnlessApplied(context.Background(), pipeline, newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.callCount).To(Equal(1))
+			Expect(mock.getCount).To(BeZero())
+
+			By("checking for drift once the interval passed")
+			now = now.Add(time.Hour)
+			Expect(r.driftCheckDue(pipeline)).To(BeTrue())
+			apiPipeline, err = r.upsertUnlessApplied(context.Background(), pipeline, newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.getCount).To(Equal(1))
+			Expect(mock.callCount).To(Equal(2))
+			Expect(pipeline.Status.RevisionCheckTime.Time).To(Equal(now))
+			Expect(r.driftCheckDue(pipeline)).To(BeFalse())
+			synced(apiPipeline)
+
+			By("checking an unchanged remote pipeline")
+			now = now.Add(2 * time.Hour)
+			_, err = r.upsertUnlessApplied(context.Background(), pipeline, newRequest(), "x", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.getCount).To(Equal(2))
+			Expect(mock.callCount).To(Equal(2))
+			Expect(pipeline.Status.RevisionCheckTime.Time).To(Equal(now))
+			Expect(r.requeueForDriftCheck(pipeline, ctrl.Result{}).RequeueAfter).To(Equal(time.Hour))
+
+			By("changing the contents")
+			changed := newRequest()
+			changed.Pipeline.Contents = "y"
+			_, err = r.upsertUnlessApplied(context.Background(), pipeline, changed, "y", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.callCount).To(Equal(3))
+		})
+	})
+
+	Context("When batching the Pipelines of a namespace", func() {
+		newPipeline := func(name string) *fleetmanagementv1alpha1.Pipeline {
+			return &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{Contents: "x"},
+			}
+		}
+
+		newReconciler := func(objs ...client.Object) *PipelineReconciler {
+			return &PipelineReconciler{
+				Client:      fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(objs...).Build(),
+				ClusterName: "prod",
+				Batcher:     fleetclient.NewBatcher(nil, time.Minute),
+			}
+		}
+
+		It("should share the source of the namespace", func() {
+			r := newReconciler()
+			Expect(r.pipelineSource(newPipeline("a")).Namespace).To(Equal("prod/default"))
+			Expect(r.ownsRemotePipeline(newPipeline("a"), &fleetclient.Pipeline{
+				Source: &fleetclient.Source{Type: "SOURCE_TYPE_KUBERNETES", Namespace: "prod/default/a"},
+			})).To(BeTrue())
+		})
+
+		It("should count the Pipelines of the namespace without a spec.source", func() {
+			other := newPipeline("other")
+			other.Namespace = "other"
+			git := newPipeline("git")
+			git.Spec.Source = &fleetmanagementv1alpha1.PipelineSource{Type: fleetmanagementv1alpha1.SourceTypeGit, Namespace: "repo"}
+			r := newReconciler(newPipeline("a"), newPipeline("b"), other, git)
+
+			members, err := r.sourceMembers(context.Background(), newPipeline("a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(members).To(Equal(2))
+		})
+
+		It("should not batch namespaces with Pipelines that skip the sync", func() {
+			synced := newPipeline("synced")
+			synced.Generation = 2
+			synced.Status.ObservedGeneration = 2
+			synced.Status.AppliedHash = "abc"
+			r := newReconciler(newPipeline("a"), synced)
+
+			members, err := r.sourceMembers(context.Background(), newPipeline("a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(members).To(BeZero())
+
+			By("changing the spec of the synced Pipeline")
+			synced.Generation = 3
+			r = newReconciler(newPipeline("a"), synced)
+			members, err = r.sourceMembers(context.Background(), newPipeline("a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(members).To(Equal(2))
+		})
+
+		It("should not batch namespaces with paused or rolled out Pipelines", func() {
+			paused := newPipeline("paused")
+			paused.Annotations = map[string]string{fleetmanagementv1alpha1.PausedAnnotation: "true"}
+			r := newReconciler(newPipeline("a"), paused)
+
+			members, err := r.sourceMembers(context.Background(), newPipeline("a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(members).To(BeZero())
+
+			canary := newPipeline("canary")
+			canary.Status.Rollout = &fleetmanagementv1alpha1.RolloutStatus{CanaryID: "canary-id"}
+			r = newReconciler(newPipeline("a"), canary)
+
+			members, err = r.sourceMembers(context.Background(), newPipeline("a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(members).To(BeZero())
+		})
+
+		It("should upsert Pipelines with a spec.source directly", func() {
+			mock := newMockFleetClient()
+			r := newReconciler()
+			r.FleetClient = mock
+			pipeline := newPipeline("a")
+			pipeline.Spec.Source = &fleetmanagementv1alpha1.PipelineSource{Type: fleetmanagementv1alpha1.SourceTypeGit, Namespace: "repo"}
+
+			_, err := r.upsertPipeline(context.Background(), pipeline,
+				&fleetclient.UpsertPipelineRequest{Pipeline: &fleetclient.Pipeline{Name: "a"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.callCount).To(Equal(1))
+		})
+
+		It("should upsert Pipelines directly without a cluster name", func() {
+			mock := newMockFleetClient()
+			r := newReconciler()
+			r.FleetClient = mock
+			r.ClusterName = ""
+
+			_, err := r.upsertPipeline(context.Background(), newPipeline("a"),
+				&fleetclient.UpsertPipelineRequest{Pipeline: &fleetclient.Pipeline{Name: "a"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mock.callCount).To(Equal(1))
+		})
+	})
+
+	Context("When several namespaces are reconciled concurrently", func() {
+		It("should take turns between the namespaces", func() {
+			newPipeline := func(namespace, name string) *fleetmanagementv1alpha1.Pipeline {
+				return &fleetmanagementv1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name, Namespace: namespace, Generation: 1,
+						Finalizers: []string{pipelineFinalizer},
+					},
+					Spec: fleetmanagementv1alpha1.PipelineSpec{Contents: "logging { }", Enabled: true},
+				}
+			}
+			var objs []client.Object
+			var bulk []ctrl.Request
+			for i := range 6 {
+				p := newPipeline("bulk", fmt.Sprintf("p%d", i))
+				objs = append(objs, p)
+				bulk = append(bulk, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(p)})
+			}
+			others := []ctrl.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "metrics"}},
+				{NamespacedName: types.NamespacedName{Namespace: "team-b", Name: "metrics"}},
+			}
+			objs = append(objs, newPipeline("team-a", "metrics"), newPipeline("team-b", "metrics"))
+
+			fleet := &laneRecordingClient{mockFleetClient: newMockFleetClient(), limiter: fleetclient.NewFairLimiter(10, 1)}
+			r := &PipelineReconciler{
+				Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).
+					WithStatusSubresource(&fleetmanagementv1alpha1.Pipeline{}).WithObjects(objs...).Build(),
+				FleetClient: fleet,
+			}
+
+			var wg sync.WaitGroup
+			reconcile := func(requests []ctrl.Request) {
+				for _, req := range requests {
+					wg.Go(func() {
+						defer GinkgoRecover()
+						_, err := r.Reconcile(context.Background(), req)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				}
+			}
+			By("starting a bulk change in one namespace, then a change in two others")
+			reconcile(bulk)
+			time.Sleep(30 * time.Millisecond)
+			reconcile(others)
+			wg.Wait()
+
+			served := fleet.served()
+			Expect(served).To(HaveLen(8))
+			Expect(slices.Index(served, "team-a")).To(BeNumerically("<", 4))
+			Expect(slices.Index(served, "team-b")).To(BeNumerically("<", 4))
+		})
 	})
 
 	Context("Mock Fleet Client Tests", func() {
@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/quota"
+)
+
+// PipelineQuotaReconciler reports the Pipeline usage of a namespace in the
+// status of its PipelineQuotas. The quotas are enforced by the webhook, and
+// for contents rendered from other resources by the PipelineReconciler.
+type PipelineQuotaReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinequotas/status,verbs=get;update;patch
+
+// Reconcile updates the usage in the status of a PipelineQuota
+func (r *PipelineQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	pq := &fleetmanagementv1alpha1.PipelineQuota{}
+	if err := r.Get(ctx, req.NamespacedName, pq); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := r.List(ctx, pipelines, client.InNamespace(pq.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := fleetmanagementv1alpha1.PipelineQuotaStatus{Hard: *pq.Spec.Hard.DeepCopy()}
+	used := quota.Usage(pipelines.Items)
+	status.Used = &used
+	if equality.Semantic.DeepEqual(pq.Status, status) {
+		return ctrl.Result{}, nil
+	}
+
+	pq.Status = status
+	if err := r.Status().Update(ctx, pq); err != nil {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("status update conflict, requeueing")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	log.V(1).Info("updated PipelineQuota usage", "pipelines", used.Pipelines,
+		"enabledPipelines", used.EnabledPipelines, "contentsBytes", used.ContentsBytes.String())
+	return ctrl.Result{}, nil
+}
+
+// quotasForPipeline maps a Pipeline to the PipelineQuotas of its namespace
+func (r *PipelineQuotaReconciler) quotasForPipeline(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	quotas := &fleetmanagementv1alpha1.PipelineQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list PipelineQuotas for Pipeline", "name", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(quotas.Items))
+	for i := range quotas.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: quotas.Items[i].Namespace, Name: quotas.Items[i].Name},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PipelineQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&fleetmanagementv1alpha1.PipelineQuota{}).
+		Watches(&fleetmanagementv1alpha1.Pipeline{},
+			handler.EnqueueRequestsFromMapFunc(r.quotasForPipeline)).
+		Named("pipelinequota").
+		Complete(r)
+}
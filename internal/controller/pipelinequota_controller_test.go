@@ -0,0 +1,96 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+var _ = Describe("PipelineQuota Controller", func() {
+	Context("When Pipelines are created in a namespace with a PipelineQuota", func() {
+		ctx := context.Background()
+
+		It("should report their usage in status", func() {
+			limit := int64(10)
+			pq := &fleetmanagementv1alpha1.PipelineQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota-usage", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineQuotaSpec{
+					Hard: fleetmanagementv1alpha1.PipelineQuotaLimits{Pipelines: &limit},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pq)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pq))).To(Succeed()) })
+
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota-usage", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					Contents: "logging { }",
+					Enabled:  true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, pipeline)).To(Succeed())
+			DeferCleanup(func() { Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pipeline))).To(Succeed()) })
+
+			Eventually(func(g Gomega) {
+				current := &fleetmanagementv1alpha1.PipelineQuota{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "quota-usage"}, current)).To(Succeed())
+				g.Expect(current.Status.Hard.Pipelines).To(Equal(&limit))
+				g.Expect(current.Status.Used).ToNot(BeNil())
+				g.Expect(current.Status.Used.Pipelines).To(BeNumerically(">=", 1))
+				g.Expect(current.Status.Used.EnabledPipelines).To(BeNumerically(">=", 1))
+				g.Expect(current.Status.Used.ContentsBytes.Cmp(resource.MustParse("11"))).To(BeNumerically(">=", 0))
+			}).Should(Succeed())
+		})
+	})
+
+	Context("When a Pipeline renders contents larger than a PipelineQuota allows", func() {
+		It("should refuse to sync it and keep the recorded size", func() {
+			limit := resource.MustParse("10")
+			pq := &fleetmanagementv1alpha1.PipelineQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "bytes", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineQuotaSpec{
+					Hard: fleetmanagementv1alpha1.PipelineQuotaLimits{ContentsBytes: &limit},
+				},
+			}
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "templated", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineSpec{
+					TemplateRef: &fleetmanagementv1alpha1.TemplateReference{Name: "metrics"},
+				},
+			}
+			r := &PipelineReconciler{
+				Client: fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).WithObjects(pq, pipeline).Build(),
+			}
+
+			Expect(r.checkQuotas(context.Background(), pipeline, "logging { }")).To(MatchError(ContainSubstring("contentsBytes would be 11")))
+			Expect(pipeline.Status.ContentsBytes).To(BeZero())
+
+			Expect(r.checkQuotas(context.Background(), pipeline, "logging{}")).To(Succeed())
+			Expect(pipeline.Status.ContentsBytes).To(Equal(int64(9)))
+		})
+	})
+})
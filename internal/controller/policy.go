@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/policy"
+)
+
+// listPolicies returns the PipelinePolicies of the pipeline namespace and the
+// ClusterPipelinePolicies
+func (r *PipelineReconciler) listPolicies(ctx context.Context, namespace string) ([]policy.Policy, error) {
+	namespaced := &fleetmanagementv1alpha1.PipelinePolicyList{}
+	if err := r.List(ctx, namespaced, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list PipelinePolicies: %w", err)
+	}
+	cluster := &fleetmanagementv1alpha1.ClusterPipelinePolicyList{}
+	if err := r.List(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterPipelinePolicies: %w", err)
+	}
+	return policy.FromPolicies(namespaced.Items, cluster.Items), nil
+}
+
+// checkPolicies records the policy violations of the pipeline and its rendered
+// contents in the PolicyViolation condition. It returns a renderError when
+// there are any, since the pipeline must not be synced.
+func (r *PipelineReconciler) checkPolicies(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, contents string) error {
+	policies, err := r.listPolicies(ctx, pipeline.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		meta.RemoveStatusCondition(&pipeline.Status.Conditions, conditionTypePolicyViolation)
+		return nil
+	}
+
+	violations := append(policy.Check(policies, pipeline), policy.CheckContents(policies, pipeline, contents)...)
+	if len(violations) == 0 {
+		meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+			Type:               conditionTypePolicyViolation,
+			Status:             metav1.ConditionFalse,
+			Reason:             reasonPolicyCompliant,
+			Message:            fmt.Sprintf("Compliant with %d policies", len(policies)),
+			ObservedGeneration: pipeline.Generation,
+		})
+		return nil
+	}
+
+	message := violations.ToAggregate().Error()
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypePolicyViolation,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonPolicyViolation,
+		Message:            message,
+		ObservedGeneration: pipeline.Generation,
+	})
+	return &renderError{reasonPolicyViolation, fmt.Errorf("policy violations: %s", message)}
+}
+
+// policyChanged reports whether an already reconciled pipeline must be checked
+// against policies again, since they do not change the pipeline generation.
+// Blocked pipelines are checked on every reconcile, as their rendered contents
+// may comply now. Others are checked when their spec violates a policy.
+func (r *PipelineReconciler) policyChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if meta.IsStatusConditionTrue(pipeline.Status.Conditions, conditionTypePolicyViolation) {
+		return true
+	}
+	policies, err := r.listPolicies(ctx, pipeline.Namespace)
+	if err != nil {
+		return true
+	}
+	return len(policy.Check(policies, pipeline)) > 0 ||
+		len(policy.CheckContents(policies, pipeline, pipeline.Spec.Contents)) > 0
+}
+
+// pipelinesForPolicy maps a PipelinePolicy to the Pipelines of its namespace,
+// and a ClusterPipelinePolicy to all Pipelines
+func (r *PipelineReconciler) pipelinesForPolicy(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	var opts []client.ListOption
+	if _, ok := obj.(*fleetmanagementv1alpha1.PipelinePolicy); ok {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := r.List(ctx, pipelines, opts...); err != nil {
+		log.Error(err, "failed to list Pipelines for policy", "name", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(pipelines.Items))
+	for i := range pipelines.Items {
+		p := &pipelines.Items[i]
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+		})
+	}
+	return requests
+}
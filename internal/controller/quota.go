@@ -0,0 +1,96 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/quota"
+)
+
+// checkQuotas records the size of the rendered contents in status, unless it
+// would take the namespace over the contentsBytes limit of a PipelineQuota.
+// The webhook only sees spec.contents, so contents growing through templates,
+// fragments or modules are caught here and returned as a renderError.
+func (r *PipelineReconciler) checkQuotas(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, contents string) error {
+	rendered := pipeline.DeepCopy()
+	rendered.Status.ContentsBytes = int64(len(contents))
+
+	quotas := &fleetmanagementv1alpha1.PipelineQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(pipeline.Namespace)); err != nil {
+		return fmt.Errorf("failed to list PipelineQuotas: %w", err)
+	}
+	if len(quotas.Items) > 0 {
+		pipelines := &fleetmanagementv1alpha1.PipelineList{}
+		if err := r.List(ctx, pipelines, client.InNamespace(pipeline.Namespace)); err != nil {
+			return fmt.Errorf("failed to list Pipelines: %w", err)
+		}
+
+		before := quota.Replace(pipelines.Items, pipeline.Name, pipeline)
+		after := quota.Replace(pipelines.Items, pipeline.Name, rendered)
+		var allErrs field.ErrorList
+		for i := range quotas.Items {
+			allErrs = append(allErrs, quota.Check(&quotas.Items[i], before, after)...)
+		}
+		if len(allErrs) > 0 {
+			return &renderError{reasonQuotaExceeded, allErrs.ToAggregate()}
+		}
+	}
+
+	pipeline.Status.ContentsBytes = rendered.Status.ContentsBytes
+	return nil
+}
+
+// quotaBlocked reports whether the last sync was refused because of a
+// PipelineQuota. Such pipelines are checked on every reconcile, since other
+// Pipelines may have shrunk or been deleted since.
+func quotaBlocked(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	ready := meta.FindStatusCondition(pipeline.Status.Conditions, conditionTypeReady)
+	return ready != nil && ready.Reason == reasonQuotaExceeded
+}
+
+// pipelinesForQuota maps a PipelineQuota to the Pipelines of its namespace
+// blocked by a quota
+func (r *PipelineReconciler) pipelinesForQuota(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := r.List(ctx, pipelines, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list Pipelines for PipelineQuota", "name", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range pipelines.Items {
+		p := &pipelines.Items[i]
+		if quotaBlocked(p) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/alloy"
+	"github.com/grafana/fleet-management-operator/pkg/otelcol"
+)
+
+// renderError is returned when the contents of a Pipeline cannot be rendered.
+// It is reported in status with its reason and not retried until the Pipeline
+// or one of the resources it is rendered from changes.
+type renderError struct {
+	reason string
+	err    error
+}
+
+func (e *renderError) Error() string {
+	return e.err.Error()
+}
+
+func (e *renderError) Unwrap() error {
+	return e.err
+}
+
+// renderContents returns the contents sent to Fleet Management: spec.contents
+// or the rendered template merged with the selected fragments, with the
+// declarations of the referenced modules prepended, formatted when spec.format
+// is set. The resources used are recorded in status.
+func (r *PipelineReconciler) renderContents(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	contents, err := r.renderTemplate(ctx, pipeline)
+	if err != nil {
+		return "", err
+	}
+
+	contents, err = r.applyFragments(ctx, pipeline, contents)
+	if err != nil {
+		return "", err
+	}
+
+	contents, err = r.applyModules(ctx, pipeline, contents)
+	if err != nil {
+		return "", err
+	}
+
+	return formatContents(pipeline, contents)
+}
+
+// formatContents lays out the contents of Pipelines with spec.format canonically
+func formatContents(pipeline *fleetmanagementv1alpha1.Pipeline, contents string) (string, error) {
+	if !pipeline.Spec.Format {
+		return contents, nil
+	}
+
+	var formatted string
+	var err error
+	if pipeline.Spec.ConfigType == fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector {
+		formatted, err = otelcol.Format(contents)
+	} else {
+		formatted, err = alloy.Format(contents)
+	}
+	if err != nil {
+		return "", &renderError{reason: reasonFormatError, err: fmt.Errorf("failed to format contents: %w", err)}
+	}
+	return formatted, nil
+}
+
+// dependenciesChanged reports whether any resource the pipeline is rendered
+// from changed since its contents were last rendered
+func (r *PipelineReconciler) dependenciesChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	return r.templateChanged(ctx, pipeline) ||
+		r.fragmentsChanged(ctx, pipeline) ||
+		r.modulesChanged(ctx, pipeline) ||
+		r.secretsChanged(ctx, pipeline)
+}
+
+// observedResource records the version of obj used for rendering and who
+// last changed it
+func observedResource(obj client.Object) *fleetmanagementv1alpha1.ObservedResource {
+	return &fleetmanagementv1alpha1.ObservedResource{
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+		Generation: obj.GetGeneration(),
+		ChangedBy:  obj.GetAnnotations()[fleetmanagementv1alpha1.ChangedByAnnotation],
+	}
+}
+
+// pipelinesReferencing maps a resource to the Pipelines in its namespace that
+// reference it by name through the given field index
+func (r *PipelineReconciler) pipelinesReferencing(index string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		log := logf.FromContext(ctx)
+
+		pipelines := &fleetmanagementv1alpha1.PipelineList{}
+		if err := r.List(ctx, pipelines,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{index: obj.GetName()},
+		); err != nil {
+			log.Error(err, "failed to list referencing Pipelines", "index", index, "name", obj.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(pipelines.Items))
+		for _, p := range pipelines.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name},
+			})
+		}
+		return requests
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+	"github.com/grafana/fleet-management-operator/pkg/naming"
+)
+
+// canarySuffix is appended to the remote name of canary pipelines
+const canarySuffix = "_canary"
+
+// contentsHash identifies pipeline contents in the rollout status. The
+// contents are hashed as rendered, before Secret values are substituted, along
+// with the versions of the Secrets, as by requestHash.
+func contentsHash(rendered string, secrets []fleetmanagementv1alpha1.ObservedResource) string {
+	h := sha256.New()
+	h.Write([]byte(rendered))
+	for _, secret := range secrets {
+		fmt.Fprintf(h, "\x00%s/%s/%s", secret.Name, secret.UID, secret.ResourceVersion)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// revisionHash identifies the contents of a remote pipeline by its revision,
+// for main pipelines whose rendered contents are not known
+func revisionHash(pipeline *fleetclient.Pipeline) string {
+	return contentsHash("revision "+pipeline.ID+" "+pipelineRevision(pipeline), nil)
+}
+
+// excludeCanaries adds the negated canary matcher to the matchers of the main
+// pipeline, so the canary collectors only run the canary while it is tried
+func (r *PipelineReconciler) excludeCanaries(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) error {
+	if pipeline.Status.ID == "" {
+		return nil
+	}
+	canary, err := matchers.Parse(pipeline.Spec.Rollout.CanaryMatchers[0])
+	if err != nil {
+		return err
+	}
+	exclude := canary.Negate().String()
+
+	main, err := r.FleetClient.GetPipeline(ctx, pipeline.Status.ID)
+	if isNotFound(err) || (err == nil && slices.Contains(main.Matchers, exclude)) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	main.Matchers = append(slices.Clone(main.Matchers), exclude)
+	if _, err := r.FleetClient.UpsertPipeline(ctx, &fleetclient.UpsertPipelineRequest{Pipeline: main}); err != nil {
+		return err
+	}
+	// The main pipeline no longer holds the last applied request, so the next
+	// sync restores its matchers
+	pipeline.Status.AppliedHash = ""
+	return nil
+}
+
+// promotionDue reports whether the canary of a rollout is ready to be promoted,
+// by bake time or by the promote annotation
+func (r *PipelineReconciler) promotionDue(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	status := pipeline.Status.Rollout
+	if pipeline.Spec.Rollout == nil || status == nil || status.CanaryHash == "" || status.CanaryHash == status.StableHash {
+		return false
+	}
+	if pipeline.Annotations[fleetmanagementv1alpha1.PromoteAnnotation] == status.CanaryHash {
+		return true
+	}
+	end, ok := bakeEnd(pipeline)
+	return ok && !r.now().Before(end)
+}
+
+// bakeEnd returns when the canary of a rollout is promoted by bake time
+func bakeEnd(pipeline *fleetmanagementv1alpha1.Pipeline) (time.Time, bool) {
+	status := pipeline.Status.Rollout
+	if pipeline.Spec.Rollout == nil || pipeline.Spec.Rollout.BakeTime == nil ||
+		status == nil || status.CanaryStartTime == nil || status.CanaryHash == status.StableHash {
+		return time.Time{}, false
+	}
+	return status.CanaryStartTime.Add(pipeline.Spec.Rollout.BakeTime.Duration), true
+}
+
+// rolloutCanary sends changed contents of a pipeline with a rollout strategy
+// to a canary copy restricted to the canary collectors, which the main
+// pipeline excludes until the change is promoted. rendered are the contents of
+// remote before Secret values were substituted. It reports whether the main
+// pipeline must be left unchanged.
+func (r *PipelineReconciler) rolloutCanary(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, remote *fleetclient.Pipeline, rendered string) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	strategy := pipeline.Spec.Rollout
+	if strategy == nil {
+		if pipeline.Status.Rollout != nil && pipeline.Status.Rollout.CanaryID == "" {
+			pipeline.Status.Rollout = nil
+		}
+		return false, nil
+	}
+
+	hash := contentsHash(rendered, pipeline.Status.Secrets)
+	if pipeline.Status.Rollout == nil {
+		pipeline.Status.Rollout = &fleetmanagementv1alpha1.RolloutStatus{}
+	}
+	status := pipeline.Status.Rollout
+
+	// The first sync, and the first one after the strategy was added, have
+	// nothing to compare with but the main pipeline
+	if status.StableHash == "" && pipeline.Status.ID != "" {
+		current, err := r.FleetClient.GetPipeline(ctx, pipeline.Status.ID)
+		if err != nil && !isNotFound(err) {
+			return true, err
+		}
+		// The main pipeline holds substituted Secret values, so only its
+		// revision is hashed when it differs
+		switch {
+		case current == nil:
+		case current.Contents == remote.Contents:
+			status.StableHash = hash
+		default:
+			status.StableHash = revisionHash(current)
+		}
+	}
+	if status.StableHash == "" {
+		status.StableHash = hash
+	}
+
+	switch {
+	case hash == status.StableHash:
+		if status.CanaryHash != "" {
+			log.Info("contents reverted, abandoning canary", "canary", status.CanaryName)
+			r.setRolloutCondition(pipeline, metav1.ConditionFalse, reasonCanaryReverted,
+				"Contents were reverted, the canary is removed")
+		}
+		return false, nil
+
+	case hash == status.CanaryHash && r.promotionDue(pipeline):
+		log.Info("promoting canary to the main pipeline", "canary", status.CanaryName, "hash", hash)
+		status.StableHash = hash
+		r.setRolloutCondition(pipeline, metav1.ConditionFalse, reasonPromoted,
+			fmt.Sprintf("Contents %s were promoted to the main pipeline", hash))
+		return false, nil
+	}
+
+	canary := *remote
+	canary.Name = naming.Sanitize(remote.Name + canarySuffix)
+	canary.Matchers = append(slices.Clone(remote.Matchers), strategy.CanaryMatchers...)
+	apiCanary, err := r.FleetClient.UpsertPipeline(ctx, &fleetclient.UpsertPipelineRequest{Pipeline: &canary})
+	if err != nil {
+		return true, err
+	}
+	if err := r.excludeCanaries(ctx, pipeline); err != nil {
+		return true, err
+	}
+
+	// A renamed canary is created under a new ID
+	if status.CanaryID != "" && status.CanaryID != apiCanary.ID {
+		if err := r.FleetClient.DeletePipeline(ctx, status.CanaryID); err != nil && !isNotFound(err) {
+			log.Error(err, "failed to delete previous canary from Fleet Management", "previousID", status.CanaryID)
+		}
+	}
+
+	if status.CanaryHash != hash {
+		log.Info("deployed canary", "canary", apiCanary.Name, "hash", hash)
+		status.CanaryHash = hash
+		status.CanaryStartTime = &metav1.Time{Time: r.now()}
+	}
+	status.CanaryID = apiCanary.ID
+	status.CanaryName = apiCanary.Name
+
+	message := fmt.Sprintf("Canary %q runs contents %s on collectors matching %v; annotate with %s=%s to promote it",
+		apiCanary.Name, hash, strategy.CanaryMatchers, fleetmanagementv1alpha1.PromoteAnnotation, hash)
+	if end, ok := bakeEnd(pipeline); ok {
+		message += " before " + end.UTC().Format(time.RFC3339)
+	}
+	r.setRolloutCondition(pipeline, metav1.ConditionTrue, reasonCanaryDeployed, message)
+	return true, nil
+}
+
+// setRolloutCondition records the phase of a rollout
+func (r *PipelineReconciler) setRolloutCondition(pipeline *fleetmanagementv1alpha1.Pipeline, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeRolloutProgressing,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: pipeline.Generation,
+	})
+}
+
+// deleteCanary removes the canary pipeline, if any, once the main pipeline
+// holds the promoted or reverted contents
+func (r *PipelineReconciler) deleteCanary(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) error {
+	status := pipeline.Status.Rollout
+	if status == nil || status.CanaryID == "" {
+		return nil
+	}
+
+	if err := r.FleetClient.DeletePipeline(ctx, status.CanaryID); err != nil && !isNotFound(err) {
+		return err
+	}
+	status.CanaryHash = ""
+	status.CanaryID = ""
+	status.CanaryName = ""
+	status.CanaryStartTime = nil
+	if pipeline.Spec.Rollout == nil {
+		pipeline.Status.Rollout = nil
+		meta.RemoveStatusCondition(&pipeline.Status.Conditions, conditionTypeRolloutProgressing)
+	}
+	return nil
+}
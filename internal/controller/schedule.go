@@ -0,0 +1,122 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/schedule"
+)
+
+// now returns the current time, overridable in tests
+func (r *PipelineReconciler) now() time.Time {
+	if r.clock != nil {
+		return r.clock()
+	}
+	return time.Now()
+}
+
+// hasSchedule reports whether the pipeline's enablement depends on time
+func hasSchedule(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	return pipeline.Spec.Schedule != nil || pipeline.Spec.ExpiresAt != nil
+}
+
+// applySchedule sets the remote pipeline's enabled flag from spec.schedule and
+// spec.expiresAt and records when it changes next
+func (r *PipelineReconciler) applySchedule(pipeline *fleetmanagementv1alpha1.Pipeline, remote *fleetclient.Pipeline) error {
+	if !hasSchedule(pipeline) {
+		pipeline.Status.NextTransitionTime = nil
+		meta.RemoveStatusCondition(&pipeline.Status.Conditions, conditionTypeScheduled)
+		return nil
+	}
+
+	state, err := schedule.Evaluate(&pipeline.Spec, r.now())
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	remote.Enabled = state.Enabled
+	pipeline.Status.NextTransitionTime = nil
+	message := "Pipeline is enabled"
+	if !state.Enabled {
+		message = "Pipeline is disabled"
+	}
+	if !state.Next.IsZero() {
+		pipeline.Status.NextTransitionTime = &metav1.Time{Time: state.Next}
+		message += " until " + state.Next.UTC().Format(time.RFC3339)
+	}
+
+	status := metav1.ConditionFalse
+	if state.Enabled {
+		status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&pipeline.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeScheduled,
+		Status:             status,
+		Reason:             state.Reason,
+		Message:            message,
+		ObservedGeneration: pipeline.Generation,
+	})
+	return nil
+}
+
+// scheduleDue reports whether the pipeline reached its next transition time
+// and must be synced again
+func (r *PipelineReconciler) scheduleDue(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	next := pipeline.Status.NextTransitionTime
+	return next != nil && !r.now().Before(next.Time)
+}
+
+// nextDeadline returns when the pipeline must be reconciled again regardless
+// of changes: its next schedule transition, the expiry of a debug pipeline or
+// the end of a canary's bake time
+func nextDeadline(pipeline *fleetmanagementv1alpha1.Pipeline) (time.Time, bool) {
+	var deadline time.Time
+	earliest := func(t time.Time, ok bool) {
+		if ok && (deadline.IsZero() || t.Before(deadline)) {
+			deadline = t
+		}
+	}
+	if next := pipeline.Status.NextTransitionTime; next != nil {
+		earliest(next.Time, true)
+	}
+	earliest(debugExpiry(pipeline))
+	earliest(bakeEnd(pipeline))
+	return deadline, !deadline.IsZero()
+}
+
+// requeueForDeadlines makes sure the pipeline is reconciled again at its next
+// deadline
+func (r *PipelineReconciler) requeueForDeadlines(pipeline *fleetmanagementv1alpha1.Pipeline, result ctrl.Result, err error) (ctrl.Result, error) {
+	deadline, ok := nextDeadline(pipeline)
+	if err != nil || !ok || result.Requeue { // nolint:staticcheck
+		return result, err
+	}
+
+	after := max(deadline.Sub(r.now()), time.Second)
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+	return result, nil
+}
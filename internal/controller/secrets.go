@@ -0,0 +1,163 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/secretref"
+)
+
+// secretRefsIndex indexes Pipelines by the names of the Secrets they reference
+const secretRefsIndex = "spec.secretRefs.name"
+
+// uncachedReader returns the reader used for Secret data and applied changes.
+// They are read through APIReader, when set, so they are not kept in the
+// informer cache.
+func (r *PipelineReconciler) uncachedReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// substituteSecrets replaces the Secret references in contents with their
+// values, escaped for a double-quoted string of the config type. It returns
+// the values substituted so they can be redacted from errors. The versions of
+// the Secrets in spec.secretRefs are recorded in status so changes to them
+// re-sync the pipeline.
+func (r *PipelineReconciler) substituteSecrets(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline, contents string) (string, []string, error) {
+	if len(pipeline.Spec.SecretRefs) == 0 {
+		pipeline.Status.Secrets = nil
+		return contents, nil, nil
+	}
+
+	listed := make(map[string]bool, len(pipeline.Spec.SecretRefs))
+	secrets := make(map[string]*corev1.Secret, len(pipeline.Spec.SecretRefs))
+	observed := make([]fleetmanagementv1alpha1.ObservedResource, 0, len(pipeline.Spec.SecretRefs))
+	for _, ref := range pipeline.Spec.SecretRefs {
+		listed[ref.Name] = true
+		secret := &corev1.Secret{}
+		if err := r.uncachedReader().Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.Name}, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", nil, fmt.Errorf("failed to get Secret %q: %w", ref.Name, err)
+			}
+			observed = append(observed, fleetmanagementv1alpha1.ObservedResource{Name: ref.Name})
+			continue
+		}
+		secrets[ref.Name] = secret
+		observed = append(observed, fleetmanagementv1alpha1.ObservedResource{
+			Name:            ref.Name,
+			UID:             secret.UID,
+			ResourceVersion: secret.ResourceVersion,
+		})
+	}
+	pipeline.Status.Secrets = observed
+
+	for _, ref := range secretref.References(contents) {
+		if !listed[ref.Secret] {
+			return "", nil, &renderError{reasonSecretError,
+				fmt.Errorf("Secret %q is referenced by the contents but not listed in spec.secretRefs", ref.Secret)}
+		}
+	}
+
+	escape := secretref.AlloyString
+	if pipeline.Spec.ConfigType == fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector {
+		escape = secretref.YAMLString
+	}
+	substituted, values, err := secretref.Substitute(contents, func(ref secretref.Reference) (string, bool) {
+		secret, ok := secrets[ref.Secret]
+		if !ok {
+			return "", false
+		}
+		value, ok := secret.Data[ref.Key]
+		return string(value), ok
+	}, escape)
+	if err != nil {
+		return "", nil, &renderError{reasonSecretError, err}
+	}
+	return substituted, values, nil
+}
+
+// secretsChanged reports whether any Secret in spec.secretRefs changed, was
+// deleted or was created since its values were last substituted. Only Secret
+// metadata is read.
+func (r *PipelineReconciler) secretsChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if len(pipeline.Spec.SecretRefs) != len(pipeline.Status.Secrets) {
+		return true
+	}
+
+	for i, ref := range pipeline.Spec.SecretRefs {
+		observed := pipeline.Status.Secrets[i]
+		if observed.Name != ref.Name {
+			return true
+		}
+
+		secret := &metav1.PartialObjectMetadata{}
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		if err := r.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) && observed.UID == "" {
+				continue
+			}
+			return true
+		}
+		if secret.UID != observed.UID || secret.ResourceVersion != observed.ResourceVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// redactError removes substituted Secret values from an error returned by
+// Fleet Management, which may quote the contents it rejected
+func redactError(err error, values []string) error {
+	if len(values) == 0 {
+		return err
+	}
+
+	var apiErr *fleetclient.FleetAPIError
+	if errors.As(err, &apiErr) {
+		redacted := *apiErr
+		redacted.Message = secretref.Redact(apiErr.Message, values)
+		return &redacted
+	}
+	return errors.New(secretref.Redact(err.Error(), values))
+}
+
+// indexSecretRefs returns the Secret names a Pipeline references, for secretRefsIndex
+func indexSecretRefs(obj client.Object) []string {
+	pipeline, ok := obj.(*fleetmanagementv1alpha1.Pipeline)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(pipeline.Spec.SecretRefs))
+	for _, ref := range pipeline.Spec.SecretRefs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/pipelinetemplate"
+)
+
+// templateRefIndex indexes Pipelines by the name of the PipelineTemplate they reference
+const templateRefIndex = "spec.templateRef.name"
+
+// renderTemplate returns spec.contents, or the rendered template for Pipelines
+// with a templateRef. The template used is recorded in status so template
+// edits can be detected.
+func (r *PipelineReconciler) renderTemplate(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	ref := pipeline.Spec.TemplateRef
+	if ref == nil {
+		pipeline.Status.Template = nil
+		return pipeline.Spec.Contents, nil
+	}
+
+	tmpl := &fleetmanagementv1alpha1.PipelineTemplate{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.Name}, tmpl); err != nil {
+		if apierrors.IsNotFound(err) {
+			pipeline.Status.Template = nil
+			return "", &renderError{reasonTemplateError, fmt.Errorf("PipelineTemplate %q not found", ref.Name)}
+		}
+		return "", fmt.Errorf("failed to get PipelineTemplate %q: %w", ref.Name, err)
+	}
+
+	pipeline.Status.Template = observedResource(tmpl)
+
+	contents, err := pipelinetemplate.Render(tmpl, pipeline)
+	if err != nil {
+		return "", &renderError{reasonTemplateError, err}
+	}
+	return contents, nil
+}
+
+// templateChanged reports whether the PipelineTemplate referenced by the pipeline
+// differs from the one its contents were last rendered from
+func (r *PipelineReconciler) templateChanged(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	ref := pipeline.Spec.TemplateRef
+	if ref == nil {
+		return false
+	}
+
+	tmpl := &fleetmanagementv1alpha1.PipelineTemplate{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.Name}, tmpl); err != nil {
+		// A deleted template is reported once; other errors are retried by reconciling
+		return !apierrors.IsNotFound(err) || pipeline.Status.Template != nil
+	}
+
+	observed := pipeline.Status.Template
+	return observed == nil ||
+		observed.Name != tmpl.Name ||
+		observed.UID != tmpl.UID ||
+		observed.Generation != tmpl.Generation
+}
+
+// indexTemplateRef returns the template name a Pipeline references, for templateRefIndex
+func indexTemplateRef(obj client.Object) []string {
+	pipeline, ok := obj.(*fleetmanagementv1alpha1.Pipeline)
+	if !ok || pipeline.Spec.TemplateRef == nil {
+		return nil
+	}
+	return []string{pipeline.Spec.TemplateRef.Name}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Results of a PipelineValidationRule evaluation
+const (
+	ruleResultPass  = "pass"
+	ruleResultFail  = "fail"
+	ruleResultError = "error"
+)
+
+// validationRuleEvaluations counts PipelineValidationRule evaluations by rule,
+// action and result
+var validationRuleEvaluations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "fleet_management_validation_rule_evaluations_total",
+	Help: "PipelineValidationRule evaluations by rule, action and result (pass, fail or error)",
+}, []string{"rule", "action", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(validationRuleEvaluations)
+}
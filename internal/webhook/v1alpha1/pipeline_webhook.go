@@ -0,0 +1,611 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/approval"
+	"github.com/grafana/fleet-management-operator/pkg/credscan"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+	"github.com/grafana/fleet-management-operator/pkg/naming"
+	"github.com/grafana/fleet-management-operator/pkg/pipelinetemplate"
+	"github.com/grafana/fleet-management-operator/pkg/policy"
+	"github.com/grafana/fleet-management-operator/pkg/quota"
+	"github.com/grafana/fleet-management-operator/pkg/schedule"
+	"github.com/grafana/fleet-management-operator/pkg/secretref"
+	"github.com/grafana/fleet-management-operator/pkg/validationrule"
+)
+
+// maxDebugTTL is the longest a debug pipeline may live
+const maxDebugTTL = 7 * 24 * time.Hour
+
+// nolint:unused
+// log is for logging in this package.
+var pipelinelog = logf.Log.WithName("pipeline-resource")
+
+// SetupPipelineWebhookWithManager registers the webhook for Pipeline in the manager.
+func SetupPipelineWebhookWithManager(mgr ctrl.Manager, validator *PipelineCustomValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.Pipeline{}).
+		WithValidator(validator).
+		WithDefaulter(&PipelineCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-fleetmanagement-grafana-com-v1alpha1-pipeline,mutating=true,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelines,verbs=create;update,versions=v1alpha1,name=mpipeline-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineCustomDefaulter records who changes the spec of a Pipeline and who
+// approves the change, for Pipelines whose changes require approval.
+type PipelineCustomDefaulter struct{}
+
+var _ admission.Defaulter[*fleetmanagementv1alpha1.Pipeline] = &PipelineCustomDefaulter{}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type Pipeline.
+func (d *PipelineCustomDefaulter) Default(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var oldPipeline *fleetmanagementv1alpha1.Pipeline
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		oldPipeline = &fleetmanagementv1alpha1.Pipeline{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldPipeline); err != nil {
+			return fmt.Errorf("failed to decode old Pipeline: %w", err)
+		}
+	}
+
+	recordUsers(pipeline, oldPipeline, req.UserInfo.Username)
+	return nil
+}
+
+// recordUsers sets the changed-by annotation when the spec changes and the
+// approved-by annotation when the approve annotation changes. Otherwise both
+// keep their previous values, so that users cannot set them.
+func recordUsers(pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline, user string) {
+	var oldAnnotations map[string]string
+	specChanged := true
+	if oldPipeline != nil {
+		oldAnnotations = oldPipeline.Annotations
+		specChanged = !equality.Semantic.DeepEqual(oldPipeline.Spec, pipeline.Spec)
+	}
+
+	changedBy := oldAnnotations[fleetmanagementv1alpha1.ChangedByAnnotation]
+	if specChanged {
+		changedBy = user
+	}
+
+	approvedBy := oldAnnotations[fleetmanagementv1alpha1.ApprovedByAnnotation]
+	if approve := pipeline.Annotations[fleetmanagementv1alpha1.ApproveAnnotation]; approve != oldAnnotations[fleetmanagementv1alpha1.ApproveAnnotation] {
+		approvedBy = ""
+		if approve != "" {
+			approvedBy = user
+		}
+	}
+
+	setAnnotation(pipeline, fleetmanagementv1alpha1.ChangedByAnnotation, changedBy)
+	setAnnotation(pipeline, fleetmanagementv1alpha1.ApprovedByAnnotation, approvedBy)
+}
+
+// recordChangedBy sets the changed-by annotation of a resource rendered into
+// Pipelines when its spec changes, so that its authors cannot approve the
+// Pipelines it changes. Otherwise the annotation keeps its previous value.
+func recordChangedBy[T client.Object](ctx context.Context, obj, oldObj T, spec func(T) any) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var oldAnnotations map[string]string
+	specChanged := true
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+			return fmt.Errorf("failed to decode old %s: %w", req.Kind.Kind, err)
+		}
+		oldAnnotations = oldObj.GetAnnotations()
+		specChanged = !equality.Semantic.DeepEqual(spec(oldObj), spec(obj))
+	}
+
+	changedBy := oldAnnotations[fleetmanagementv1alpha1.ChangedByAnnotation]
+	if specChanged {
+		changedBy = req.UserInfo.Username
+	}
+	setAnnotation(obj, fleetmanagementv1alpha1.ChangedByAnnotation, changedBy)
+	return nil
+}
+
+// setAnnotation sets an annotation, or removes it when value is empty
+func setAnnotation(obj client.Object, key, value string) {
+	annotations := obj.GetAnnotations()
+	if value == "" {
+		delete(annotations, key)
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-pipeline,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelines,verbs=create;update,versions=v1alpha1,name=vpipeline-v1alpha1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:rbac:groups=fleetmanagement.grafana.com,resources=pipelinevalidationrules,verbs=get;list;watch
+
+// PipelineCustomValidator validates Pipeline resources when they are created or updated.
+type PipelineCustomValidator struct {
+	// Naming computes remote pipeline names with the same defaults as the reconciler
+	Naming *naming.Resolver
+
+	// Reader looks up referenced PipelineTemplates to check parameters.
+	// A nil Reader skips the check.
+	Reader client.Reader
+
+	// CredentialScan decides whether plaintext credentials in spec.contents are
+	// warned about or denied. Empty disables scanning.
+	CredentialScan credscan.Mode
+}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.Pipeline] = &PipelineCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type Pipeline.
+func (v *PipelineCustomValidator) ValidateCreate(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, error) {
+	pipelinelog.V(1).Info("validation for Pipeline upon creation", "name", pipeline.GetName())
+
+	return v.validate(ctx, pipeline, nil)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type Pipeline.
+func (v *PipelineCustomValidator) ValidateUpdate(ctx context.Context, oldPipeline, newPipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, error) {
+	pipelinelog.V(1).Info("validation for Pipeline upon update", "name", newPipeline.GetName())
+
+	return v.validate(ctx, newPipeline, oldPipeline)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type Pipeline.
+func (v *PipelineCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks a Pipeline. oldPipeline is nil on create.
+func (v *PipelineCustomValidator) validate(ctx context.Context, pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+
+	// Policies, rules and the credential scan mode may have changed since the
+	// Pipeline was written, but they must not keep the finalizer from being
+	// added or removed. Rules, policies and the credential scan also look at
+	// labels and annotations, so any other update is checked in full.
+	if onlyFinalizersChanged(pipeline, oldPipeline) {
+		return nil, nil
+	}
+
+	nameWarnings, nameErrs := v.validateName(pipeline, oldPipeline)
+	warnings = append(warnings, nameWarnings...)
+	allErrs = append(allErrs, nameErrs...)
+
+	_, matcherErrs := matchers.FromSpec(&pipeline.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, matcherErrs...)
+
+	templateWarnings, templateErrs := v.validateTemplate(ctx, pipeline)
+	warnings = append(warnings, templateWarnings...)
+	allErrs = append(allErrs, templateErrs...)
+
+	if pipeline.Spec.FragmentSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(pipeline.Spec.FragmentSelector,
+			metav1validation.LabelSelectorValidationOptions{}, field.NewPath("spec", "fragmentSelector"))...)
+	}
+
+	allErrs = append(allErrs, schedule.Validate(&pipeline.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateDebug(pipeline, oldPipeline)...)
+
+	allErrs = append(allErrs, validateApproval(pipeline, oldPipeline)...)
+
+	rolloutWarnings, rolloutErrs := validateRollout(pipeline)
+	warnings = append(warnings, rolloutWarnings...)
+	allErrs = append(allErrs, rolloutErrs...)
+
+	credentialWarnings, credentialErrs := v.validateCredentials(pipeline)
+	warnings = append(warnings, credentialWarnings...)
+	allErrs = append(allErrs, credentialErrs...)
+
+	secretWarnings, secretErrs := v.validateSecretRefs(pipeline)
+	warnings = append(warnings, secretWarnings...)
+	allErrs = append(allErrs, secretErrs...)
+
+	moduleWarnings, moduleErrs := v.validateModules(ctx, pipeline)
+	warnings = append(warnings, moduleWarnings...)
+	allErrs = append(allErrs, moduleErrs...)
+
+	policyWarnings, policyErrs := v.validatePolicies(ctx, pipeline)
+	warnings = append(warnings, policyWarnings...)
+	allErrs = append(allErrs, policyErrs...)
+
+	ruleWarnings, ruleErrs := v.validateRules(ctx, pipeline, oldPipeline)
+	warnings = append(warnings, ruleWarnings...)
+	allErrs = append(allErrs, ruleErrs...)
+
+	quotaWarnings, quotaErrs := v.validateQuotas(ctx, pipeline, oldPipeline)
+	warnings = append(warnings, quotaWarnings...)
+	allErrs = append(allErrs, quotaErrs...)
+
+	return warnings, invalid(pipeline, allErrs)
+}
+
+// onlyFinalizersChanged reports whether an update leaves the spec, labels and
+// annotations as is, or is made to a Pipeline being deleted, which only has
+// its finalizers removed
+func onlyFinalizersChanged(pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if oldPipeline == nil {
+		return false
+	}
+	if !pipeline.DeletionTimestamp.IsZero() {
+		return true
+	}
+	return equality.Semantic.DeepEqual(oldPipeline.Spec, pipeline.Spec) &&
+		equality.Semantic.DeepEqual(oldPipeline.Labels, pipeline.Labels) &&
+		equality.Semantic.DeepEqual(oldPipeline.Annotations, pipeline.Annotations)
+}
+
+// invalid returns the error denying a Pipeline with errs, or nil when there are none
+func invalid(pipeline *fleetmanagementv1alpha1.Pipeline, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		fleetmanagementv1alpha1.GroupVersion.WithKind("Pipeline").GroupKind(),
+		pipeline.Name, errs)
+}
+
+// validateName checks that the computed remote pipeline name is a valid Alloy
+// identifier. An invalid spec.name that was already accepted before is only
+// warned about so existing Pipelines can still be updated.
+func (v *PipelineCustomValidator) validateName(pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	specPath := field.NewPath("spec")
+
+	resolver := v.Naming
+	if resolver == nil {
+		resolver = &naming.Resolver{}
+	}
+
+	remoteName, err := resolver.RemoteName(pipeline)
+	if err != nil {
+		path := specPath.Child("nameTemplate")
+		if pipeline.Spec.NamingStrategy != fleetmanagementv1alpha1.NamingStrategyTemplate &&
+			resolver.DefaultStrategy != fleetmanagementv1alpha1.NamingStrategyTemplate {
+			path = specPath.Child("namingStrategy")
+		}
+		return nil, field.ErrorList{field.Invalid(path, pipeline.Spec.NameTemplate, err.Error())}
+	}
+
+	if err := naming.ValidateIdentifier(remoteName); err != nil {
+		// metadata.name is used as is by the MetadataName strategy, as it was
+		// before names were validated, so it is only warned about
+		if pipeline.Spec.Name == "" {
+			return admission.Warnings{"metadata.name: " + err.Error() +
+				"; set spec.name or spec.namingStrategy: NamespacedName for a sanitized name"}, nil
+		}
+		if oldPipeline != nil && oldPipeline.Spec.Name == pipeline.Spec.Name {
+			return admission.Warnings{"spec.name: " + err.Error()}, nil
+		}
+		return nil, field.ErrorList{field.Invalid(specPath.Child("name"), pipeline.Spec.Name, err.Error())}
+	}
+
+	return nil, nil
+}
+
+// validateTemplate checks that at most one of contents and templateRef is set,
+// that one of them or fragmentSelector is set, and that the parameters match
+// the referenced template. A missing template is only warned about since it
+// may be created after the Pipeline.
+func (v *PipelineCustomValidator) validateTemplate(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	specPath := field.NewPath("spec")
+	ref := pipeline.Spec.TemplateRef
+
+	switch {
+	case ref == nil && pipeline.Spec.Contents == "" && pipeline.Spec.FragmentSelector == nil:
+		return nil, field.ErrorList{field.Required(specPath.Child("contents"), "one of contents, templateRef or fragmentSelector must be set")}
+	case ref != nil && pipeline.Spec.Contents != "":
+		return nil, field.ErrorList{field.Forbidden(specPath.Child("contents"), "contents must be empty when templateRef is set")}
+	case ref == nil && len(pipeline.Spec.Parameters) > 0:
+		return nil, field.ErrorList{field.Forbidden(specPath.Child("parameters"), "parameters require templateRef")}
+	case ref == nil || v.Reader == nil:
+		return nil, nil
+	}
+
+	tmpl := &fleetmanagementv1alpha1.PipelineTemplate{}
+	if err := v.Reader.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.Name}, tmpl); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Warnings{fmt.Sprintf("spec.templateRef: PipelineTemplate %q not found", ref.Name)}, nil
+		}
+		return admission.Warnings{fmt.Sprintf("spec.templateRef: parameters not checked: %v", err)}, nil
+	}
+
+	_, errs := pipelinetemplate.Params(&tmpl.Spec, pipeline.Spec.Parameters, specPath.Child("parameters"))
+	return nil, errs
+}
+
+// validateModules checks that modules are only used by Alloy pipelines. Missing
+// modules are only warned about since they may be created after the Pipeline.
+func (v *PipelineCustomValidator) validateModules(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	if len(pipeline.Spec.Modules) == 0 {
+		return nil, nil
+	}
+
+	modulesPath := field.NewPath("spec", "modules")
+	if pipeline.Spec.ConfigType != "" && pipeline.Spec.ConfigType != fleetmanagementv1alpha1.ConfigTypeAlloy {
+		return nil, field.ErrorList{field.Forbidden(modulesPath, "modules are only supported for Alloy pipelines")}
+	}
+	if v.Reader == nil {
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	for i, ref := range pipeline.Spec.Modules {
+		module := &fleetmanagementv1alpha1.PipelineModule{}
+		if err := v.Reader.Get(ctx, types.NamespacedName{Namespace: pipeline.Namespace, Name: ref.Name}, module); err != nil {
+			if apierrors.IsNotFound(err) {
+				warnings = append(warnings, fmt.Sprintf("%s: PipelineModule %q not found", modulesPath.Index(i), ref.Name))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// validatePolicies checks the pipeline and the components of spec.contents
+// against the PipelinePolicies of its namespace and the ClusterPipelinePolicies.
+// Policies that cannot be listed are only warned about; the reconciler enforces
+// them too, on the rendered contents.
+func (v *PipelineCustomValidator) validatePolicies(ctx context.Context, pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	if v.Reader == nil {
+		return nil, nil
+	}
+
+	namespaced := &fleetmanagementv1alpha1.PipelinePolicyList{}
+	if err := v.Reader.List(ctx, namespaced, client.InNamespace(pipeline.Namespace)); err != nil {
+		return admission.Warnings{fmt.Sprintf("PipelinePolicies not checked: %v", err)}, nil
+	}
+	cluster := &fleetmanagementv1alpha1.ClusterPipelinePolicyList{}
+	if err := v.Reader.List(ctx, cluster); err != nil {
+		return admission.Warnings{fmt.Sprintf("ClusterPipelinePolicies not checked: %v", err)}, nil
+	}
+	policies := policy.FromPolicies(namespaced.Items, cluster.Items)
+	allErrs := policy.Check(policies, pipeline)
+	if pipeline.Spec.Contents != "" {
+		allErrs = append(allErrs, policy.CheckContents(policies, pipeline, pipeline.Spec.Contents)...)
+	}
+	return nil, allErrs
+}
+
+// validateRules evaluates the PipelineValidationRules selecting the pipeline.
+// Rules that cannot be listed or evaluated are only warned about, so a broken
+// rule does not block every Pipeline.
+func (v *PipelineCustomValidator) validateRules(ctx context.Context, pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	if v.Reader == nil {
+		return nil, nil
+	}
+
+	rules := &fleetmanagementv1alpha1.PipelineValidationRuleList{}
+	if err := v.Reader.List(ctx, rules); err != nil {
+		return admission.Warnings{fmt.Sprintf("PipelineValidationRules not checked: %v", err)}, nil
+	}
+	slices.SortFunc(rules.Items, func(a, b fleetmanagementv1alpha1.PipelineValidationRule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		applies, err := validationrule.Applies(rule, pipeline)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("PipelineValidationRule %s not checked: %v", rule.Name, err))
+			continue
+		}
+		if !applies {
+			continue
+		}
+
+		action := cmp.Or(rule.Spec.Action, fleetmanagementv1alpha1.ValidationActionDeny)
+		passed, err := validationrule.Evaluate(ctx, rule, pipeline, oldPipeline)
+		switch {
+		case err != nil:
+			validationRuleEvaluations.WithLabelValues(rule.Name, string(action), ruleResultError).Inc()
+			pipelinelog.Info("failed to evaluate PipelineValidationRule", "rule", rule.Name,
+				"pipeline", pipeline.Namespace+"/"+pipeline.Name, "error", err.Error())
+			warnings = append(warnings, fmt.Sprintf("PipelineValidationRule %s could not be evaluated: %v", rule.Name, err))
+		case passed:
+			validationRuleEvaluations.WithLabelValues(rule.Name, string(action), ruleResultPass).Inc()
+		default:
+			validationRuleEvaluations.WithLabelValues(rule.Name, string(action), ruleResultFail).Inc()
+			pipelinelog.Info("Pipeline failed PipelineValidationRule", "rule", rule.Name, "action", action,
+				"pipeline", pipeline.Namespace+"/"+pipeline.Name)
+			if action == fleetmanagementv1alpha1.ValidationActionWarn {
+				warnings = append(warnings, validationrule.Message(rule))
+			} else {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), validationrule.Message(rule)))
+			}
+		}
+	}
+	return warnings, allErrs
+}
+
+// validateQuotas checks that the change stays within the PipelineQuotas of the
+// namespace. Usage is counted from the cached Pipelines, so concurrent
+// creations may briefly exceed a quota.
+func (v *PipelineCustomValidator) validateQuotas(ctx context.Context, pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	if v.Reader == nil {
+		return nil, nil
+	}
+
+	quotas := &fleetmanagementv1alpha1.PipelineQuotaList{}
+	if err := v.Reader.List(ctx, quotas, client.InNamespace(pipeline.Namespace)); err != nil {
+		return admission.Warnings{fmt.Sprintf("PipelineQuotas not checked: %v", err)}, nil
+	}
+	if len(quotas.Items) == 0 {
+		return nil, nil
+	}
+	slices.SortFunc(quotas.Items, func(a, b fleetmanagementv1alpha1.PipelineQuota) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	pipelines := &fleetmanagementv1alpha1.PipelineList{}
+	if err := v.Reader.List(ctx, pipelines, client.InNamespace(pipeline.Namespace)); err != nil {
+		return admission.Warnings{fmt.Sprintf("PipelineQuotas not checked: %v", err)}, nil
+	}
+
+	before := quota.Replace(pipelines.Items, pipeline.Name, oldPipeline)
+	after := quota.Replace(pipelines.Items, pipeline.Name, pipeline)
+	var allErrs field.ErrorList
+	for i := range quotas.Items {
+		allErrs = append(allErrs, quota.Check(&quotas.Items[i], before, after)...)
+	}
+	return nil, allErrs
+}
+
+// validateDebug checks the TTL of a debug pipeline and that spec.debug is not
+// added, changed or removed after creation
+func validateDebug(pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) field.ErrorList {
+	debug := pipeline.Spec.Debug
+	if oldPipeline != nil && !equality.Semantic.DeepEqual(oldPipeline.Spec.Debug, debug) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "debug"),
+			"can only be set when the Pipeline is created, since the TTL is counted from its creation")}
+	}
+	if debug == nil {
+		return nil
+	}
+	if ttl := debug.TTL.Duration; ttl <= 0 || ttl > maxDebugTTL {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "debug", "ttl"), debug.TTL.String(),
+			fmt.Sprintf("must be positive and at most %s", maxDebugTTL))}
+	}
+	return nil
+}
+
+// validateApproval denies approvals of a change by one of its authors: the
+// user who changed the spec, or the template, fragments or modules rendered
+// into the Pipeline
+func validateApproval(pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) field.ErrorList {
+	approve := pipeline.Annotations[fleetmanagementv1alpha1.ApproveAnnotation]
+	if approve == "" || (oldPipeline != nil && oldPipeline.Annotations[fleetmanagementv1alpha1.ApproveAnnotation] == approve) {
+		return nil
+	}
+
+	// The authors of rendered resources are only known from the status,
+	// which update requests of the main resource do not change
+	authored := pipeline.DeepCopy()
+	if oldPipeline != nil {
+		authored.Status = oldPipeline.Status
+	}
+	approver := pipeline.Annotations[fleetmanagementv1alpha1.ApprovedByAnnotation]
+	if approver != "" && slices.Contains(approval.Authors(authored), approver) {
+		return field.ErrorList{field.Forbidden(
+			field.NewPath("metadata", "annotations").Key(fleetmanagementv1alpha1.ApproveAnnotation),
+			fmt.Sprintf("%q made the change and cannot approve it", approver))}
+	}
+	return nil
+}
+
+// validateRollout checks the canary matchers and bake time of a rollout
+// strategy. Without a bake time, canaries wait for the promote annotation.
+func validateRollout(pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	rollout := pipeline.Spec.Rollout
+	if rollout == nil {
+		return nil, nil
+	}
+
+	var allErrs field.ErrorList
+	rolloutPath := field.NewPath("spec", "rollout")
+	for i, s := range rollout.CanaryMatchers {
+		if _, err := matchers.Parse(s); err != nil {
+			allErrs = append(allErrs, field.Invalid(rolloutPath.Child("canaryMatchers").Index(i), s, err.Error()))
+		}
+	}
+	if rollout.BakeTime == nil {
+		return admission.Warnings{fmt.Sprintf("spec.rollout: canaries are only promoted by the %s annotation",
+			fleetmanagementv1alpha1.PromoteAnnotation)}, allErrs
+	}
+	if rollout.BakeTime.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(rolloutPath.Child("bakeTime"), rollout.BakeTime.String(), "must be positive"))
+	}
+	return nil, allErrs
+}
+
+// validateSecretRefs checks that the Secrets referenced in spec.contents are
+// listed in spec.secretRefs. References in templates and fragments are only
+// checked by the reconciler.
+func (v *PipelineCustomValidator) validateSecretRefs(pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	refs := secretref.References(pipeline.Spec.Contents)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	if len(pipeline.Spec.SecretRefs) == 0 {
+		return admission.Warnings{"spec.contents: Secret references are only substituted when spec.secretRefs is set"}, nil
+	}
+
+	listed := make(map[string]bool, len(pipeline.Spec.SecretRefs))
+	for _, ref := range pipeline.Spec.SecretRefs {
+		listed[ref.Name] = true
+	}
+	var allErrs field.ErrorList
+	for _, ref := range refs {
+		if !listed[ref.Secret] {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "contents"), "${secret:"+ref.String()+"}",
+				fmt.Sprintf("Secret %q is not listed in spec.secretRefs", ref.Secret)))
+		}
+	}
+	return nil, allErrs
+}
+
+// validateCredentials scans spec.contents for plaintext credentials. Contents
+// rendered from templates and fragments are scanned by the reconciler.
+func (v *PipelineCustomValidator) validateCredentials(pipeline *fleetmanagementv1alpha1.Pipeline) (admission.Warnings, field.ErrorList) {
+	if v.CredentialScan == "" || v.CredentialScan == credscan.ModeOff {
+		return nil, nil
+	}
+
+	contentsPath := field.NewPath("spec", "contents")
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+	for _, finding := range credscan.ScanPipeline(pipeline, pipeline.Spec.Contents) {
+		msg := fmt.Sprintf("%s; use spec.secretRefs or allow the rule with the %s annotation",
+			finding, credscan.AllowAnnotation)
+		if v.CredentialScan == credscan.ModeDeny {
+			allErrs = append(allErrs, field.Forbidden(contentsPath, msg))
+		} else {
+			warnings = append(warnings, contentsPath.String()+": "+msg)
+		}
+	}
+	return warnings, allErrs
+}
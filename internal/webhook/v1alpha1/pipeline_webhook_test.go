@@ -0,0 +1,709 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/credscan"
+)
+
+var _ = Describe("Pipeline Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *fleetmanagementv1alpha1.Pipeline
+		validator *PipelineCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &fleetmanagementv1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "node-metrics",
+				Namespace: "default",
+			},
+			Spec: fleetmanagementv1alpha1.PipelineSpec{
+				Contents:       "prometheus.exporter.self \"alloy\" { }",
+				Enabled:        true,
+				ConfigType:     fleetmanagementv1alpha1.ConfigTypeAlloy,
+				NamingStrategy: fleetmanagementv1alpha1.NamingStrategyNamespacedName,
+			},
+		}
+		validator = &PipelineCustomValidator{}
+	})
+
+	Context("When validating the remote pipeline name", func() {
+		It("Should admit a generated name", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should only warn about a metadata.name that is not an Alloy identifier", func() {
+			obj.Spec.NamingStrategy = fleetmanagementv1alpha1.NamingStrategyMetadataName
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("metadata.name")))
+		})
+
+		It("Should deny a spec.name that is not an Alloy identifier", func() {
+			obj.Spec.Name = "node-metrics"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.name")))
+		})
+
+		It("Should only warn about an unchanged invalid spec.name on update", func() {
+			obj.Spec.Name = "node-metrics"
+			oldObj := obj.DeepCopy()
+			obj.Spec.Enabled = false
+			warnings, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("Should deny a name template that does not render", func() {
+			obj.Spec.NamingStrategy = fleetmanagementv1alpha1.NamingStrategyTemplate
+			obj.Spec.NameTemplate = "{{ .Labels.team }}"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.nameTemplate")))
+		})
+	})
+
+	Context("When validating matchers", func() {
+		It("Should report the offending matcher and its index", func() {
+			obj.Spec.Matchers = []string{"collector.os=linux", "env=~[prod"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.matchers[1]")))
+			Expect(err).To(MatchError(ContainSubstring("env=~[prod")))
+		})
+
+		It("Should validate structured matchers", func() {
+			obj.Spec.StructuredMatchers = []fleetmanagementv1alpha1.Matcher{
+				{Key: "region", Op: fleetmanagementv1alpha1.MatchOperatorRegexp, Value: "us-("},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.structuredMatchers[0]")))
+		})
+
+		It("Should admit valid matchers", func() {
+			obj.Spec.Matchers = []string{"collector.os=linux", "region=~us-.*"}
+			obj.Spec.StructuredMatchers = []fleetmanagementv1alpha1.Matcher{
+				{Key: "env", Op: fleetmanagementv1alpha1.MatchOperatorNotEqual, Value: "dev"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When validating template references", func() {
+		var tmpl *fleetmanagementv1alpha1.PipelineTemplate
+
+		BeforeEach(func() {
+			tmpl = &fleetmanagementv1alpha1.PipelineTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "scrape", Namespace: "default"},
+				Spec: fleetmanagementv1alpha1.PipelineTemplateSpec{
+					Contents: "prometheus.remote_write \"default\" { endpoint { url = {{ quote .Params.url }} } }",
+					Parameters: []fleetmanagementv1alpha1.TemplateParameter{
+						{Name: "url", Type: fleetmanagementv1alpha1.ParameterTypeString, Required: true},
+					},
+				},
+			}
+			scheme := runtime.NewScheme()
+			Expect(fleetmanagementv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(tmpl).Build()
+
+			obj.Spec.Contents = ""
+			obj.Spec.TemplateRef = &fleetmanagementv1alpha1.TemplateReference{Name: "scrape"}
+		})
+
+		It("Should admit valid parameters", func() {
+			obj.Spec.Parameters = map[string]apiextensionsv1.JSON{"url": {Raw: []byte(`"https://prom.example.com"`)}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny parameters that do not match the template", func() {
+			obj.Spec.Parameters = map[string]apiextensionsv1.JSON{"url": {Raw: []byte(`42`)}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.parameters[url]")))
+		})
+
+		It("Should deny contents together with templateRef", func() {
+			obj.Spec.Contents = "prometheus.exporter.self \"alloy\" { }"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.contents")))
+		})
+
+		It("Should only warn when the template does not exist yet", func() {
+			obj.Spec.TemplateRef.Name = "missing"
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("not found")))
+		})
+	})
+
+	Context("When selecting fragments", func() {
+		It("Should admit a fragmentSelector without contents", func() {
+			obj.Spec.Contents = ""
+			obj.Spec.FragmentSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"pipeline": "shared"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should require contents, templateRef or fragmentSelector", func() {
+			obj.Spec.Contents = ""
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("one of contents, templateRef or fragmentSelector must be set")))
+		})
+
+		It("Should deny an invalid fragmentSelector", func() {
+			obj.Spec.FragmentSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpIn},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.fragmentSelector")))
+		})
+	})
+
+	Context("When scheduling pipelines", func() {
+		It("Should admit a valid schedule", func() {
+			obj.Spec.Schedule = &fleetmanagementv1alpha1.PipelineSchedule{
+				TimeZone: "Europe/Paris",
+				Windows: []fleetmanagementv1alpha1.ScheduleWindow{
+					{Start: "0 9 * * MON-FRI", Duration: metav1.Duration{Duration: 8 * time.Hour}},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny an invalid cron expression and time zone", func() {
+			obj.Spec.Schedule = &fleetmanagementv1alpha1.PipelineSchedule{
+				TimeZone: "Nowhere/Special",
+				Windows: []fleetmanagementv1alpha1.ScheduleWindow{
+					{Start: "0 9 * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.timeZone")))
+			Expect(err).To(MatchError(ContainSubstring("spec.schedule.windows[0].start")))
+		})
+	})
+
+	Context("When creating debug pipelines", func() {
+		It("Should admit a debug pipeline with a short TTL", func() {
+			obj.Spec.Debug = &fleetmanagementv1alpha1.DebugTarget{CollectorID: "host-1", TTL: metav1.Duration{Duration: time.Hour}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny a TTL longer than a week", func() {
+			obj.Spec.Debug = &fleetmanagementv1alpha1.DebugTarget{CollectorID: "host-1", TTL: metav1.Duration{Duration: 30 * 24 * time.Hour}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.debug.ttl")))
+		})
+
+		It("Should deny adding or changing debug after creation", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.Debug = &fleetmanagementv1alpha1.DebugTarget{CollectorID: "host-1", TTL: metav1.Duration{Duration: time.Hour}}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.debug")))
+
+			oldObj = obj.DeepCopy()
+			obj.Spec.Debug.TTL = metav1.Duration{Duration: 2 * time.Hour}
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.debug")))
+
+			oldObj = obj.DeepCopy()
+			obj.Spec.Contents = "logging { }"
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When rolling out through a canary", func() {
+		It("Should warn when canaries are only promoted by annotation", func() {
+			obj.Spec.Rollout = &fleetmanagementv1alpha1.RolloutStrategy{CanaryMatchers: []string{"canary=true"}}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring(fleetmanagementv1alpha1.PromoteAnnotation)))
+		})
+
+		It("Should deny invalid canary matchers", func() {
+			obj.Spec.Rollout = &fleetmanagementv1alpha1.RolloutStrategy{
+				CanaryMatchers: []string{"canary"},
+				BakeTime:       &metav1.Duration{Duration: time.Hour},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.rollout.canaryMatchers[0]")))
+		})
+	})
+
+	Context("When recording approvals", func() {
+		request := func(user string, old *fleetmanagementv1alpha1.Pipeline) context.Context {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: user},
+			}}
+			if old != nil {
+				raw, err := json.Marshal(old)
+				Expect(err).ToNot(HaveOccurred())
+				req.Operation = admissionv1.Update
+				req.OldObject = runtime.RawExtension{Raw: raw}
+			}
+			return admission.NewContextWithRequest(ctx, req)
+		}
+
+		It("Should record who changes the spec and who approves it", func() {
+			defaulter := &PipelineCustomDefaulter{}
+			obj.Annotations = map[string]string{fleetmanagementv1alpha1.ChangedByAnnotation: "mallory"}
+			Expect(defaulter.Default(request("alice", nil), obj)).To(Succeed())
+			Expect(obj.Annotations).To(HaveKeyWithValue(fleetmanagementv1alpha1.ChangedByAnnotation, "alice"))
+
+			By("approving as another user")
+			oldObj := obj.DeepCopy()
+			obj.Annotations[fleetmanagementv1alpha1.ApproveAnnotation] = "abc"
+			Expect(defaulter.Default(request("bob", oldObj), obj)).To(Succeed())
+			Expect(obj.Annotations).To(HaveKeyWithValue(fleetmanagementv1alpha1.ChangedByAnnotation, "alice"))
+			Expect(obj.Annotations).To(HaveKeyWithValue(fleetmanagementv1alpha1.ApprovedByAnnotation, "bob"))
+
+			By("forging the approver")
+			oldObj = obj.DeepCopy()
+			obj.Annotations[fleetmanagementv1alpha1.ApprovedByAnnotation] = "carol"
+			Expect(defaulter.Default(request("alice", oldObj), obj)).To(Succeed())
+			Expect(obj.Annotations).To(HaveKeyWithValue(fleetmanagementv1alpha1.ApprovedByAnnotation, "bob"))
+		})
+
+		It("Should deny approving one's own change", func() {
+			oldObj := obj.DeepCopy()
+			obj.Annotations = map[string]string{
+				fleetmanagementv1alpha1.ApproveAnnotation:    "abc",
+				fleetmanagementv1alpha1.ApprovedByAnnotation: "alice",
+				fleetmanagementv1alpha1.ChangedByAnnotation:  "alice",
+			}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("cannot approve it")))
+		})
+
+		It("Should deny approving a change rendered from one's own template", func() {
+			obj.Status.Template = &fleetmanagementv1alpha1.ObservedResource{Name: "metrics", ChangedBy: "carol"}
+			oldObj := obj.DeepCopy()
+			obj.Status = fleetmanagementv1alpha1.PipelineStatus{}
+			obj.Annotations = map[string]string{
+				fleetmanagementv1alpha1.ApproveAnnotation:    "abc",
+				fleetmanagementv1alpha1.ApprovedByAnnotation: "carol",
+				fleetmanagementv1alpha1.ChangedByAnnotation:  "alice",
+			}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("cannot approve it")))
+		})
+	})
+
+	Context("When checking tenancy policies", func() {
+		BeforeEach(func() {
+			maxMatchers := int32(2)
+			policies := []runtime.Object{
+				&fleetmanagementv1alpha1.PipelinePolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "tenancy", Namespace: "default"},
+					Spec: fleetmanagementv1alpha1.PipelinePolicySpec{
+						RequiredMatchers:     []string{"team=${namespace}"},
+						ForbiddenMatcherKeys: []string{"team"},
+						AlloyComponents:      &fleetmanagementv1alpha1.ComponentLists{Denied: []string{"local.file", "remote.*"}},
+					},
+				},
+				&fleetmanagementv1alpha1.ClusterPipelinePolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "limits"},
+					Spec:       fleetmanagementv1alpha1.PipelinePolicySpec{MaxMatchers: &maxMatchers},
+				},
+			}
+			scheme := runtime.NewScheme()
+			Expect(fleetmanagementv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Reader = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(policies...).Build()
+		})
+
+		It("Should admit a compliant Pipeline", func() {
+			obj.Spec.Matchers = []string{"team=default", "env=prod"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny a Pipeline missing a required matcher", func() {
+			obj.Spec.Matchers = []string{"env=prod"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("requires the matcher team=default")))
+		})
+
+		It("Should deny a Pipeline matching on a forbidden key", func() {
+			obj.Spec.Matchers = []string{"team=default", "team=payments"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("forbids matching on team")))
+		})
+
+		It("Should deny a Pipeline exceeding a cluster policy", func() {
+			obj.Spec.Matchers = []string{"team=default", "env=prod", "region=eu"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("ClusterPipelinePolicy limits allows at most 2 matchers")))
+		})
+
+		It("Should deny denied components with their block and line", func() {
+			obj.Spec.Matchers = []string{"team=default"}
+			obj.Spec.Contents = "prometheus.exporter.self \"alloy\" { }\n\nremote.http \"config\" {\n  url = \"https://example.com\"\n}\n"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(`denies component remote.http "config" at line 3`)))
+		})
+
+		It("Should ignore policies of other namespaces", func() {
+			obj.Namespace = "other"
+			obj.Spec.Matchers = []string{"env=prod"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should admit removing the finalizer of a Pipeline violating a policy added later", func() {
+			obj.Spec.Matchers = []string{"env=prod"}
+			now := metav1.Now()
+			obj.Finalizers = []string{"pipeline.fleetmanagement.grafana.com/finalizer"}
+			obj.DeletionTimestamp = &now
+			oldObj := obj.DeepCopy()
+			obj.Finalizers = nil
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When evaluating PipelineValidationRules", func() {
+		BeforeEach(func() {
+			rules := []runtime.Object{
+				&fleetmanagementv1alpha1.PipelineValidationRule{
+					ObjectMeta: metav1.ObjectMeta{Name: "owner"},
+					Spec: fleetmanagementv1alpha1.PipelineValidationRuleSpec{
+						Expression: `object.spec.enabled || ("annotations" in object.metadata && "owner" in object.metadata.annotations)`,
+						Message:    "disabled Pipelines must carry an owner annotation",
+						Action:     fleetmanagementv1alpha1.ValidationActionDeny,
+					},
+				},
+				&fleetmanagementv1alpha1.PipelineValidationRule{
+					ObjectMeta: metav1.ObjectMeta{Name: "no-self-exporter"},
+					Spec: fleetmanagementv1alpha1.PipelineValidationRuleSpec{
+						Expression: `!("prometheus.exporter.self" in alloyBlocks(contents))`,
+						Action:     fleetmanagementv1alpha1.ValidationActionWarn,
+						Namespaces: []string{"default"},
+					},
+				},
+			}
+			scheme := runtime.NewScheme()
+			Expect(fleetmanagementv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Reader = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(rules...).Build()
+		})
+
+		It("Should warn about Pipelines failing a Warn rule", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("PipelineValidationRule no-self-exporter: failed")))
+		})
+
+		It("Should deny Pipelines failing a Deny rule", func() {
+			obj.Spec.Enabled = false
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("disabled Pipelines must carry an owner annotation")))
+
+			obj.Annotations = map[string]string{"owner": "team-a"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should only warn about rules that fail to evaluate", func() {
+			obj.Namespace = "other"
+			obj.Spec.Contents = "logging {"
+			obj.Spec.Enabled = false
+			obj.Annotations = map[string]string{"owner": "team-a"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+
+			obj.Namespace = "default"
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("could not be evaluated")))
+		})
+
+		It("Should admit adding the finalizer to a Pipeline failing a Deny rule added later", func() {
+			obj.Spec.Enabled = false
+			oldObj := obj.DeepCopy()
+			obj.Finalizers = []string{"pipeline.fleetmanagement.grafana.com/finalizer"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny removing the annotation a Deny rule requires", func() {
+			obj.Spec.Enabled = false
+			obj.Annotations = map[string]string{"owner": "team-a"}
+			oldObj := obj.DeepCopy()
+			obj.Annotations = nil
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("disabled Pipelines must carry an owner annotation")))
+		})
+	})
+
+	Context("When enforcing PipelineQuotas", func() {
+		BeforeEach(func() {
+			pipelines, enabled := int64(2), int64(1)
+			objects := []runtime.Object{
+				&fleetmanagementv1alpha1.PipelineQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+					Spec: fleetmanagementv1alpha1.PipelineQuotaSpec{Hard: fleetmanagementv1alpha1.PipelineQuotaLimits{
+						Pipelines: &pipelines, EnabledPipelines: &enabled,
+					}},
+				},
+				&fleetmanagementv1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+					Spec:       fleetmanagementv1alpha1.PipelineSpec{Enabled: true},
+				},
+			}
+			scheme := runtime.NewScheme()
+			Expect(fleetmanagementv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Reader = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+		})
+
+		It("Should admit Pipelines within the quota", func() {
+			obj.Spec.Enabled = false
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny Pipelines exceeding the quota", func() {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(`exceeded PipelineQuota "default": enabledPipelines would be 2, limited to 1`)))
+		})
+
+		It("Should admit updates that do not increase usage", func() {
+			obj.Name = "existing"
+			oldObj := obj.DeepCopy()
+			obj.Spec.Contents = "logging { }"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When scanning for plaintext credentials", func() {
+		BeforeEach(func() {
+			obj.Spec.Contents = "prometheus.remote_write \"default\" {\n  endpoint {\n    bearer_token = \"hunter2\"\n  }\n}"
+		})
+
+		It("Should warn in warn mode", func() {
+			validator.CredentialScan = credscan.ModeWarn
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("line 3: string literal assigned to a credential attribute")))
+			Expect(warnings).ToNot(ContainElement(ContainSubstring("hunter2")))
+		})
+
+		It("Should deny in deny mode", func() {
+			validator.CredentialScan = credscan.ModeDeny
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("alloy-literal-secret")))
+		})
+
+		It("Should honor the allow-list annotation", func() {
+			validator.CredentialScan = credscan.ModeDeny
+			obj.Annotations = map[string]string{credscan.AllowAnnotation: "alloy-literal-secret"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should admit adding the finalizer to a violating Pipeline", func() {
+			validator.CredentialScan = credscan.ModeDeny
+			oldObj := obj.DeepCopy()
+			obj.Finalizers = []string{"pipeline.fleetmanagement.grafana.com/finalizer"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny removing the allow-list annotation", func() {
+			validator.CredentialScan = credscan.ModeDeny
+			obj.Annotations = map[string]string{credscan.AllowAnnotation: "alloy-literal-secret"}
+			oldObj := obj.DeepCopy()
+			obj.Annotations = map[string]string{fleetmanagementv1alpha1.PausedAnnotation: "true"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("alloy-literal-secret")))
+		})
+
+		It("Should admit removing the finalizer of a violating Pipeline", func() {
+			validator.CredentialScan = credscan.ModeDeny
+			now := metav1.Now()
+			obj.Finalizers = []string{"pipeline.fleetmanagement.grafana.com/finalizer"}
+			obj.DeletionTimestamp = &now
+			oldObj := obj.DeepCopy()
+			obj.Finalizers = nil
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When validating Secret references", func() {
+		BeforeEach(func() {
+			obj.Spec.Contents = `prometheus.remote_write "default" { endpoint { url = "${secret:prom/url}" } }`
+		})
+
+		It("Should admit references to listed Secrets", func() {
+			obj.Spec.SecretRefs = []fleetmanagementv1alpha1.SecretReference{{Name: "prom"}}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny references to Secrets not in secretRefs", func() {
+			obj.Spec.SecretRefs = []fleetmanagementv1alpha1.SecretReference{{Name: "other"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(`Secret "prom" is not listed in spec.secretRefs`)))
+		})
+
+		It("Should warn that references are not substituted without secretRefs", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("only substituted when spec.secretRefs is set")))
+		})
+	})
+
+	Context("When validating module references", func() {
+		BeforeEach(func() {
+			obj.Spec.Modules = []fleetmanagementv1alpha1.ModuleReference{{Name: "remote-write"}}
+		})
+
+		It("Should deny modules for OpenTelemetry pipelines", func() {
+			obj.Spec.ConfigType = fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.modules")))
+		})
+
+		It("Should only warn when a module does not exist yet", func() {
+			scheme := runtime.NewScheme()
+			Expect(fleetmanagementv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Reader = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring(`PipelineModule "remote-write" not found`)))
+		})
+	})
+})
+
+var _ = Describe("PipelineTemplate Webhook", func() {
+	It("Should deny templates that do not parse", func() {
+		tmpl := &fleetmanagementv1alpha1.PipelineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+			Spec:       fleetmanagementv1alpha1.PipelineTemplateSpec{Contents: "{{ .Params.url "},
+		}
+		_, err := (&PipelineTemplateCustomValidator{}).ValidateCreate(context.Background(), tmpl)
+		Expect(err).To(MatchError(ContainSubstring("spec.contents")))
+	})
+
+	It("Should record who changes the spec", func() {
+		request := func(user string, old *fleetmanagementv1alpha1.PipelineTemplate) context.Context {
+			raw, err := json.Marshal(old)
+			Expect(err).ToNot(HaveOccurred())
+			return admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					UserInfo:  authenticationv1.UserInfo{Username: user},
+					OldObject: runtime.RawExtension{Raw: raw},
+				},
+			})
+		}
+		defaulter := &PipelineTemplateCustomDefaulter{}
+		tmpl := &fleetmanagementv1alpha1.PipelineTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "metrics",
+				Namespace:   "default",
+				Annotations: map[string]string{fleetmanagementv1alpha1.ChangedByAnnotation: "alice"},
+			},
+			Spec: fleetmanagementv1alpha1.PipelineTemplateSpec{Contents: "old"},
+		}
+
+		By("changing only the annotations")
+		oldTmpl := tmpl.DeepCopy()
+		tmpl.Annotations[fleetmanagementv1alpha1.ChangedByAnnotation] = "mallory"
+		Expect(defaulter.Default(request("mallory", oldTmpl), tmpl)).To(Succeed())
+		Expect(tmpl.Annotations).To(HaveKeyWithValue(fleetmanagementv1alpha1.ChangedByAnnotation, "alice"))
+
+		By("changing the spec")
+		oldTmpl = tmpl.DeepCopy()
+		tmpl.Spec.Contents = "new"
+		Expect(defaulter.Default(request("carol", oldTmpl), tmpl)).To(Succeed())
+		Expect(tmpl.Annotations).To(HaveKeyWithValue(fleetmanagementv1alpha1.ChangedByAnnotation, "carol"))
+	})
+})
+
+var _ = Describe("PipelineModule Webhook", func() {
+	var module *fleetmanagementv1alpha1.PipelineModule
+
+	BeforeEach(func() {
+		module = &fleetmanagementv1alpha1.PipelineModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote-write", Namespace: "default"},
+			Spec: fleetmanagementv1alpha1.PipelineModuleSpec{
+				Contents: "declare \"write\" {\n  argument \"url\" { }\n}",
+			},
+		}
+	})
+
+	It("Should admit a module with declarations", func() {
+		_, err := (&PipelineModuleCustomValidator{}).ValidateCreate(context.Background(), module)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should deny a module without declare blocks", func() {
+		module.Spec.Contents = "prometheus.exporter.self \"alloy\" { }"
+		_, err := (&PipelineModuleCustomValidator{}).ValidateCreate(context.Background(), module)
+		Expect(err).To(MatchError(ContainSubstring("spec.contents")))
+	})
+
+	It("Should deny a module depending on itself", func() {
+		module.Spec.DependsOn = []string{"remote-write"}
+		_, err := (&PipelineModuleCustomValidator{}).ValidateCreate(context.Background(), module)
+		Expect(err).To(MatchError(ContainSubstring("spec.dependsOn[0]")))
+	})
+})
+
+var _ = Describe("PipelineKillSwitch Webhook", func() {
+	It("Should deny an invalid remoteNamePattern", func() {
+		ks := &fleetmanagementv1alpha1.PipelineKillSwitch{
+			ObjectMeta: metav1.ObjectMeta{Name: "flood"},
+			Spec:       fleetmanagementv1alpha1.PipelineKillSwitchSpec{RemoteNamePattern: "team_a_(.*"},
+		}
+		_, err := (&PipelineKillSwitchCustomValidator{}).ValidateCreate(context.Background(), ks)
+		Expect(err).To(MatchError(ContainSubstring("spec.remoteNamePattern")))
+	})
+
+	It("Should require a selector or a remoteNamePattern", func() {
+		ks := &fleetmanagementv1alpha1.PipelineKillSwitch{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}
+		_, err := (&PipelineKillSwitchCustomValidator{}).ValidateCreate(context.Background(), ks)
+		Expect(err).To(MatchError(ContainSubstring("one of selector or remoteNamePattern must be set")))
+	})
+})
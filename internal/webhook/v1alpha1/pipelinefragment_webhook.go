@@ -0,0 +1,48 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// SetupPipelineFragmentWebhookWithManager registers the webhook for PipelineFragment in the manager.
+func SetupPipelineFragmentWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.PipelineFragment{}).
+		WithDefaulter(&PipelineFragmentCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-fleetmanagement-grafana-com-v1alpha1-pipelinefragment,mutating=true,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinefragments,verbs=create;update,versions=v1alpha1,name=mpipelinefragment-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineFragmentCustomDefaulter records who changes the spec of a PipelineFragment, so that
+// they cannot approve the changes it renders into gated Pipelines.
+type PipelineFragmentCustomDefaulter struct{}
+
+var _ admission.Defaulter[*fleetmanagementv1alpha1.PipelineFragment] = &PipelineFragmentCustomDefaulter{}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type PipelineFragment.
+func (d *PipelineFragmentCustomDefaulter) Default(ctx context.Context, fragment *fleetmanagementv1alpha1.PipelineFragment) error {
+	return recordChangedBy(ctx, fragment, &fleetmanagementv1alpha1.PipelineFragment{}, func(fragment *fleetmanagementv1alpha1.PipelineFragment) any {
+		return fragment.Spec
+	})
+}
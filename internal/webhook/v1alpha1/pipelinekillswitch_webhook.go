@@ -0,0 +1,94 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var pipelinekillswitchlog = logf.Log.WithName("pipelinekillswitch-resource")
+
+// SetupPipelineKillSwitchWebhookWithManager registers the webhook for PipelineKillSwitch in the manager.
+func SetupPipelineKillSwitchWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.PipelineKillSwitch{}).
+		WithValidator(&PipelineKillSwitchCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-pipelinekillswitch,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinekillswitches,verbs=create;update,versions=v1alpha1,name=vpipelinekillswitch-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineKillSwitchCustomValidator validates PipelineKillSwitch resources when they are created or updated.
+type PipelineKillSwitchCustomValidator struct{}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.PipelineKillSwitch] = &PipelineKillSwitchCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type PipelineKillSwitch.
+func (v *PipelineKillSwitchCustomValidator) ValidateCreate(_ context.Context, ks *fleetmanagementv1alpha1.PipelineKillSwitch) (admission.Warnings, error) {
+	pipelinekillswitchlog.V(1).Info("validation for PipelineKillSwitch upon creation", "name", ks.GetName())
+
+	return nil, v.validate(ks)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type PipelineKillSwitch.
+func (v *PipelineKillSwitchCustomValidator) ValidateUpdate(_ context.Context, _, ks *fleetmanagementv1alpha1.PipelineKillSwitch) (admission.Warnings, error) {
+	pipelinekillswitchlog.V(1).Info("validation for PipelineKillSwitch upon update", "name", ks.GetName())
+
+	return nil, v.validate(ks)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type PipelineKillSwitch.
+func (v *PipelineKillSwitchCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.PipelineKillSwitch) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PipelineKillSwitchCustomValidator) validate(ks *fleetmanagementv1alpha1.PipelineKillSwitch) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if ks.Spec.Selector == nil && ks.Spec.RemoteNamePattern == "" {
+		allErrs = append(allErrs, field.Required(specPath, "one of selector or remoteNamePattern must be set"))
+	}
+	if ks.Spec.Selector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(ks.Spec.Selector,
+			metav1validation.LabelSelectorValidationOptions{}, specPath.Child("selector"))...)
+	}
+	if ks.Spec.RemoteNamePattern != "" {
+		if _, err := regexp.Compile(ks.Spec.RemoteNamePattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("remoteNamePattern"),
+				ks.Spec.RemoteNamePattern, err.Error()))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		fleetmanagementv1alpha1.GroupVersion.WithKind("PipelineKillSwitch").GroupKind(),
+		ks.Name, allErrs)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/modules"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var pipelinemodulelog = logf.Log.WithName("pipelinemodule-resource")
+
+// SetupPipelineModuleWebhookWithManager registers the webhook for PipelineModule in the manager.
+func SetupPipelineModuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.PipelineModule{}).
+		WithValidator(&PipelineModuleCustomValidator{}).
+		WithDefaulter(&PipelineModuleCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-fleetmanagement-grafana-com-v1alpha1-pipelinemodule,mutating=true,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinemodules,verbs=create;update,versions=v1alpha1,name=mpipelinemodule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineModuleCustomDefaulter records who changes the spec of a PipelineModule, so that
+// they cannot approve the changes it renders into gated Pipelines.
+type PipelineModuleCustomDefaulter struct{}
+
+var _ admission.Defaulter[*fleetmanagementv1alpha1.PipelineModule] = &PipelineModuleCustomDefaulter{}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type PipelineModule.
+func (d *PipelineModuleCustomDefaulter) Default(ctx context.Context, module *fleetmanagementv1alpha1.PipelineModule) error {
+	return recordChangedBy(ctx, module, &fleetmanagementv1alpha1.PipelineModule{}, func(module *fleetmanagementv1alpha1.PipelineModule) any {
+		return module.Spec
+	})
+}
+
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-pipelinemodule,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinemodules,verbs=create;update,versions=v1alpha1,name=vpipelinemodule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineModuleCustomValidator validates PipelineModule resources when they are created or updated.
+type PipelineModuleCustomValidator struct{}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.PipelineModule] = &PipelineModuleCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type PipelineModule.
+func (v *PipelineModuleCustomValidator) ValidateCreate(_ context.Context, module *fleetmanagementv1alpha1.PipelineModule) (admission.Warnings, error) {
+	pipelinemodulelog.V(1).Info("validation for PipelineModule upon creation", "name", module.GetName())
+
+	return nil, v.validate(module)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type PipelineModule.
+func (v *PipelineModuleCustomValidator) ValidateUpdate(_ context.Context, _, module *fleetmanagementv1alpha1.PipelineModule) (admission.Warnings, error) {
+	pipelinemodulelog.V(1).Info("validation for PipelineModule upon update", "name", module.GetName())
+
+	return nil, v.validate(module)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type PipelineModule.
+func (v *PipelineModuleCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.PipelineModule) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PipelineModuleCustomValidator) validate(module *fleetmanagementv1alpha1.PipelineModule) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if len(modules.Declarations(module.Spec.Contents)) == 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("contents"), "",
+			"contents must contain at least one declare block"))
+	}
+	for i, dep := range module.Spec.DependsOn {
+		if dep == module.Name {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("dependsOn").Index(i), dep,
+				"a PipelineModule cannot depend on itself"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		fleetmanagementv1alpha1.GroupVersion.WithKind("PipelineModule").GroupKind(),
+		module.Name, allErrs)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/policy"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var pipelinepolicylog = logf.Log.WithName("pipelinepolicy-resource")
+
+// SetupPipelinePolicyWebhookWithManager registers the webhooks for PipelinePolicy
+// and ClusterPipelinePolicy in the manager.
+func SetupPipelinePolicyWebhookWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.PipelinePolicy{}).
+		WithValidator(&PipelinePolicyCustomValidator{}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.ClusterPipelinePolicy{}).
+		WithValidator(&ClusterPipelinePolicyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-pipelinepolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinepolicies,verbs=create;update,versions=v1alpha1,name=vpipelinepolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelinePolicyCustomValidator validates PipelinePolicy resources when they are created or updated.
+type PipelinePolicyCustomValidator struct{}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.PipelinePolicy] = &PipelinePolicyCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type PipelinePolicy.
+func (v *PipelinePolicyCustomValidator) ValidateCreate(_ context.Context, p *fleetmanagementv1alpha1.PipelinePolicy) (admission.Warnings, error) {
+	pipelinepolicylog.V(1).Info("validation for PipelinePolicy upon creation", "name", p.GetName())
+
+	return nil, validatePolicy("PipelinePolicy", p.Name, &p.Spec)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type PipelinePolicy.
+func (v *PipelinePolicyCustomValidator) ValidateUpdate(_ context.Context, _, p *fleetmanagementv1alpha1.PipelinePolicy) (admission.Warnings, error) {
+	pipelinepolicylog.V(1).Info("validation for PipelinePolicy upon update", "name", p.GetName())
+
+	return nil, validatePolicy("PipelinePolicy", p.Name, &p.Spec)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type PipelinePolicy.
+func (v *PipelinePolicyCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.PipelinePolicy) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-clusterpipelinepolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=clusterpipelinepolicies,verbs=create;update,versions=v1alpha1,name=vclusterpipelinepolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClusterPipelinePolicyCustomValidator validates ClusterPipelinePolicy resources when they are created or updated.
+type ClusterPipelinePolicyCustomValidator struct{}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.ClusterPipelinePolicy] = &ClusterPipelinePolicyCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type ClusterPipelinePolicy.
+func (v *ClusterPipelinePolicyCustomValidator) ValidateCreate(_ context.Context, p *fleetmanagementv1alpha1.ClusterPipelinePolicy) (admission.Warnings, error) {
+	pipelinepolicylog.V(1).Info("validation for ClusterPipelinePolicy upon creation", "name", p.GetName())
+
+	return nil, validatePolicy("ClusterPipelinePolicy", p.Name, &p.Spec)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type ClusterPipelinePolicy.
+func (v *ClusterPipelinePolicyCustomValidator) ValidateUpdate(_ context.Context, _, p *fleetmanagementv1alpha1.ClusterPipelinePolicy) (admission.Warnings, error) {
+	pipelinepolicylog.V(1).Info("validation for ClusterPipelinePolicy upon update", "name", p.GetName())
+
+	return nil, validatePolicy("ClusterPipelinePolicy", p.Name, &p.Spec)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type ClusterPipelinePolicy.
+func (v *ClusterPipelinePolicyCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.ClusterPipelinePolicy) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validatePolicy checks the spec of a PipelinePolicy or ClusterPipelinePolicy
+func validatePolicy(kind, name string, spec *fleetmanagementv1alpha1.PipelinePolicySpec) error {
+	allErrs := policy.Validate(spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(fleetmanagementv1alpha1.GroupVersion.WithKind(kind).GroupKind(), name, allErrs)
+}
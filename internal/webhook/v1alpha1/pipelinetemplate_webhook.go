@@ -0,0 +1,93 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/pipelinetemplate"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var pipelinetemplatelog = logf.Log.WithName("pipelinetemplate-resource")
+
+// SetupPipelineTemplateWebhookWithManager registers the webhook for PipelineTemplate in the manager.
+func SetupPipelineTemplateWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.PipelineTemplate{}).
+		WithValidator(&PipelineTemplateCustomValidator{}).
+		WithDefaulter(&PipelineTemplateCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-fleetmanagement-grafana-com-v1alpha1-pipelinetemplate,mutating=true,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinetemplates,verbs=create;update,versions=v1alpha1,name=mpipelinetemplate-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineTemplateCustomDefaulter records who changes the spec of a PipelineTemplate, so that
+// they cannot approve the changes it renders into gated Pipelines.
+type PipelineTemplateCustomDefaulter struct{}
+
+var _ admission.Defaulter[*fleetmanagementv1alpha1.PipelineTemplate] = &PipelineTemplateCustomDefaulter{}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type PipelineTemplate.
+func (d *PipelineTemplateCustomDefaulter) Default(ctx context.Context, tmpl *fleetmanagementv1alpha1.PipelineTemplate) error {
+	return recordChangedBy(ctx, tmpl, &fleetmanagementv1alpha1.PipelineTemplate{}, func(tmpl *fleetmanagementv1alpha1.PipelineTemplate) any {
+		return tmpl.Spec
+	})
+}
+
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-pipelinetemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinetemplates,verbs=create;update,versions=v1alpha1,name=vpipelinetemplate-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineTemplateCustomValidator validates PipelineTemplate resources when they are created or updated.
+type PipelineTemplateCustomValidator struct{}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.PipelineTemplate] = &PipelineTemplateCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type PipelineTemplate.
+func (v *PipelineTemplateCustomValidator) ValidateCreate(_ context.Context, tmpl *fleetmanagementv1alpha1.PipelineTemplate) (admission.Warnings, error) {
+	pipelinetemplatelog.V(1).Info("validation for PipelineTemplate upon creation", "name", tmpl.GetName())
+
+	return nil, v.validate(tmpl)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type PipelineTemplate.
+func (v *PipelineTemplateCustomValidator) ValidateUpdate(_ context.Context, _, tmpl *fleetmanagementv1alpha1.PipelineTemplate) (admission.Warnings, error) {
+	pipelinetemplatelog.V(1).Info("validation for PipelineTemplate upon update", "name", tmpl.GetName())
+
+	return nil, v.validate(tmpl)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type PipelineTemplate.
+func (v *PipelineTemplateCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.PipelineTemplate) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PipelineTemplateCustomValidator) validate(tmpl *fleetmanagementv1alpha1.PipelineTemplate) error {
+	allErrs := pipelinetemplate.Validate(&tmpl.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		fleetmanagementv1alpha1.GroupVersion.WithKind("PipelineTemplate").GroupKind(),
+		tmpl.Name, allErrs)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/validationrule"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var pipelinevalidationrulelog = logf.Log.WithName("pipelinevalidationrule-resource")
+
+// SetupPipelineValidationRuleWebhookWithManager registers the webhook for PipelineValidationRule in the manager.
+func SetupPipelineValidationRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &fleetmanagementv1alpha1.PipelineValidationRule{}).
+		WithValidator(&PipelineValidationRuleCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-fleetmanagement-grafana-com-v1alpha1-pipelinevalidationrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=fleetmanagement.grafana.com,resources=pipelinevalidationrules,verbs=create;update,versions=v1alpha1,name=vpipelinevalidationrule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// PipelineValidationRuleCustomValidator validates PipelineValidationRule resources when they are created or updated.
+type PipelineValidationRuleCustomValidator struct{}
+
+var _ admission.Validator[*fleetmanagementv1alpha1.PipelineValidationRule] = &PipelineValidationRuleCustomValidator{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type PipelineValidationRule.
+func (v *PipelineValidationRuleCustomValidator) ValidateCreate(_ context.Context, rule *fleetmanagementv1alpha1.PipelineValidationRule) (admission.Warnings, error) {
+	pipelinevalidationrulelog.V(1).Info("validation for PipelineValidationRule upon creation", "name", rule.GetName())
+
+	return nil, v.validate(rule)
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type PipelineValidationRule.
+func (v *PipelineValidationRuleCustomValidator) ValidateUpdate(_ context.Context, _, rule *fleetmanagementv1alpha1.PipelineValidationRule) (admission.Warnings, error) {
+	pipelinevalidationrulelog.V(1).Info("validation for PipelineValidationRule upon update", "name", rule.GetName())
+
+	return nil, v.validate(rule)
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type PipelineValidationRule.
+func (v *PipelineValidationRuleCustomValidator) ValidateDelete(_ context.Context, _ *fleetmanagementv1alpha1.PipelineValidationRule) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PipelineValidationRuleCustomValidator) validate(rule *fleetmanagementv1alpha1.PipelineValidationRule) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if _, err := validationrule.Compile(rule.Spec.Expression); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("expression"), rule.Spec.Expression, err.Error()))
+	}
+	if rule.Spec.PipelineSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(rule.Spec.PipelineSelector,
+			metav1validation.LabelSelectorValidationOptions{}, specPath.Child("pipelineSelector"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		fleetmanagementv1alpha1.GroupVersion.WithKind("PipelineValidationRule").GroupKind(),
+		rule.Name, allErrs)
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alloy parses the block structure of Alloy configuration and formats it.
+//
+// Only blocks are kept: their name, label, line and nested blocks. Attribute
+// values are skipped, including the object literals they may contain.
+package alloy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Block is an Alloy block, e.g. prometheus.remote_write "default" { ... }
+type Block struct {
+	// Name is the dotted block name, e.g. "prometheus.remote_write"
+	Name string
+	// Label is the block label, empty for unlabeled blocks
+	Label string
+	// Line is the 1-based line number of the block name
+	Line int
+	// Blocks are the nested blocks
+	Blocks []Block
+}
+
+// ID returns the name and label of the block, e.g. prometheus.remote_write.default
+func (b Block) ID() string {
+	if b.Label == "" {
+		return b.Name
+	}
+	return b.Name + "." + b.Label
+}
+
+// Walk calls fn for every block, parents before their nested blocks
+func Walk(blocks []Block, fn func(b Block)) {
+	for _, b := range blocks {
+		fn(b)
+		Walk(b.Blocks, fn)
+	}
+}
+
+// Parse returns the top-level blocks of Alloy contents
+func Parse(contents string) ([]Block, error) {
+	tokens, err := tokenize(contents)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	blocks, err := p.body(false)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenPunct
+	tokenNewline
+	tokenOther
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// tokenize splits contents into identifiers, strings, punctuation and
+// newlines, dropping comments
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, token{tokenNewline, "\n", line})
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(s[i:], "//"):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(s[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"':
+			start, startLine := i, line
+			i++
+			for i < len(s) && s[i] != '"' {
+				if s[i] == '\\' {
+					i++
+				} else if s[i] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", startLine)
+				}
+				i++
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("line %d: unterminated string", startLine)
+			}
+			i++
+			tokens = append(tokens, token{tokenString, s[start+1 : i-1], startLine})
+		case c == '`':
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated raw string", line)
+			}
+			tokens = append(tokens, token{tokenString, s[i+1 : i+1+end], line})
+			line += strings.Count(s[i:i+2+end], "\n")
+			i += end + 2
+		case isIdentStart(c):
+			start := i
+			for i < len(s) && (isIdentStart(s[i]) || isDigit(s[i]) || s[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, s[start:i], line})
+		case strings.IndexByte("{}[]()=,", c) >= 0:
+			tokens = append(tokens, token{tokenPunct, string(c), line})
+			i++
+		default:
+			tokens = append(tokens, token{tokenOther, string(c), line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek(offset int) *token {
+	if p.pos+offset >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos+offset]
+}
+
+// body parses statements until the end of contents or, in a block, its
+// closing brace
+func (p *parser) body(inBlock bool) ([]Block, error) {
+	var blocks []Block
+	for {
+		t := p.peek(0)
+		switch {
+		case t == nil:
+			if inBlock {
+				return nil, fmt.Errorf("unexpected end of contents, missing }")
+			}
+			return blocks, nil
+		case t.kind == tokenNewline:
+			p.pos++
+		case t.kind == tokenPunct && t.text == "}":
+			if !inBlock {
+				return nil, fmt.Errorf("line %d: unexpected }", t.line)
+			}
+			p.pos++
+			return blocks, nil
+		case t.kind == tokenIdent:
+			block, err := p.statement()
+			if err != nil {
+				return nil, err
+			}
+			if block != nil {
+				blocks = append(blocks, *block)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", t.line, t.text)
+		}
+	}
+}
+
+// statement parses an attribute or a block starting at an identifier. It
+// returns nil for attributes.
+func (p *parser) statement() (*Block, error) {
+	name := p.peek(0)
+	next := p.peek(1)
+	switch {
+	case next != nil && next.kind == tokenPunct && next.text == "=":
+		p.pos += 2
+		return nil, p.expression()
+	case next != nil && next.kind == tokenPunct && next.text == "{":
+		p.pos += 2
+		return p.block(name, "")
+	case next != nil && next.kind == tokenString:
+		brace := p.peek(2)
+		if brace != nil && brace.kind == tokenPunct && brace.text == "{" {
+			p.pos += 3
+			return p.block(name, next.text)
+		}
+	}
+	return nil, fmt.Errorf("line %d: expected attribute or block after %q", name.line, name.text)
+}
+
+func (p *parser) block(name *token, label string) (*Block, error) {
+	nested, err := p.body(true)
+	if err != nil {
+		return nil, err
+	}
+	return &Block{Name: name.text, Label: label, Line: name.line, Blocks: nested}, nil
+}
+
+// expression skips an attribute value, up to the first newline or closing
+// block brace outside of brackets
+func (p *parser) expression() error {
+	var open []string
+	for {
+		t := p.peek(0)
+		if t == nil {
+			if len(open) > 0 {
+				return fmt.Errorf("unexpected end of contents, missing %s", open[len(open)-1])
+			}
+			return nil
+		}
+		if len(open) == 0 && (t.kind == tokenNewline || (t.kind == tokenPunct && t.text == "}")) {
+			return nil
+		}
+		if t.kind == tokenPunct {
+			switch t.text {
+			case "{":
+				open = append(open, "}")
+			case "[":
+				open = append(open, "]")
+			case "(":
+				open = append(open, ")")
+			case "}", "]", ")":
+				if len(open) == 0 || open[len(open)-1] != t.text {
+					return fmt.Errorf("line %d: unexpected %s", t.line, t.text)
+				}
+				open = open[:len(open)-1]
+			}
+		}
+		p.pos++
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alloy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	contents := `// Scrape and forward
+logging {
+  level = "info"
+}
+
+prometheus.scrape "pods" {
+  targets    = discovery.kubernetes.pods.targets
+  forward_to = [prometheus.remote_write.default.receiver]
+  /* a { b } */
+  clustering {
+    enabled = true
+  }
+}
+
+prometheus.remote_write "default" {
+  endpoint {
+    url = "https://prom.example.com/{path}"
+  }
+  external_labels = {
+    cluster = "prod",
+  }
+}
+`
+	blocks, err := Parse(contents)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var got []string
+	Walk(blocks, func(b Block) { got = append(got, b.ID()) })
+	want := []string{"logging", "prometheus.scrape.pods", "clustering", "prometheus.remote_write.default", "endpoint"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("blocks = %v, want %v", got, want)
+	}
+
+	lines := []int{blocks[0].Line, blocks[1].Line, blocks[1].Blocks[0].Line, blocks[2].Line}
+	if want := []int{2, 6, 10, 15}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %v, want %v", lines, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"missing brace", "logging {\n  level = \"info\"\n"},
+		{"extra brace", "logging { }\n}"},
+		{"unterminated string", "logging {\n  level = \"info\n}"},
+		{"unterminated comment", "/* logging"},
+		{"unbalanced list", "a = [1, 2)"},
+		{"dangling identifier", "logging\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.contents); err == nil {
+				t.Errorf("Parse(%q) succeeded", tt.contents)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alloy
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// attributeRE matches the key of an attribute or object field, e.g. `url =` or `"job" =`
+	attributeRE = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*|"(?:[^"\\]|\\.)*") ?=($|[^=])`)
+
+	// blockRE matches a block header, e.g. `prometheus.scrape "default" {`
+	blockRE = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.]*)(?: ?("(?:[^"\\]|\\.)*"))? ?\{`)
+)
+
+// formatLine is a line of contents being formatted
+type formatLine struct {
+	// text is the line without indentation
+	text string
+	// verbatim lines start inside a multi-line string or comment and are kept as they are
+	verbatim bool
+	// indent is the number of tabs the line is indented with
+	indent int
+	// delta is the number of brackets the line opens minus those it closes
+	delta int
+	// opens is true when the code of the line ends with an opening bracket
+	opens bool
+	// closers is the number of closing brackets the line starts with
+	closers int
+	// key is the length of the attribute key, or 0 for other lines
+	key int
+}
+
+// lineScanner normalizes lines, carrying multi-line strings and comments over
+type lineScanner struct {
+	inRaw     bool
+	inComment bool
+}
+
+// Format lays out Alloy contents the way alloy fmt does: block bodies are
+// indented with tabs, the = of consecutive attributes are aligned, spaces
+// between tokens are collapsed to one and blank lines are collapsed and
+// removed at the start and end of blocks. Strings and comments are kept as
+// they are. Contents that cannot be parsed are returned with an error.
+func Format(contents string) (string, error) {
+	if _, err := Parse(contents); err != nil {
+		return "", err
+	}
+
+	var lines []formatLine
+	s := &lineScanner{}
+	depth := 0
+	for _, raw := range strings.Split(contents, "\n") {
+		verbatim := s.inRaw || s.inComment
+		l := s.scan(raw)
+		l.verbatim = verbatim
+		if verbatim {
+			l.text = raw
+		}
+		l.indent = max(depth-l.closers, 0)
+		depth += l.delta
+		lines = append(lines, l)
+	}
+
+	alignAttributes(lines)
+
+	var b strings.Builder
+	blank := false
+	for i, l := range lines {
+		if !l.verbatim && l.text == "" {
+			blank = true
+			continue
+		}
+		if blank && b.Len() > 0 && !lines[prevCode(lines, i)].opens && l.closers == 0 {
+			b.WriteByte('\n')
+		}
+		blank = false
+		if !l.verbatim {
+			b.WriteString(strings.Repeat("\t", l.indent))
+		}
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// scan normalizes the spacing of a line outside of strings and comments and
+// counts its brackets
+func (s *lineScanner) scan(line string) formatLine {
+	var b strings.Builder
+	var l formatLine
+	space, leading := false, true
+	var last byte
+
+	for i := 0; i < len(line); {
+		if s.inRaw {
+			end := strings.IndexByte(line[i:], '`')
+			if end < 0 {
+				b.WriteString(line[i:])
+				break
+			}
+			b.WriteString(line[i : i+end+1])
+			i += end + 1
+			s.inRaw = false
+			continue
+		}
+		if s.inComment {
+			end := strings.Index(line[i:], "*/")
+			if end < 0 {
+				b.WriteString(line[i:])
+				break
+			}
+			b.WriteString(line[i : i+end+2])
+			i += end + 2
+			s.inComment = false
+			continue
+		}
+
+		c := line[i]
+		if c == ' ' || c == '\t' || c == '\r' {
+			space = true
+			i++
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(line))
+			b.WriteString(line[i:end])
+			i = end
+			leading = false
+			continue
+		case c == '`':
+			s.inRaw = true
+			b.WriteByte(c)
+			i++
+			leading = false
+			continue
+		case strings.HasPrefix(line[i:], "//"):
+			b.WriteString(strings.TrimRight(line[i:], " \t\r"))
+			i = len(line)
+			continue
+		case strings.HasPrefix(line[i:], "/*"):
+			s.inComment = true
+			b.WriteString("/*")
+			i += 2
+			continue
+		case strings.IndexByte("{[(", c) >= 0:
+			l.delta++
+			leading = false
+		case strings.IndexByte("}])", c) >= 0:
+			l.delta--
+			if leading {
+				l.closers++
+			}
+		default:
+			leading = false
+		}
+		b.WriteByte(c)
+		last = c
+		i++
+	}
+
+	l.text = b.String()
+	l.opens = last != 0 && strings.IndexByte("{[(", last) >= 0
+
+	if m := attributeRE.FindStringSubmatchIndex(l.text); m != nil {
+		key := l.text[:m[3]]
+		value := strings.TrimLeft(l.text[strings.IndexByte(l.text[m[3]:], '=')+m[3]+1:], " ")
+		l.text = key + " ="
+		if value != "" {
+			l.text += " " + value
+		}
+		l.key = len(key)
+	} else if m := blockRE.FindStringSubmatch(l.text); m != nil {
+		header := m[1]
+		if m[2] != "" {
+			header += " " + m[2]
+		}
+		l.text = header + " {" + l.text[len(m[0]):]
+	}
+	return l
+}
+
+// alignAttributes pads the keys of consecutive single-line attributes at the
+// same indentation so that their = line up
+func alignAttributes(lines []formatLine) {
+	alignable := func(l formatLine) bool {
+		return !l.verbatim && l.key > 0 && l.delta == 0
+	}
+	for i := 0; i < len(lines); {
+		if !alignable(lines[i]) {
+			i++
+			continue
+		}
+		end, width := i, 0
+		for end < len(lines) && alignable(lines[end]) && lines[end].indent == lines[i].indent {
+			width = max(width, lines[end].key)
+			end++
+		}
+		for j := i; j < end; j++ {
+			l := &lines[j]
+			l.text = l.text[:l.key] + strings.Repeat(" ", width-l.key) + l.text[l.key:]
+		}
+		i = end
+	}
+}
+
+// prevCode returns the index of the last line before i that is not blank
+func prevCode(lines []formatLine, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if lines[j].verbatim || lines[j].text != "" {
+			return j
+		}
+	}
+	return i
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alloy
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{
+			name: "indents and aligns",
+			contents: `
+
+prometheus.scrape "pods"{
+  targets = discovery.kubernetes.pods.targets
+    forward_to=[prometheus.remote_write.default.receiver]   
+
+
+  clustering {
+
+      enabled   = true
+  }
+}
+
+
+prometheus.remote_write "default" {
+endpoint {
+url = "https://prom.example.com"
+}
+}
+
+`,
+			want: `prometheus.scrape "pods" {
+	targets    = discovery.kubernetes.pods.targets
+	forward_to = [prometheus.remote_write.default.receiver]
+
+	clustering {
+		enabled = true
+	}
+}
+
+prometheus.remote_write "default" {
+	endpoint {
+		url = "https://prom.example.com"
+	}
+}
+`,
+		},
+		{
+			name: "keeps strings and comments",
+			contents: `// Forward   logs
+loki.process "default" {
+  // keep   this
+  stage.template {
+    source   = "msg"
+    template = ` + "`" + `{{ .Value   }}
+  indented   line
+` + "`" + `
+  }
+  forward_to = [loki.write.default.receiver]   /* trailing   comment */
+}
+`,
+			want: `// Forward   logs
+loki.process "default" {
+	// keep   this
+	stage.template {
+		source   = "msg"
+		template = ` + "`" + `{{ .Value   }}
+  indented   line
+` + "`" + `
+	}
+	forward_to = [loki.write.default.receiver] /* trailing   comment */
+}
+`,
+		},
+		{
+			name: "indents object literals",
+			contents: `prometheus.remote_write "default" {
+  external_labels = {
+  cluster = "prod",
+  "k8s.namespace" = "default",
+  }
+}
+`,
+			want: `prometheus.remote_write "default" {
+	external_labels = {
+		cluster         = "prod",
+		"k8s.namespace" = "default",
+	}
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.contents)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() =\n%s\nwant\n%s", got, tt.want)
+			}
+			again, err := Format(got)
+			if err != nil {
+				t.Fatalf("Format() of formatted contents error = %v", err)
+			}
+			if again != got {
+				t.Errorf("Format() is not idempotent:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	if _, err := Format("logging {\n"); err == nil {
+		t.Error("Format() of unterminated block succeeded")
+	}
+}
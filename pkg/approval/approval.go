@@ -0,0 +1,191 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package approval decides which Pipelines need a second person to approve
+// their changes, and describes those changes.
+package approval
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+const (
+	// MaxDiffLength is the longest diff kept in status
+	MaxDiffLength = 4096
+
+	// maxDiffLines bounds the size of the specs compared line by line
+	maxDiffLines = 2000
+)
+
+// Gate selects the Pipelines whose spec changes require approval
+type Gate struct {
+	// Namespaces whose Pipelines are all gated
+	Namespaces []string
+
+	// Selector gates Pipelines whose labels it matches. A nil selector gates none.
+	Selector labels.Selector
+}
+
+// ParseGate builds a gate from a comma-separated list of namespaces and a label selector
+func ParseGate(namespaces, selector string) (*Gate, error) {
+	gate := &Gate{}
+	for ns := range strings.SplitSeq(namespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			gate.Namespaces = append(gate.Namespaces, ns)
+		}
+	}
+	if selector != "" {
+		s, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		gate.Selector = s
+	}
+	return gate, nil
+}
+
+// Gated reports whether spec changes of the pipeline require approval
+func (g *Gate) Gated(pipeline *fleetmanagementv1alpha1.Pipeline) bool {
+	if g == nil {
+		return false
+	}
+	if slices.Contains(g.Namespaces, pipeline.Namespace) {
+		return true
+	}
+	return g.Selector != nil && !g.Selector.Empty() && g.Selector.Matches(labels.Set(pipeline.Labels))
+}
+
+// Approved reports whether the pipeline carries an approval of the spec
+// identified by hash from someone other than the authors of the change
+func Approved(pipeline *fleetmanagementv1alpha1.Pipeline, hash string) bool {
+	annotations := pipeline.Annotations
+	approver := annotations[fleetmanagementv1alpha1.ApprovedByAnnotation]
+	return hash != "" && annotations[fleetmanagementv1alpha1.ApproveAnnotation] == hash &&
+		approver != "" && !slices.Contains(Authors(pipeline), approver)
+}
+
+// Authors returns the users who last changed the spec of the pipeline or of
+// the template, fragments and modules it was last rendered from, as recorded
+// in status. The rendered contents change with any of them, so none of them
+// may approve the change.
+func Authors(pipeline *fleetmanagementv1alpha1.Pipeline) []string {
+	authors := []string{pipeline.Annotations[fleetmanagementv1alpha1.ChangedByAnnotation]}
+	if pipeline.Status.Template != nil {
+		authors = append(authors, pipeline.Status.Template.ChangedBy)
+	}
+	for _, observed := range slices.Concat(pipeline.Status.Fragments, pipeline.Status.Modules) {
+		authors = append(authors, observed.ChangedBy)
+	}
+	authors = slices.DeleteFunc(authors, func(author string) bool { return author == "" })
+	slices.Sort(authors)
+	return slices.Compact(authors)
+}
+
+// Change is what an approval covers: the spec of a Pipeline, the contents
+// rendered from it and its template, fragments and modules before Secret
+// values are substituted, and the versions of the substituted Secrets. A
+// change to any of them needs a new approval.
+type Change struct {
+	Spec     *fleetmanagementv1alpha1.PipelineSpec      `json:"spec"`
+	Contents string                                     `json:"contents"`
+	Secrets  []fleetmanagementv1alpha1.ObservedResource `json:"secrets,omitempty"`
+}
+
+// Hash identifies the change in approval annotations
+func (c *Change) Hash() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// YAML renders the change for diffing
+func (c *Change) YAML() (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Diff returns the lines removed from before ("-") and added in after ("+"),
+// with unchanged lines (" ") in between, truncated to MaxDiffLength
+func Diff(before, after string) string {
+	a := splitLines(before)
+	b := splitLines(after)
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return fmt.Sprintf("spec too large to diff (%d and %d lines)", len(a), len(b))
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+
+	diff := out.String()
+	if len(diff) > MaxDiffLength {
+		diff = diff[:MaxDiffLength] + "\n... (truncated)\n"
+	}
+	return diff
+}
+
+// splitLines splits s into lines without the trailing empty line
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
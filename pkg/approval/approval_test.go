@@ -0,0 +1,167 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func TestGated(t *testing.T) {
+	gate, err := ParseGate("prod, payments", "tier=critical")
+	if err != nil {
+		t.Fatalf("ParseGate() error = %v", err)
+	}
+
+	tests := []struct {
+		namespace string
+		labels    map[string]string
+		want      bool
+	}{
+		{"prod", nil, true},
+		{"payments", nil, true},
+		{"dev", nil, false},
+		{"dev", map[string]string{"tier": "critical"}, true},
+		{"dev", map[string]string{"tier": "low"}, false},
+	}
+	for _, tt := range tests {
+		pipeline := &fleetmanagementv1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace, Labels: tt.labels},
+		}
+		if got := gate.Gated(pipeline); got != tt.want {
+			t.Errorf("Gated(%s, %v) = %v, want %v", tt.namespace, tt.labels, got, tt.want)
+		}
+	}
+
+	if (&Gate{}).Gated(&fleetmanagementv1alpha1.Pipeline{}) {
+		t.Error("empty gate gates pipelines")
+	}
+	if _, err := ParseGate("", "tier in (a"); err == nil {
+		t.Error("ParseGate() accepted an invalid selector")
+	}
+}
+
+func TestApproved(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{"no approval", map[string]string{fleetmanagementv1alpha1.ChangedByAnnotation: "alice"}, false},
+		{"other user", map[string]string{
+			fleetmanagementv1alpha1.ApproveAnnotation:    "abc",
+			fleetmanagementv1alpha1.ApprovedByAnnotation: "bob",
+			fleetmanagementv1alpha1.ChangedByAnnotation:  "alice",
+		}, true},
+		{"same user", map[string]string{
+			fleetmanagementv1alpha1.ApproveAnnotation:    "abc",
+			fleetmanagementv1alpha1.ApprovedByAnnotation: "alice",
+			fleetmanagementv1alpha1.ChangedByAnnotation:  "alice",
+		}, false},
+		{"stale hash", map[string]string{
+			fleetmanagementv1alpha1.ApproveAnnotation:    "old",
+			fleetmanagementv1alpha1.ApprovedByAnnotation: "bob",
+			fleetmanagementv1alpha1.ChangedByAnnotation:  "alice",
+		}, false},
+		{"approver not recorded", map[string]string{
+			fleetmanagementv1alpha1.ApproveAnnotation:   "abc",
+			fleetmanagementv1alpha1.ChangedByAnnotation: "alice",
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := Approved(pipeline, "abc"); got != tt.want {
+				t.Errorf("Approved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApprovedByTemplateAuthor(t *testing.T) {
+	pipeline := &fleetmanagementv1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			fleetmanagementv1alpha1.ApproveAnnotation:    "abc",
+			fleetmanagementv1alpha1.ApprovedByAnnotation: "carol",
+			fleetmanagementv1alpha1.ChangedByAnnotation:  "alice",
+		}},
+		Status: fleetmanagementv1alpha1.PipelineStatus{
+			Template:  &fleetmanagementv1alpha1.ObservedResource{Name: "remote-write", ChangedBy: "bob"},
+			Fragments: []fleetmanagementv1alpha1.ObservedResource{{Name: "labels", ChangedBy: "carol"}},
+			Modules:   []fleetmanagementv1alpha1.ObservedResource{{Name: "scrape", ChangedBy: "alice"}, {Name: "old"}},
+		},
+	}
+
+	if got, want := Authors(pipeline), []string{"alice", "bob", "carol"}; !slices.Equal(got, want) {
+		t.Errorf("Authors() = %v, want %v", got, want)
+	}
+	if Approved(pipeline, "abc") {
+		t.Error("Approved() accepted the approval of a fragment author")
+	}
+	pipeline.Annotations[fleetmanagementv1alpha1.ApprovedByAnnotation] = "dave"
+	if !Approved(pipeline, "abc") {
+		t.Error("Approved() rejected the approval of another user")
+	}
+}
+
+func TestChangeHash(t *testing.T) {
+	spec := &fleetmanagementv1alpha1.PipelineSpec{Contents: "a", Enabled: true}
+	change := &Change{Spec: spec, Contents: "a"}
+	first, err := change.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := (&Change{Spec: spec.DeepCopy(), Contents: "a"}).Hash()
+	if first != second || len(first) != 16 {
+		t.Errorf("Hash() = %q and %q, want the same 16 characters", first, second)
+	}
+
+	tests := map[string]*Change{
+		"spec":     {Spec: &fleetmanagementv1alpha1.PipelineSpec{Contents: "b", Enabled: true}, Contents: "a"},
+		"rendered": {Spec: spec, Contents: "b"},
+		"secrets": {Spec: spec, Contents: "a", Secrets: []fleetmanagementv1alpha1.ObservedResource{
+			{Name: "creds", ResourceVersion: "2"},
+		}},
+	}
+	for name, changed := range tests {
+		if hash, _ := changed.Hash(); hash == first {
+			t.Errorf("Hash() did not change with the %s", name)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := Diff("contents: a\nenabled: true\nmatchers:\n- env=prod\n", "contents: b\nenabled: true\nmatchers:\n- env=prod\n- team=a\n")
+	want := "- contents: a\n+ contents: b\n  enabled: true\n  matchers:\n  - env=prod\n+ - team=a\n"
+	if got != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Diff("", "a\n"); got != "+ a\n" {
+		t.Errorf("Diff() from nothing = %q", got)
+	}
+
+	long := strings.Repeat("line\n", 1000)
+	if got := Diff("", long); !strings.HasSuffix(got, "(truncated)\n") {
+		t.Error("Diff() did not truncate a long diff")
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credscan detects credentials written in plain text in pipeline contents.
+//
+// Findings name the rule and line that matched but never the value itself.
+// Values that are read from elsewhere, such as env(...), ${secret:...}
+// references or ${env:...} in OpenTelemetry Collector configs, are not reported.
+package credscan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// AllowAnnotation lists rule IDs, comma-separated, not reported for a Pipeline.
+// The value "*" disables scanning.
+const AllowAnnotation = "fleetmanagement.grafana.com/allow-credentials"
+
+// Mode decides what happens to a Pipeline with findings
+type Mode string
+
+const (
+	// ModeOff disables scanning
+	ModeOff Mode = "off"
+	// ModeWarn reports findings without blocking the Pipeline
+	ModeWarn Mode = "warn"
+	// ModeDeny rejects Pipelines with findings
+	ModeDeny Mode = "deny"
+)
+
+// ParseMode parses a --credential-scan flag value
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeOff, ModeWarn, ModeDeny:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown credential scan mode %q, must be one of off, warn, deny", s)
+}
+
+// Finding is a credential-looking value in contents
+type Finding struct {
+	// Line is the 1-based line number of the value
+	Line int
+	// Rule is the ID of the rule that matched
+	Rule string
+	// Description explains what was found
+	Description string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", f.Line, f.Description, f.Rule)
+}
+
+type rule struct {
+	id          string
+	description string
+	pattern     *regexp.Regexp
+	// value is the index of the submatch holding the value, or 0 for the whole match
+	value int
+	// configType restricts the rule to one config type when set
+	configType fleetmanagementv1alpha1.ConfigType
+}
+
+const sensitiveKeys = `password|passwd|secret|client_secret|token|bearer_token|api_key|apikey|access_key|secret_key|private_key`
+
+var rules = []rule{
+	{
+		id:          "alloy-literal-secret",
+		description: "string literal assigned to a credential attribute",
+		pattern:     regexp.MustCompile(`(?i)\b(?:` + sensitiveKeys + `)\s*=\s*"([^"]*)"`),
+		value:       1,
+		configType:  fleetmanagementv1alpha1.ConfigTypeAlloy,
+	},
+	{
+		id:          "otel-literal-secret",
+		description: "literal value assigned to a credential key",
+		pattern:     regexp.MustCompile(`(?i)^\s*-?\s*["']?(?:` + sensitiveKeys + `)["']?\s*:\s*["']?([^"'\s#]+)`),
+		value:       1,
+		configType:  fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector,
+	},
+	{
+		id:          "bearer-token",
+		description: "bearer token",
+		pattern:     regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]{20,}=*)`),
+		value:       1,
+	},
+	{
+		id:          "grafana-cloud-token",
+		description: "Grafana Cloud access policy or service account token",
+		pattern:     regexp.MustCompile(`\b(?:glc|glsa)_[A-Za-z0-9_+/=-]{20,}`),
+	},
+	{
+		id:          "aws-access-key",
+		description: "AWS access key ID",
+		pattern:     regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		id:          "github-token",
+		description: "GitHub token",
+		pattern:     regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr|github_pat)_[A-Za-z0-9_]{20,}`),
+	},
+	{
+		id:          "slack-token",
+		description: "Slack token",
+		pattern:     regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+	},
+	{
+		id:          "private-key",
+		description: "PEM private key",
+		pattern:     regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+	},
+}
+
+// Scan returns the findings in contents, in line order. Rules listed in allowed are skipped.
+func Scan(configType fleetmanagementv1alpha1.ConfigType, contents string, allowed map[string]bool) []Finding {
+	if configType == "" {
+		configType = fleetmanagementv1alpha1.ConfigTypeAlloy
+	}
+
+	var findings []Finding
+	for i, line := range strings.Split(contents, "\n") {
+		for _, r := range rules {
+			if allowed[r.id] || (r.configType != "" && r.configType != configType) {
+				continue
+			}
+			for _, m := range r.pattern.FindAllStringSubmatch(line, -1) {
+				if isReference(m[r.value]) {
+					continue
+				}
+				findings = append(findings, Finding{Line: i + 1, Rule: r.id, Description: r.description})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// isReference reports whether a value is read from elsewhere rather than written in place
+func isReference(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" ||
+		strings.HasPrefix(value, "${") ||
+		strings.HasPrefix(value, "$$") ||
+		strings.HasPrefix(value, "env(") ||
+		strings.HasPrefix(value, "{{")
+}
+
+// Allowed returns the rule IDs allowed by the annotations of a Pipeline, and
+// whether scanning is disabled altogether
+func Allowed(annotations map[string]string) (map[string]bool, bool) {
+	value, ok := annotations[AllowAnnotation]
+	if !ok {
+		return nil, false
+	}
+
+	allowed := map[string]bool{}
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "*" {
+			return nil, true
+		}
+		if id != "" {
+			allowed[id] = true
+		}
+	}
+	return allowed, false
+}
+
+// ScanPipeline scans contents rendered for a Pipeline, honoring its allow-list annotation
+func ScanPipeline(pipeline *fleetmanagementv1alpha1.Pipeline, contents string) []Finding {
+	allowed, disabled := Allowed(pipeline.Annotations)
+	if disabled {
+		return nil
+	}
+	return Scan(pipeline.Spec.ConfigType, contents, allowed)
+}
+
+// Summary joins findings into one message
+func Summary(findings []Finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, f := range findings {
+		parts = append(parts, f.String())
+	}
+	return "plaintext credentials found: " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchClient sends the requests of a Batcher
+type BatchClient interface {
+	UpsertPipeline(ctx context.Context, req *UpsertPipelineRequest) (*Pipeline, error)
+	SyncPipelines(ctx context.Context, req *SyncPipelinesRequest) (*SyncPipelinesResponse, error)
+	ListPipelines(ctx context.Context) ([]*Pipeline, error)
+}
+
+// Batcher coalesces the upserts of pipelines sharing a source into one
+// SyncPipelines request. Since SyncPipelines deletes the pipelines of the
+// source it is not given, a batch is only sent once every pipeline of the
+// source has joined it, and only when Fleet Management has no pipeline of the
+// source that is missing from the batch. Other batches are sent as one
+// UpsertPipeline request per pipeline instead. Upserts of a source wait while
+// one of its batches is synced, so that SyncPipelines cannot delete a pipeline
+// created after the source was listed.
+type Batcher struct {
+	client BatchClient
+	window time.Duration
+
+	mu      sync.Mutex
+	batches map[Source]*batch
+	syncing map[Source]*sync.RWMutex
+}
+
+// batch collects the pipelines of a source
+type batch struct {
+	ctx     context.Context
+	source  Source
+	members int
+	entries []*batchEntry
+	timer   *time.Timer
+}
+
+// batchEntry is a pipeline waiting in a batch for its result
+type batchEntry struct {
+	pipeline *Pipeline
+	done     chan batchResult
+}
+
+type batchResult struct {
+	pipeline *Pipeline
+	err      error
+}
+
+// NewBatcher creates a Batcher that waits up to window for the pipelines of a source
+func NewBatcher(client BatchClient, window time.Duration) *Batcher {
+	return &Batcher{
+		client:  client,
+		window:  window,
+		batches: make(map[Source]*batch),
+		syncing: make(map[Source]*sync.RWMutex),
+	}
+}
+
+// Upsert creates or updates a pipeline of a source that has members pipelines
+// in total and returns the pipeline stored in Fleet Management. Pipelines of
+// sources with a single member, or an unknown number of members (0), are
+// upserted right away.
+func (b *Batcher) Upsert(ctx context.Context, members int, pipeline *Pipeline) (*Pipeline, error) {
+	if pipeline.Source == nil {
+		return b.client.UpsertPipeline(ctx, &UpsertPipelineRequest{Pipeline: pipeline})
+	}
+	if members <= 1 {
+		lock := b.sourceLock(*pipeline.Source)
+		lock.RLock()
+		defer lock.RUnlock()
+		return b.client.UpsertPipeline(ctx, &UpsertPipelineRequest{Pipeline: pipeline})
+	}
+
+	entry := &batchEntry{pipeline: pipeline, done: make(chan batchResult, 1)}
+	b.add(ctx, members, entry)
+
+	select {
+	case result := <-entry.done:
+		return result.pipeline, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Leave tells the batch of a source that one of its pipelines is not going to
+// join, since it needs no sync. Its remote pipeline stays in Fleet Management,
+// so the batch cannot be synced as a whole and is sent one by one right away
+// instead of waiting for the window to pass.
+func (b *Batcher) Leave(source Source) {
+	b.mu.Lock()
+	bt, ok := b.batches[source]
+	if ok {
+		bt.timer.Stop()
+		delete(b.batches, source)
+	}
+	b.mu.Unlock()
+
+	if ok {
+		go b.upsertEach(bt)
+	}
+}
+
+// sourceLock returns the lock held while a batch of the source is synced
+func (b *Batcher) sourceLock(source Source) *sync.RWMutex {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lock, ok := b.syncing[source]
+	if !ok {
+		lock = &sync.RWMutex{}
+		b.syncing[source] = lock
+	}
+	return lock
+}
+
+// add puts the entry into the batch of its source, sending the batch once it
+// is complete. A pipeline that is already in the batch is replaced.
+func (b *Batcher) add(ctx context.Context, members int, entry *batchEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	source := *entry.pipeline.Source
+	bt, ok := b.batches[source]
+	if !ok {
+		// The requests of the batch are sent with the priority and lane of the
+		// first pipeline, but are not cancelled with it
+		bt = &batch{ctx: context.WithoutCancel(ctx), source: source}
+		bt.timer = time.AfterFunc(b.window, func() { b.expire(bt) })
+		b.batches[source] = bt
+	}
+	bt.members = members
+
+	for i, e := range bt.entries {
+		if e.pipeline.Name == entry.pipeline.Name {
+			e.done <- batchResult{err: fmt.Errorf("pipeline %q was upserted again", entry.pipeline.Name)}
+			bt.entries = append(bt.entries[:i], bt.entries[i+1:]...)
+			break
+		}
+	}
+	bt.entries = append(bt.entries, entry)
+
+	if len(bt.entries) >= bt.members {
+		bt.timer.Stop()
+		delete(b.batches, source)
+		go b.sync(bt)
+	}
+}
+
+// expire sends the pipelines of a batch that did not complete within the window one by one
+func (b *Batcher) expire(bt *batch) {
+	b.mu.Lock()
+	if b.batches[bt.source] != bt {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.batches, bt.source)
+	b.mu.Unlock()
+
+	b.upsertEach(bt)
+}
+
+// upsertEach sends the pipelines of a batch one by one
+func (b *Batcher) upsertEach(bt *batch) {
+	lock := b.sourceLock(bt.source)
+	lock.RLock()
+	defer lock.RUnlock()
+	b.upsertEntries(bt)
+}
+
+// upsertEntries sends the pipelines of a batch one by one without waiting for
+// a sync of the source
+func (b *Batcher) upsertEntries(bt *batch) {
+	for _, e := range bt.entries {
+		pipeline, err := b.client.UpsertPipeline(bt.ctx, &UpsertPipelineRequest{Pipeline: e.pipeline})
+		e.done <- batchResult{pipeline: pipeline, err: err}
+	}
+}
+
+// sync sends a complete batch as one SyncPipelines request and hands each
+// pipeline its result. Batches that would delete a pipeline of the source
+// are upserted one by one instead.
+func (b *Batcher) sync(bt *batch) {
+	source := bt.source
+	lock := b.sourceLock(source)
+	lock.Lock()
+	defer lock.Unlock()
+
+	pipelines := make([]*Pipeline, len(bt.entries))
+	names := make(map[string]bool, len(bt.entries))
+	for i, e := range bt.entries {
+		pipelines[i] = e.pipeline
+		names[e.pipeline.Name] = true
+	}
+
+	remote, err := b.client.ListPipelines(bt.ctx)
+	if err != nil {
+		for _, e := range bt.entries {
+			e.done <- batchResult{err: err}
+		}
+		return
+	}
+	for _, p := range remote {
+		if p.Source != nil && *p.Source == source && !names[p.Name] {
+			b.upsertEntries(bt)
+			return
+		}
+	}
+
+	resp, err := b.client.SyncPipelines(bt.ctx, &SyncPipelinesRequest{Source: &source, Pipelines: pipelines})
+	if err != nil {
+		for _, e := range bt.entries {
+			e.done <- batchResult{err: err}
+		}
+		return
+	}
+
+	byName := make(map[string]*Pipeline, len(resp.Pipelines))
+	for _, p := range resp.Pipelines {
+		byName[p.Name] = p
+	}
+	for _, e := range bt.entries {
+		p, ok := byName[e.pipeline.Name]
+		if !ok {
+			e.done <- batchResult{err: fmt.Errorf("SyncPipelines did not return pipeline %q", e.pipeline.Name)}
+			continue
+		}
+		e.done <- batchResult{pipeline: p}
+	}
+}
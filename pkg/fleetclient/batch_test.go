@@ -0,0 +1,238 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetclient
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBatchClient records the requests it receives and assigns IDs by name.
+// SyncPipelines deletes the remote pipelines of the source it is not given.
+type fakeBatchClient struct {
+	mu      sync.Mutex
+	upserts []string
+	syncs   [][]string
+	remote  []*Pipeline
+}
+
+func (f *fakeBatchClient) ListPipelines(_ context.Context) ([]*Pipeline, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.remote, nil
+}
+
+func (f *fakeBatchClient) UpsertPipeline(_ context.Context, req *UpsertPipelineRequest) (*Pipeline, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.upserts = append(f.upserts, req.Pipeline.Name)
+	return &Pipeline{Name: req.Pipeline.Name, ID: "id-" + req.Pipeline.Name}, nil
+}
+
+func (f *fakeBatchClient) SyncPipelines(_ context.Context, req *SyncPipelinesRequest) (*SyncPipelinesResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	resp := &SyncPipelinesResponse{}
+	for _, p := range req.Pipelines {
+		names = append(names, p.Name)
+		resp.Pipelines = append(resp.Pipelines, &Pipeline{Name: p.Name, ID: "id-" + p.Name})
+	}
+	f.syncs = append(f.syncs, names)
+	f.remote = slices.DeleteFunc(f.remote, func(p *Pipeline) bool {
+		return p.Source != nil && *p.Source == *req.Source
+	})
+	return resp, nil
+}
+
+// upsertAll upserts count pipelines of the source concurrently and returns their IDs
+func upsertAll(t *testing.T, b *Batcher, source Source, members, count int) []string {
+	t.Helper()
+	ids := make([]string, count)
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for i := range count {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := b.Upsert(context.Background(), members, &Pipeline{Name: fmt.Sprintf("p%d", i), Source: &source})
+			errs[i] = err
+			if p != nil {
+				ids[i] = p.ID
+			}
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("upsert p%d: %v", i, err)
+		}
+		if ids[i] != fmt.Sprintf("id-p%d", i) {
+			t.Errorf("p%d got ID %q", i, ids[i])
+		}
+	}
+	return ids
+}
+
+func TestBatcherSyncsCompleteSource(t *testing.T) {
+	client := &fakeBatchClient{}
+	b := NewBatcher(client, time.Minute)
+
+	upsertAll(t, b, Source{Type: "GIT", Namespace: "repo"}, 3, 3)
+
+	if len(client.syncs) != 1 || len(client.syncs[0]) != 3 {
+		t.Errorf("syncs = %v, want one with 3 pipelines", client.syncs)
+	}
+	if len(client.upserts) != 0 {
+		t.Errorf("upserts = %v, want none", client.upserts)
+	}
+}
+
+func TestBatcherUpsertsIncompleteSource(t *testing.T) {
+	client := &fakeBatchClient{}
+	b := NewBatcher(client, 10*time.Millisecond)
+
+	upsertAll(t, b, Source{Type: "GIT", Namespace: "repo"}, 3, 2)
+
+	if len(client.syncs) != 0 {
+		t.Errorf("syncs = %v, want none", client.syncs)
+	}
+	if len(client.upserts) != 2 {
+		t.Errorf("upserts = %v, want 2", client.upserts)
+	}
+}
+
+func TestBatcherUpsertsSingleMember(t *testing.T) {
+	client := &fakeBatchClient{}
+	b := NewBatcher(client, time.Minute)
+
+	upsertAll(t, b, Source{Type: "KUBERNETES", Namespace: "default/p0"}, 1, 1)
+
+	if len(client.syncs) != 0 || len(client.upserts) != 1 {
+		t.Errorf("syncs = %v, upserts = %v, want a single upsert", client.syncs, client.upserts)
+	}
+}
+
+func TestBatcherKeepsForeignPipelinesOfSource(t *testing.T) {
+	source := Source{Type: "KUBERNETES", Namespace: "prod/default"}
+	foreign := &Pipeline{Name: "terraform", ID: "id-terraform", Source: &source}
+	client := &fakeBatchClient{remote: []*Pipeline{foreign}}
+	b := NewBatcher(client, time.Minute)
+
+	upsertAll(t, b, source, 2, 2)
+
+	if len(client.syncs) != 0 {
+		t.Errorf("syncs = %v, want none", client.syncs)
+	}
+	if len(client.upserts) != 2 {
+		t.Errorf("upserts = %v, want 2", client.upserts)
+	}
+	if !slices.Contains(client.remote, foreign) {
+		t.Errorf("pipeline %q of the source was deleted", foreign.Name)
+	}
+}
+
+func TestBatcherLeaveSendsBatchRightAway(t *testing.T) {
+	source := Source{Type: "KUBERNETES", Namespace: "prod/default"}
+	client := &fakeBatchClient{}
+	b := NewBatcher(client, time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.Upsert(context.Background(), 2, &Pipeline{Name: "p0", Source: &source})
+		done <- err
+	}()
+	for {
+		b.mu.Lock()
+		_, pending := b.batches[source]
+		b.mu.Unlock()
+		if pending {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.Leave(source)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("upsert p0: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch was not sent after a member left")
+	}
+	if len(client.syncs) != 0 || !slices.Equal(client.upserts, []string{"p0"}) {
+		t.Errorf("syncs = %v, upserts = %v, want a single upsert", client.syncs, client.upserts)
+	}
+}
+
+// blockingBatchClient holds ListPipelines until released and records the
+// order of the requests that change pipelines
+type blockingBatchClient struct {
+	fakeBatchClient
+	listing chan struct{}
+	release chan struct{}
+	order   []string
+}
+
+func (c *blockingBatchClient) ListPipelines(ctx context.Context) ([]*Pipeline, error) {
+	close(c.listing)
+	<-c.release
+	return c.fakeBatchClient.ListPipelines(ctx)
+}
+
+func (c *blockingBatchClient) UpsertPipeline(ctx context.Context, req *UpsertPipelineRequest) (*Pipeline, error) {
+	c.mu.Lock()
+	c.order = append(c.order, "upsert "+req.Pipeline.Name)
+	c.mu.Unlock()
+	return c.fakeBatchClient.UpsertPipeline(ctx, req)
+}
+
+func (c *blockingBatchClient) SyncPipelines(ctx context.Context, req *SyncPipelinesRequest) (*SyncPipelinesResponse, error) {
+	c.mu.Lock()
+	c.order = append(c.order, "sync")
+	c.mu.Unlock()
+	return c.fakeBatchClient.SyncPipelines(ctx, req)
+}
+
+func TestBatcherUpsertWaitsForSyncOfSource(t *testing.T) {
+	source := Source{Type: "KUBERNETES", Namespace: "prod/default"}
+	client := &blockingBatchClient{listing: make(chan struct{}), release: make(chan struct{})}
+	b := NewBatcher(client, time.Minute)
+
+	var wg sync.WaitGroup
+	wg.Go(func() { upsertAll(t, b, source, 2, 2) })
+	<-client.listing
+
+	// A pipeline created while the source is listed must not be deleted by SyncPipelines
+	wg.Go(func() {
+		if _, err := b.Upsert(context.Background(), 1, &Pipeline{Name: "late", Source: &source}); err != nil {
+			t.Errorf("upsert late: %v", err)
+		}
+	})
+	time.Sleep(20 * time.Millisecond)
+	close(client.release)
+	wg.Wait()
+
+	if !slices.Equal(client.order, []string{"sync", "upsert late"}) {
+		t.Errorf("requests = %v, want the sync before the upsert", client.order)
+	}
+}
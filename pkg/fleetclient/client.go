@@ -23,26 +23,50 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
-// Client is a client for the Fleet Management Pipeline API
+const (
+	// pipelineServicePath is the path of the PipelineService the base URL points at
+	pipelineServicePath = "pipeline.v1.PipelineService/"
+
+	// collectorServicePath is the path of the CollectorService
+	collectorServicePath = "collector.v1.CollectorService/"
+
+	// RateLimit is the Fleet Management API rate limit in requests per second
+	RateLimit rate.Limit = 3
+)
+
+// Client is a client for the Fleet Management Pipeline and Collector APIs
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	limiter    *rate.Limiter
-	username   string
-	password   string
+	baseURL          string
+	collectorBaseURL string
+	httpClient       *http.Client
+	limiter          Limiter
+	username         string
+	password         string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithLimiter replaces the default rate limiter of 3 requests per second
+func WithLimiter(limiter Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
 }
 
 // NewClient creates a new Fleet Management API client
-func NewClient(baseURL, username, password string) *Client {
-	return &Client{
-		baseURL:  baseURL,
-		username: username,
-		password: password,
+func NewClient(baseURL, username, password string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:          baseURL,
+		collectorBaseURL: collectorServiceURL(baseURL),
+		username:         username,
+		password:         password,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 			Transport: &http.Transport{
@@ -52,69 +76,109 @@ func NewClient(baseURL, username, password string) *Client {
 			},
 		},
 		// Fleet Management API rate limit: 3 requests per second
-		limiter: rate.NewLimiter(rate.Limit(3), 1),
+		limiter: rate.NewLimiter(RateLimit, 1),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // UpsertPipeline creates or updates a pipeline
 func (c *Client) UpsertPipeline(ctx context.Context, req *UpsertPipelineRequest) (*Pipeline, error) {
-	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
+	var pipeline Pipeline
+	if err := c.do(ctx, "UpsertPipeline", req, &pipeline); err != nil {
+		return nil, err
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
+	return &pipeline, nil
+}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"UpsertPipeline", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// SyncPipelines makes the pipelines of a source match the given ones in a
+// single request: missing pipelines are created, existing ones updated and
+// the other pipelines of the source deleted
+func (c *Client) SyncPipelines(ctx context.Context, req *SyncPipelinesRequest) (*SyncPipelinesResponse, error) {
+	var resp SyncPipelinesResponse
+	if err := c.do(ctx, "SyncPipelines", req, &resp); err != nil {
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.SetBasicAuth(c.username, c.password)
+	return &resp, nil
+}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// ListPipelines lists all pipelines
+func (c *Client) ListPipelines(ctx context.Context) ([]*Pipeline, error) {
+	var resp ListPipelinesResponse
+	if err := c.do(ctx, "ListPipelines", struct{}{}, &resp); err != nil {
+		return nil, err
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &FleetAPIError{
-			StatusCode: resp.StatusCode,
-			Operation:  "UpsertPipeline",
-			Message:    string(bodyBytes),
-		}
-	}
+	return resp.Pipelines, nil
+}
 
+// GetPipeline retrieves a pipeline by ID
+func (c *Client) GetPipeline(ctx context.Context, id string) (*Pipeline, error) {
 	var pipeline Pipeline
-	if err := json.NewDecoder(resp.Body).Decode(&pipeline); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.do(ctx, "GetPipeline", map[string]string{"id": id}, &pipeline); err != nil {
+		return nil, err
 	}
 
 	return &pipeline, nil
 }
 
+// GetPipelineID looks up the ID of a pipeline by name
+func (c *Client) GetPipelineID(ctx context.Context, name string) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, "GetPipelineID", map[string]string{"name": name}, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// ListCollectors lists collectors, optionally filtered by matchers
+func (c *Client) ListCollectors(ctx context.Context, req *ListCollectorsRequest) ([]*Collector, error) {
+	var resp ListCollectorsResponse
+	if err := c.doURL(ctx, c.collectorBaseURL, "ListCollectors", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Collectors, nil
+}
+
 // DeletePipeline deletes a pipeline by ID
 func (c *Client) DeletePipeline(ctx context.Context, id string) error {
+	err := c.do(ctx, "DeletePipeline", map[string]string{"id": id}, nil)
+
+	// 404 is treated as success (pipeline already deleted)
+	if apiErr, ok := err.(*FleetAPIError); ok && apiErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// do sends a rate-limited request to the given PipelineService operation and
+// decodes the response into out. A nil out discards the response body.
+func (c *Client) do(ctx context.Context, operation string, in, out any) error {
+	return c.doURL(ctx, c.baseURL, operation, in, out)
+}
+
+// doURL sends a rate-limited request to the operation of the service at baseURL
+func (c *Client) doURL(ctx context.Context, baseURL, operation string, in, out any) error {
 	// Wait for rate limiter
 	if err := c.limiter.Wait(ctx); err != nil {
 		return fmt.Errorf("rate limiter error: %w", err)
 	}
 
-	req := map[string]string{"id": id}
-	body, err := json.Marshal(req)
+	body, err := json.Marshal(in)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"DeletePipeline", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+operation, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -130,19 +194,30 @@ func (c *Client) DeletePipeline(ctx context.Context, id string) error {
 		_ = resp.Body.Close()
 	}()
 
-	// 404 is treated as success (pipeline already deleted)
-	if resp.StatusCode == http.StatusNotFound {
-		return nil
-	}
-
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return &FleetAPIError{
 			StatusCode: resp.StatusCode,
-			Operation:  "DeletePipeline",
+			Operation:  operation,
 			Message:    string(bodyBytes),
 		}
 	}
 
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
 	return nil
 }
+
+// collectorServiceURL derives the CollectorService URL from the PipelineService base URL
+func collectorServiceURL(baseURL string) string {
+	if strings.HasSuffix(baseURL, pipelineServicePath) {
+		return strings.TrimSuffix(baseURL, pipelineServicePath) + collectorServicePath
+	}
+	return baseURL
+}
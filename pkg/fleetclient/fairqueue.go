@@ -0,0 +1,262 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetclient
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// Limiter delays requests to stay within the Fleet Management API rate limit
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// Priority orders requests waiting for the rate limit
+type Priority int
+
+const (
+	// PriorityLow is for periodic resyncs, such as collector refreshes
+	PriorityLow Priority = iota
+	// PriorityNormal is the default
+	PriorityNormal
+	// PriorityHigh is for deletes and user-initiated changes
+	PriorityHigh
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+// String returns the priority name used in metrics
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return strconv.Itoa(int(p))
+}
+
+type laneKey struct{}
+type priorityKey struct{}
+
+// WithLane returns a context whose requests wait in the given lane, e.g.
+// the namespace of the Pipeline being reconciled
+func WithLane(ctx context.Context, lane string) context.Context {
+	return context.WithValue(ctx, laneKey{}, lane)
+}
+
+// WithPriority returns a context whose requests wait with the given priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+func laneFrom(ctx context.Context) string {
+	lane, _ := ctx.Value(laneKey{}).(string)
+	return lane
+}
+
+func priorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= PriorityLow && p <= PriorityHigh {
+		return p
+	}
+	return PriorityNormal
+}
+
+var (
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fleet_management_api_queue_depth",
+		Help: "Requests waiting for the Fleet Management API rate limit by priority and lane",
+	}, []string{"priority", "lane"})
+
+	queueWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fleet_management_api_queue_wait_seconds",
+		Help:    "Time requests waited for the Fleet Management API rate limit by priority",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"priority"})
+)
+
+// Metrics returns the collectors of the fair-queueing limiter metrics
+func Metrics() []prometheus.Collector {
+	return []prometheus.Collector{queueDepth, queueWait}
+}
+
+// waiter is a request waiting for its turn
+type waiter struct {
+	lane     string
+	priority Priority
+	enqueued time.Time
+	ready    chan struct{}
+}
+
+// lanes is the queue of one priority: a FIFO per lane, served round-robin
+type lanes struct {
+	waiting map[string][]*waiter
+	order   []string
+}
+
+// FairLimiter is a Limiter sharing the rate limit fairly between lanes. Each
+// time a request may be sent, it goes to the highest priority with waiting
+// requests, and within it to the next lane in round-robin order, so a burst
+// in one lane delays other lanes by at most one request per burst request
+// ahead of them.
+type FairLimiter struct {
+	limiter *rate.Limiter
+	now     func() time.Time
+
+	mu      sync.Mutex
+	queues  [numPriorities]lanes
+	pending int
+	running bool
+}
+
+// NewFairLimiter creates a FairLimiter allowing limit requests per second with
+// the given burst
+func NewFairLimiter(limit rate.Limit, burst int) *FairLimiter {
+	f := &FairLimiter{limiter: rate.NewLimiter(limit, burst), now: time.Now}
+	for i := range f.queues {
+		f.queues[i].waiting = map[string][]*waiter{}
+	}
+	return f
+}
+
+// Wait blocks until the request may be sent, in the lane and with the
+// priority of the context
+func (f *FairLimiter) Wait(ctx context.Context) error {
+	w := &waiter{lane: laneFrom(ctx), priority: priorityFrom(ctx), enqueued: f.now(), ready: make(chan struct{})}
+
+	f.mu.Lock()
+	f.enqueue(w)
+	if !f.running {
+		f.running = true
+		go f.dispatch()
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		// A waiter served meanwhile is no longer queued; its turn is lost
+		f.mu.Lock()
+		f.remove(w)
+		f.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// dispatch serves waiters as the rate limit allows until none are left
+func (f *FairLimiter) dispatch() {
+	for {
+		f.mu.Lock()
+		if f.pending == 0 {
+			f.running = false
+			f.mu.Unlock()
+			return
+		}
+		f.mu.Unlock()
+
+		// The context is never canceled, so Wait only fails if burst is zero
+		if err := f.limiter.Wait(context.Background()); err != nil {
+			time.Sleep(time.Second)
+		}
+
+		f.mu.Lock()
+		if w := f.next(); w != nil {
+			queueWait.WithLabelValues(w.priority.String()).Observe(f.now().Sub(w.enqueued).Seconds())
+			close(w.ready)
+		}
+		f.mu.Unlock()
+	}
+}
+
+// enqueue adds a waiter at the end of its lane. f.mu must be held.
+func (f *FairLimiter) enqueue(w *waiter) {
+	q := &f.queues[w.priority]
+	if len(q.waiting[w.lane]) == 0 {
+		q.order = append(q.order, w.lane)
+	}
+	q.waiting[w.lane] = append(q.waiting[w.lane], w)
+	f.pending++
+	queueDepth.WithLabelValues(w.priority.String(), w.lane).Inc()
+}
+
+// next dequeues the waiter to serve, or returns nil. f.mu must be held.
+func (f *FairLimiter) next() *waiter {
+	for p := numPriorities - 1; p >= 0; p-- {
+		q := &f.queues[p]
+		if len(q.order) == 0 {
+			continue
+		}
+		lane := q.order[0]
+		q.order = q.order[1:]
+		w := q.waiting[lane][0]
+		if rest := q.waiting[lane][1:]; len(rest) > 0 {
+			q.waiting[lane] = rest
+			q.order = append(q.order, lane)
+		} else {
+			delete(q.waiting, lane)
+		}
+		f.dequeued(w)
+		return w
+	}
+	return nil
+}
+
+// remove drops a waiter that is still queued and reports whether it was.
+// f.mu must be held.
+func (f *FairLimiter) remove(w *waiter) bool {
+	q := &f.queues[w.priority]
+	queued := q.waiting[w.lane]
+	for i, other := range queued {
+		if other != w {
+			continue
+		}
+		queued = append(queued[:i:i], queued[i+1:]...)
+		if len(queued) > 0 {
+			q.waiting[w.lane] = queued
+		} else {
+			delete(q.waiting, w.lane)
+			for j, lane := range q.order {
+				if lane == w.lane {
+					q.order = append(q.order[:j:j], q.order[j+1:]...)
+					break
+				}
+			}
+		}
+		f.dequeued(w)
+		return true
+	}
+	return false
+}
+
+// dequeued updates the queue depth after a waiter left. f.mu must be held.
+func (f *FairLimiter) dequeued(w *waiter) {
+	f.pending--
+	if len(f.queues[w.priority].waiting[w.lane]) == 0 {
+		queueDepth.DeleteLabelValues(w.priority.String(), w.lane)
+	} else {
+		queueDepth.WithLabelValues(w.priority.String(), w.lane).Dec()
+	}
+}
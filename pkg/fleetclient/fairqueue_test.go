@@ -0,0 +1,99 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestFairLimiterOrder(t *testing.T) {
+	f := NewFairLimiter(rate.Limit(1), 1)
+
+	add := func(lane string, priority Priority) {
+		f.enqueue(&waiter{lane: lane, priority: priority, ready: make(chan struct{})})
+	}
+	// A burst in team-a, then single requests from team-b and team-c, a
+	// periodic resync and an urgent delete
+	for range 3 {
+		add("team-a", PriorityNormal)
+	}
+	add("team-b", PriorityNormal)
+	add("team-c", PriorityLow)
+	add("team-c", PriorityNormal)
+	add("team-d", PriorityHigh)
+
+	var got []string
+	for w := f.next(); w != nil; w = f.next() {
+		got = append(got, w.lane+"/"+w.priority.String())
+	}
+	want := []string{
+		"team-d/high",
+		"team-a/normal", "team-b/normal", "team-c/normal",
+		"team-a/normal", "team-a/normal",
+		"team-c/low",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if f.pending != 0 {
+		t.Errorf("pending = %d after draining", f.pending)
+	}
+}
+
+func TestFairLimiterWait(t *testing.T) {
+	f := NewFairLimiter(rate.Limit(1000), 1)
+	ctx := WithPriority(WithLane(context.Background(), "team-a"), PriorityHigh)
+	for range 3 {
+		if err := f.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	// Requests canceled while waiting leave the queue
+	f = NewFairLimiter(rate.Every(time.Hour), 1)
+	if err := f.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	canceled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := f.Wait(canceled); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want deadline exceeded", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pending != 0 || len(f.queues[PriorityHigh].order) != 0 {
+		t.Errorf("canceled request still queued: pending = %d", f.pending)
+	}
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	if p := priorityFrom(context.Background()); p != PriorityNormal {
+		t.Errorf("default priority = %s, want normal", p)
+	}
+	if p := priorityFrom(WithPriority(context.Background(), Priority(7))); p != PriorityNormal {
+		t.Errorf("invalid priority = %s, want normal", p)
+	}
+	if lane := laneFrom(WithLane(context.Background(), "team-a")); lane != "team-a" {
+		t.Errorf("lane = %q, want team-a", lane)
+	}
+}
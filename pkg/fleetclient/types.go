@@ -43,6 +43,59 @@ type UpsertPipelineRequest struct {
 	ValidateOnly bool      `json:"validateOnly,omitempty"`
 }
 
+// SyncPipelinesRequest is the request to replace the pipelines of a source
+type SyncPipelinesRequest struct {
+	Source    *Source     `json:"source"`
+	Pipelines []*Pipeline `json:"pipelines"`
+}
+
+// SyncPipelinesResponse is the response of SyncPipelines
+type SyncPipelinesResponse struct {
+	Pipelines []*Pipeline `json:"pipelines"`
+}
+
+// ListPipelinesResponse is the response of ListPipelines
+type ListPipelinesResponse struct {
+	Pipelines []*Pipeline `json:"pipelines"`
+}
+
+// Collector represents a collector registered in Fleet Management
+type Collector struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name,omitempty"`
+	CollectorType    string            `json:"collectorType,omitempty"`
+	RemoteAttributes map[string]string `json:"remoteAttributes,omitempty"`
+	LocalAttributes  map[string]string `json:"localAttributes,omitempty"`
+	Enabled          *bool             `json:"enabled,omitempty"`
+	CreatedAt        *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt        *time.Time        `json:"updatedAt,omitempty"`
+	MarkedInactiveAt *time.Time        `json:"markedInactiveAt,omitempty"`
+}
+
+// Attributes returns the attributes matchers are evaluated against: local
+// attributes overridden by remote attributes, plus collector.ID
+func (c *Collector) Attributes() map[string]string {
+	attrs := make(map[string]string, len(c.LocalAttributes)+len(c.RemoteAttributes)+1)
+	for k, v := range c.LocalAttributes {
+		attrs[k] = v
+	}
+	for k, v := range c.RemoteAttributes {
+		attrs[k] = v
+	}
+	attrs["collector.ID"] = c.ID
+	return attrs
+}
+
+// ListCollectorsRequest is the request to list collectors
+type ListCollectorsRequest struct {
+	Matchers []string `json:"matchers,omitempty"`
+}
+
+// ListCollectorsResponse is the response of ListCollectors
+type ListCollectorsResponse struct {
+	Collectors []*Collector `json:"collectors"`
+}
+
 // FleetAPIError represents an error from the Fleet Management API
 type FleetAPIError struct {
 	StatusCode int
@@ -0,0 +1,138 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fragments merges PipelineFragment contents into one pipeline.
+//
+// Fragments are merged by ascending spec.order, then name, followed by the
+// pipeline's own contents. Alloy contents are concatenated. OpenTelemetry
+// Collector contents are merged as YAML: mappings are merged recursively and
+// any other value may only be set by one source.
+package fragments
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// Sort orders fragments the way they are merged
+func Sort(fragments []fleetmanagementv1alpha1.PipelineFragment) {
+	slices.SortFunc(fragments, func(a, b fleetmanagementv1alpha1.PipelineFragment) int {
+		return cmp.Or(cmp.Compare(a.Spec.Order, b.Spec.Order), strings.Compare(a.Name, b.Name))
+	})
+}
+
+// Merge combines the sorted fragments with the pipeline's own contents, which may be empty
+func Merge(configType fleetmanagementv1alpha1.ConfigType, sorted []fleetmanagementv1alpha1.PipelineFragment, contents string) (string, error) {
+	if len(sorted) == 0 {
+		return contents, nil
+	}
+	if configType == fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector {
+		return mergeYAML(sorted, contents)
+	}
+
+	var b strings.Builder
+	for i, fragment := range sorted {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "// Fragment: %s\n", fragment.Name)
+		b.WriteString(strings.TrimRight(fragment.Spec.Contents, "\n"))
+		b.WriteString("\n")
+	}
+	if contents != "" {
+		b.WriteString("\n")
+		b.WriteString(contents)
+	}
+	return b.String(), nil
+}
+
+func mergeYAML(sorted []fleetmanagementv1alpha1.PipelineFragment, contents string) (string, error) {
+	merged := map[string]any{}
+	setBy := map[string]string{}
+
+	add := func(owner, doc string) error {
+		var m map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &m); err != nil {
+			return fmt.Errorf("%s is not valid YAML: %w", owner, err)
+		}
+		return mergeMap(merged, m, "", owner, setBy)
+	}
+
+	for _, fragment := range sorted {
+		if err := add(fmt.Sprintf("PipelineFragment %q", fragment.Name), fragment.Spec.Contents); err != nil {
+			return "", err
+		}
+	}
+	if strings.TrimSpace(contents) != "" {
+		if err := add("the pipeline contents", contents); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal merged contents: %w", err)
+	}
+	return string(out), nil
+}
+
+// mergeMap merges src into dst. setBy records which source first set each value.
+func mergeMap(dst, src map[string]any, path, owner string, setBy map[string]string) error {
+	for key, value := range src {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			recordOwner(value, keyPath, owner, setBy)
+			continue
+		}
+
+		existingMap, existingIsMap := existing.(map[string]any)
+		valueMap, valueIsMap := value.(map[string]any)
+		switch {
+		case existingIsMap && valueIsMap:
+			if err := mergeMap(existingMap, valueMap, keyPath, owner, setBy); err != nil {
+				return err
+			}
+		case reflect.DeepEqual(existing, value):
+		default:
+			return fmt.Errorf("%s is set by both %s and %s", keyPath, setBy[keyPath], owner)
+		}
+	}
+	return nil
+}
+
+func recordOwner(value any, path, owner string, setBy map[string]string) {
+	setBy[path] = owner
+	m, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	for key, v := range m {
+		recordOwner(v, path+"."+key, owner, setBy)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fragments
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func fragment(name string, order int32, contents string) fleetmanagementv1alpha1.PipelineFragment {
+	return fleetmanagementv1alpha1.PipelineFragment{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       fleetmanagementv1alpha1.PipelineFragmentSpec{Contents: contents, Order: order},
+	}
+}
+
+func TestSort(t *testing.T) {
+	fragments := []fleetmanagementv1alpha1.PipelineFragment{
+		fragment("scrape-b", 10, ""),
+		fragment("exporters", 0, ""),
+		fragment("scrape-a", 10, ""),
+	}
+	Sort(fragments)
+
+	var got []string
+	for _, f := range fragments {
+		got = append(got, f.Name)
+	}
+	if want := "exporters,scrape-a,scrape-b"; strings.Join(got, ",") != want {
+		t.Errorf("Sort() = %v, want %s", got, want)
+	}
+}
+
+func TestMergeAlloy(t *testing.T) {
+	got, err := Merge(fleetmanagementv1alpha1.ConfigTypeAlloy, []fleetmanagementv1alpha1.PipelineFragment{
+		fragment("exporters", 0, "prometheus.remote_write \"default\" { }\n"),
+		fragment("scrape", 10, "prometheus.scrape \"app\" { }"),
+	}, "logging { }")
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	want := "// Fragment: exporters\nprometheus.remote_write \"default\" { }\n\n" +
+		"// Fragment: scrape\nprometheus.scrape \"app\" { }\n\n" +
+		"logging { }"
+	if got != want {
+		t.Errorf("Merge() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeOpenTelemetryCollector(t *testing.T) {
+	exporters := fragment("exporters", 0, `exporters:
+  otlphttp:
+    endpoint: https://otlp.example.com
+service:
+  pipelines:
+    metrics:
+      exporters: [otlphttp]
+`)
+	receivers := fragment("receivers", 10, `receivers:
+  prometheus:
+    config: {}
+service:
+  pipelines:
+    metrics:
+      receivers: [prometheus]
+`)
+
+	got, err := Merge(fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector,
+		[]fleetmanagementv1alpha1.PipelineFragment{exporters, receivers}, "")
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	for _, want := range []string{"otlphttp:", "prometheus:", "exporters:\n      - otlphttp", "receivers:\n      - prometheus"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Merge() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	conflict := fragment("other", 20, "service:\n  pipelines:\n    metrics:\n      exporters: [debug]\n")
+	_, err = Merge(fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector,
+		[]fleetmanagementv1alpha1.PipelineFragment{exporters, receivers, conflict}, "")
+	want := `service.pipelines.metrics.exporters is set by both PipelineFragment "exporters" and PipelineFragment "other"`
+	if err == nil || err.Error() != want {
+		t.Errorf("Merge() error = %v, want %q", err, want)
+	}
+}
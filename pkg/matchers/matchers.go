@@ -0,0 +1,215 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package matchers parses and evaluates collector matchers written in the
+// Prometheus Alertmanager syntax: key=value, key!=value, key=~regex and
+// key!~regex. Values may be double-quoted. Regular expressions are fully
+// anchored, and a missing attribute matches as the empty string.
+package matchers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MatchType is the comparison operator of a Matcher
+type MatchType string
+
+const (
+	// MatchEqual matches attributes equal to the value
+	MatchEqual MatchType = "="
+
+	// MatchNotEqual matches attributes not equal to the value
+	MatchNotEqual MatchType = "!="
+
+	// MatchRegexp matches attributes matching the regular expression
+	MatchRegexp MatchType = "=~"
+
+	// MatchNotRegexp matches attributes not matching the regular expression
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Matcher matches a single collector attribute
+type Matcher struct {
+	Name  string
+	Type  MatchType
+	Value string
+
+	re *regexp.Regexp
+}
+
+// New creates a Matcher, compiling the value for regular expression matchers
+func New(t MatchType, name, value string) (*Matcher, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	m := &Matcher{Name: name, Type: t, Value: value}
+	switch t {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", value, err)
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("unknown match type %q", t)
+	}
+	return m, nil
+}
+
+// Parse parses a matcher such as `collector.os=linux` or `region=~"us-.*"`
+func Parse(s string) (*Matcher, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexAny(s, "=!~")
+	if i < 0 {
+		return nil, fmt.Errorf("missing operator, expected one of =, !=, =~, !~")
+	}
+	name := strings.TrimSpace(s[:i])
+	rest := s[i:]
+
+	var t MatchType
+	switch {
+	case strings.HasPrefix(rest, string(MatchRegexp)):
+		t = MatchRegexp
+	case strings.HasPrefix(rest, string(MatchNotEqual)):
+		t = MatchNotEqual
+	case strings.HasPrefix(rest, string(MatchNotRegexp)):
+		t = MatchNotRegexp
+	case strings.HasPrefix(rest, string(MatchEqual)):
+		t = MatchEqual
+	default:
+		return nil, fmt.Errorf("invalid operator at %q, expected one of =, !=, =~, !~", rest)
+	}
+
+	value := strings.TrimSpace(rest[len(t):])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted value %s", value)
+		}
+		value = unquoted
+	}
+
+	return New(t, name, value)
+}
+
+// Matches reports whether the attribute value v satisfies the matcher
+func (m *Matcher) Matches(v string) bool {
+	switch m.Type {
+	case MatchEqual:
+		return v == m.Value
+	case MatchNotEqual:
+		return v != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(v)
+	case MatchNotRegexp:
+		return !m.re.MatchString(v)
+	default:
+		return false
+	}
+}
+
+// Negate returns the matcher matching the attributes m does not match
+func (m *Matcher) Negate() *Matcher {
+	negated := *m
+	switch m.Type {
+	case MatchEqual:
+		negated.Type = MatchNotEqual
+	case MatchNotEqual:
+		negated.Type = MatchEqual
+	case MatchRegexp:
+		negated.Type = MatchNotRegexp
+	case MatchNotRegexp:
+		negated.Type = MatchRegexp
+	}
+	return &negated
+}
+
+// String formats the matcher in Alertmanager syntax, quoting the value when needed
+func (m *Matcher) String() string {
+	value := m.Value
+	if strings.ContainsAny(value, " \t\",{}") || strings.TrimSpace(value) != value {
+		value = strconv.Quote(value)
+	}
+	return m.Name + string(m.Type) + value
+}
+
+// Matchers is a list of matchers that must all match
+type Matchers []*Matcher
+
+// ParseError reports a matcher that failed to parse and its position in the list
+type ParseError struct {
+	Index int
+	Input string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("matcher %d %q: %v", e.Index, e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseAll parses a list of matchers. The returned error is a *ParseError for the first invalid matcher.
+func ParseAll(ss []string) (Matchers, error) {
+	ms := make(Matchers, 0, len(ss))
+	for i, s := range ss {
+		m, err := Parse(s)
+		if err != nil {
+			return nil, &ParseError{Index: i, Input: s, Err: err}
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// Matches reports whether the attributes satisfy every matcher.
+// Missing attributes are treated as empty strings.
+func (ms Matchers) Matches(attributes map[string]string) bool {
+	for _, m := range ms {
+		if !m.Matches(attributes[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Strings formats every matcher in Alertmanager syntax
+func (ms Matchers) Strings() []string {
+	out := make([]string, 0, len(ms))
+	for _, m := range ms {
+		out = append(out, m.String())
+	}
+	return out
+}
+
+// validateName checks an attribute name
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("missing attribute name")
+	}
+	if strings.ContainsAny(name, " \t\",{}=!~") {
+		return fmt.Errorf("invalid attribute name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package matchers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Matcher
+		wantErr bool
+	}{
+		{in: "collector.os=linux", want: Matcher{Name: "collector.os", Type: MatchEqual, Value: "linux"}},
+		{in: "env != dev", want: Matcher{Name: "env", Type: MatchNotEqual, Value: "dev"}},
+		{in: "region=~us-.*", want: Matcher{Name: "region", Type: MatchRegexp, Value: "us-.*"}},
+		{in: "team!~a|b", want: Matcher{Name: "team", Type: MatchNotRegexp, Value: "a|b"}},
+		{in: `owner="platform team"`, want: Matcher{Name: "owner", Type: MatchEqual, Value: "platform team"}},
+		{in: "env=", want: Matcher{Name: "env", Type: MatchEqual, Value: ""}},
+		{in: "env=~[prod", wantErr: true},
+		{in: "env", wantErr: true},
+		{in: "=prod", wantErr: true},
+		{in: "env~=prod", wantErr: true},
+		{in: `env="prod`, wantErr: true},
+	}
+	for _, tt := range tests {
+		m, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if m.Name != tt.want.Name || m.Type != tt.want.Type || m.Value != tt.want.Value {
+			t.Errorf("Parse(%q) = %s%s%s, want %s%s%s", tt.in, m.Name, m.Type, m.Value, tt.want.Name, tt.want.Type, tt.want.Value)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	ms, err := ParseAll([]string{"collector.os=linux", "region=~us-.*", "env!=dev", "team!~blue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		attrs map[string]string
+		want  bool
+	}{
+		{map[string]string{"collector.os": "linux", "region": "us-east", "env": "prod"}, true},
+		{map[string]string{"collector.os": "linux", "region": "eu-west", "env": "prod"}, false},
+		{map[string]string{"collector.os": "linux", "region": "us-east", "env": "dev"}, false},
+		{map[string]string{"collector.os": "linux", "region": "us-east", "team": "blue"}, false},
+		{map[string]string{"collector.os": "linux", "region": "xus-east"}, false},
+		{map[string]string{}, false},
+	}
+	for _, tt := range tests {
+		if got := ms.Matches(tt.attrs); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestParseAllReportsIndex(t *testing.T) {
+	_, err := ParseAll([]string{"env=prod", "env=~[prod"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("ParseAll() error = %v, want *ParseError", err)
+	}
+	if parseErr.Index != 1 {
+		t.Errorf("ParseError.Index = %d, want 1", parseErr.Index)
+	}
+}
+
+func TestString(t *testing.T) {
+	m, err := New(MatchEqual, "owner", "platform team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.String(); got != `owner="platform team"` {
+		t.Errorf("String() = %s", got)
+	}
+	parsed, err := Parse(m.String())
+	if err != nil || parsed.Value != m.Value {
+		t.Errorf("String() does not round-trip: %v, %v", parsed, err)
+	}
+}
+
+func TestNegate(t *testing.T) {
+	for in, want := range map[string]string{
+		"canary=true":  "canary!=true",
+		"canary!=true": "canary=true",
+		"ring=~a|b":    "ring!~a|b",
+		"ring!~a|b":    "ring=~a|b",
+	} {
+		m, err := Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		negated := m.Negate()
+		if got := negated.String(); got != want {
+			t.Errorf("Parse(%q).Negate() = %s, want %s", in, got, want)
+		}
+		for _, v := range []string{"true", "a", "c"} {
+			if negated.Matches(v) == m.Matches(v) {
+				t.Errorf("Parse(%q).Negate() matches %q like the matcher", in, v)
+			}
+		}
+	}
+}
+
+func TestFromSpec(t *testing.T) {
+	spec := &fleetmanagementv1alpha1.PipelineSpec{
+		Matchers: []string{"env=prod", "region=~[us"},
+		StructuredMatchers: []fleetmanagementv1alpha1.Matcher{
+			{Key: "collector.os", Op: fleetmanagementv1alpha1.MatchOperatorEqual, Value: "linux"},
+			{Key: "team", Op: fleetmanagementv1alpha1.MatchOperatorRegexp, Value: "("},
+		},
+	}
+
+	ms, errs := FromSpec(spec, field.NewPath("spec"))
+	if len(ms) != 2 {
+		t.Errorf("FromSpec() returned %d matchers, want 2", len(ms))
+	}
+	if len(errs) != 2 {
+		t.Fatalf("FromSpec() returned %d errors, want 2: %v", len(errs), errs)
+	}
+	if errs[0].Field != "spec.matchers[1]" || errs[1].Field != "spec.structuredMatchers[1]" {
+		t.Errorf("unexpected error fields %q, %q", errs[0].Field, errs[1].Field)
+	}
+
+	spec = &fleetmanagementv1alpha1.PipelineSpec{
+		Matchers: []string{"env=prod"},
+		Debug:    &fleetmanagementv1alpha1.DebugTarget{CollectorID: "host-1"},
+	}
+	ms, errs = FromSpec(spec, field.NewPath("spec"))
+	if len(errs) != 0 || len(ms) != 2 || ms[1].String() != "collector.ID=host-1" {
+		t.Errorf("FromSpec() = %v, %v, want the debug collector matcher last", ms, errs)
+	}
+
+	spec = &fleetmanagementv1alpha1.PipelineSpec{Matchers: make([]string, 60)}
+	for i := range spec.Matchers {
+		spec.Matchers[i] = "env=prod"
+	}
+	spec.StructuredMatchers = make([]fleetmanagementv1alpha1.Matcher, 41)
+	for i := range spec.StructuredMatchers {
+		spec.StructuredMatchers[i] = fleetmanagementv1alpha1.Matcher{Key: "env", Op: "=", Value: "prod"}
+	}
+	if _, errs := FromSpec(spec, field.NewPath("spec")); len(errs) != 1 || !strings.Contains(errs[0].Error(), "100") {
+		t.Errorf("FromSpec() errors = %v, want too many matchers", errs)
+	}
+}
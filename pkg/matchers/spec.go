@@ -0,0 +1,68 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package matchers
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// MaxMatchers is the maximum number of matchers Fleet Management accepts per pipeline
+const MaxMatchers = 100
+
+// FromSpec parses spec.matchers and spec.structuredMatchers into a single list,
+// followed by a collector.ID matcher for debug pipelines. Errors point at the
+// offending entry below specPath.
+func FromSpec(spec *fleetmanagementv1alpha1.PipelineSpec, specPath *field.Path) (Matchers, field.ErrorList) {
+	var allErrs field.ErrorList
+	ms := make(Matchers, 0, len(spec.Matchers)+len(spec.StructuredMatchers))
+
+	for i, s := range spec.Matchers {
+		m, err := Parse(s)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("matchers").Index(i), s, err.Error()))
+			continue
+		}
+		ms = append(ms, m)
+	}
+
+	for i, sm := range spec.StructuredMatchers {
+		m, err := New(MatchType(sm.Op), sm.Key, sm.Value)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("structuredMatchers").Index(i), sm, err.Error()))
+			continue
+		}
+		ms = append(ms, m)
+	}
+
+	// Debug pipelines only ever reach their one collector
+	if spec.Debug != nil {
+		m, err := New(MatchEqual, "collector.ID", spec.Debug.CollectorID)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("debug", "collectorID"), spec.Debug.CollectorID, err.Error()))
+		} else {
+			ms = append(ms, m)
+		}
+	}
+
+	if total := len(spec.Matchers) + len(spec.StructuredMatchers); total > MaxMatchers {
+		allErrs = append(allErrs, field.TooMany(specPath.Child("structuredMatchers"), total, MaxMatchers))
+	}
+
+	return ms, allErrs
+}
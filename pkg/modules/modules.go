@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package modules prepends PipelineModule declarations to Alloy contents.
+//
+// Modules are included in dependency order: every module comes after the
+// modules listed in its dependsOn, and each module is included once even
+// when several modules depend on it. Two modules, or a module and the
+// pipeline contents, may not declare a component with the same name.
+package modules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// declarePattern matches the label of an Alloy declare block
+var declarePattern = regexp.MustCompile(`(?m)^[ \t]*declare[ \t]+"([^"]+)"[ \t]*\{`)
+
+// Declarations returns the names of the components declared in Alloy contents
+func Declarations(contents string) []string {
+	var names []string
+	for _, m := range declarePattern.FindAllStringSubmatch(contents, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// GetFunc looks up a PipelineModule by name. It returns a nil module when
+// the module does not exist.
+type GetFunc func(name string) (*fleetmanagementv1alpha1.PipelineModule, error)
+
+// NotFoundError is returned when a referenced module does not exist
+type NotFoundError struct {
+	Name string
+	// RequiredBy is the module depending on the missing one, empty for a Pipeline reference
+	RequiredBy string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.RequiredBy == "" {
+		return fmt.Sprintf("PipelineModule %q not found", e.Name)
+	}
+	return fmt.Sprintf("PipelineModule %q required by %q not found", e.Name, e.RequiredBy)
+}
+
+// Resolve returns the requested modules and their dependencies in the order
+// they must be included
+func Resolve(requested []string, get GetFunc) ([]*fleetmanagementv1alpha1.PipelineModule, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	var ordered []*fleetmanagementv1alpha1.PipelineModule
+	state := map[string]int{}
+	var path []string
+
+	var visit func(name, requiredBy string) error
+	visit = func(name, requiredBy string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("PipelineModule dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+		}
+
+		module, err := get(name)
+		if err != nil {
+			return err
+		}
+		if module == nil {
+			return &NotFoundError{Name: name, RequiredBy: requiredBy}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range module.Spec.DependsOn {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+
+		ordered = append(ordered, module)
+		return nil
+	}
+
+	for _, name := range requested {
+		if err := visit(name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Assemble prepends the declarations of the ordered modules to contents. It
+// fails when a component is declared more than once.
+func Assemble(ordered []*fleetmanagementv1alpha1.PipelineModule, contents string) (string, error) {
+	if len(ordered) == 0 {
+		return contents, nil
+	}
+
+	declaredBy := map[string]string{}
+	declare := func(owner, contents string) error {
+		for _, name := range Declarations(contents) {
+			if other, ok := declaredBy[name]; ok {
+				return fmt.Errorf("component %q is declared by both %s and %s", name, other, owner)
+			}
+			declaredBy[name] = owner
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	for _, module := range ordered {
+		if err := declare(fmt.Sprintf("PipelineModule %q", module.Name), module.Spec.Contents); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "// Module: %s\n", module.Name)
+		b.WriteString(strings.TrimRight(module.Spec.Contents, "\n"))
+		b.WriteString("\n\n")
+	}
+	if err := declare("the pipeline contents", contents); err != nil {
+		return "", err
+	}
+
+	b.WriteString(contents)
+	return b.String(), nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modules
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func module(name, contents string, dependsOn ...string) *fleetmanagementv1alpha1.PipelineModule {
+	return &fleetmanagementv1alpha1.PipelineModule{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       fleetmanagementv1alpha1.PipelineModuleSpec{Contents: contents, DependsOn: dependsOn},
+	}
+}
+
+func getter(modules ...*fleetmanagementv1alpha1.PipelineModule) GetFunc {
+	byName := map[string]*fleetmanagementv1alpha1.PipelineModule{}
+	for _, m := range modules {
+		byName[m.Name] = m
+	}
+	return func(name string) (*fleetmanagementv1alpha1.PipelineModule, error) {
+		return byName[name], nil
+	}
+}
+
+func names(ordered []*fleetmanagementv1alpha1.PipelineModule) []string {
+	var out []string
+	for _, m := range ordered {
+		out = append(out, m.Name)
+	}
+	return out
+}
+
+func TestDeclarations(t *testing.T) {
+	contents := `declare "a" {
+  argument "x" { }
+}
+  declare "b_c" { }
+// declare "commented" {
+prometheus.scrape "declare" { }`
+	if got, want := Declarations(contents), []string{"a", "b_c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Declarations() = %v, want %v", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		modules   []*fleetmanagementv1alpha1.PipelineModule
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "dependencies first",
+			requested: []string{"app"},
+			modules: []*fleetmanagementv1alpha1.PipelineModule{
+				module("app", "", "scrape"),
+				module("scrape", "", "write"),
+				module("write", ""),
+			},
+			want: []string{"write", "scrape", "app"},
+		},
+		{
+			name:      "shared dependency included once",
+			requested: []string{"a", "b"},
+			modules: []*fleetmanagementv1alpha1.PipelineModule{
+				module("a", "", "base"),
+				module("b", "", "base"),
+				module("base", ""),
+			},
+			want: []string{"base", "a", "b"},
+		},
+		{
+			name:      "cycle",
+			requested: []string{"a"},
+			modules: []*fleetmanagementv1alpha1.PipelineModule{
+				module("a", "", "b"),
+				module("b", "", "a"),
+			},
+			wantErr: "PipelineModule dependency cycle: a -> b -> a",
+		},
+		{
+			name:      "missing dependency",
+			requested: []string{"a"},
+			modules:   []*fleetmanagementv1alpha1.PipelineModule{module("a", "", "b")},
+			wantErr:   `PipelineModule "b" required by "a" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.requested, getter(tt.modules...))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("Resolve() = %v, want %v", names(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveGetError(t *testing.T) {
+	want := errors.New("boom")
+	_, err := Resolve([]string{"a"}, func(string) (*fleetmanagementv1alpha1.PipelineModule, error) {
+		return nil, want
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("Resolve() error = %v, want %v", err, want)
+	}
+}
+
+func TestAssemble(t *testing.T) {
+	ordered := []*fleetmanagementv1alpha1.PipelineModule{
+		module("write", "declare \"write\" { }\n"),
+		module("scrape", "declare \"scrape\" { }"),
+	}
+
+	got, err := Assemble(ordered, "scrape \"default\" { }")
+	if err != nil {
+		t.Fatalf("Assemble() error = %v", err)
+	}
+	want := "// Module: write\ndeclare \"write\" { }\n\n// Module: scrape\ndeclare \"scrape\" { }\n\nscrape \"default\" { }"
+	if got != want {
+		t.Errorf("Assemble() = %q, want %q", got, want)
+	}
+
+	_, err = Assemble(ordered, "declare \"write\" { }")
+	if err == nil || !strings.Contains(err.Error(), `component "write" is declared by both PipelineModule "write" and the pipeline contents`) {
+		t.Errorf("Assemble() error = %v, want a clash", err)
+	}
+}
@@ -0,0 +1,533 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monitors generates Alloy configuration from Prometheus Operator
+// ServiceMonitors and PodMonitors.
+//
+// Fleet Management runs every pipeline as its own module, so a component of
+// one pipeline cannot forward to another. Generate therefore writes all
+// monitors into one configuration sharing a single prometheus.remote_write.
+//
+// Only the fields that need no Secrets are supported: TLS, authorization and
+// basic auth settings are ignored, as reported by Ignored.
+package monitors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/grafana/fleet-management-operator/pkg/alloy"
+)
+
+const (
+	// ServiceMonitorKind scrapes the endpoints of Services
+	ServiceMonitorKind = "ServiceMonitor"
+	// PodMonitorKind scrapes Pods
+	PodMonitorKind = "PodMonitor"
+)
+
+// GroupVersion is the API group and version of the Prometheus Operator monitors
+var GroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+
+// Monitor is a ServiceMonitor or PodMonitor, reduced to the fields used to generate Alloy
+type Monitor struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Spec      MonitorSpec
+}
+
+// MonitorSpec is the spec of a ServiceMonitor or PodMonitor
+type MonitorSpec struct {
+	JobLabel            string                `json:"jobLabel,omitempty"`
+	Selector            metav1.LabelSelector  `json:"selector"`
+	NamespaceSelector   NamespaceSelector     `json:"namespaceSelector,omitempty"`
+	Endpoints           []Endpoint            `json:"endpoints,omitempty"`
+	PodMetricsEndpoints []Endpoint            `json:"podMetricsEndpoints,omitempty"`
+	SampleLimit         *uint64               `json:"sampleLimit,omitempty"`
+	TargetLabels        []string              `json:"targetLabels,omitempty"`
+	PodTargetLabels     []string              `json:"podTargetLabels,omitempty"`
+	AttachMetadata      *AttachMetadataConfig `json:"attachMetadata,omitempty"`
+}
+
+// NamespaceSelector selects the namespaces targets are discovered in
+type NamespaceSelector struct {
+	Any        bool     `json:"any,omitempty"`
+	MatchNames []string `json:"matchNames,omitempty"`
+}
+
+// AttachMetadataConfig adds node metadata to the targets
+type AttachMetadataConfig struct {
+	Node *bool `json:"node,omitempty"`
+}
+
+// Endpoint is a ServiceMonitor endpoint or a PodMonitor podMetricsEndpoint
+type Endpoint struct {
+	Port              string              `json:"port,omitempty"`
+	TargetPort        *intstr.IntOrString `json:"targetPort,omitempty"`
+	Path              string              `json:"path,omitempty"`
+	Scheme            string              `json:"scheme,omitempty"`
+	Params            map[string][]string `json:"params,omitempty"`
+	Interval          string              `json:"interval,omitempty"`
+	ScrapeTimeout     string              `json:"scrapeTimeout,omitempty"`
+	HonorLabels       bool                `json:"honorLabels,omitempty"`
+	HonorTimestamps   *bool               `json:"honorTimestamps,omitempty"`
+	Relabelings       []RelabelConfig     `json:"relabelings,omitempty"`
+	MetricRelabelings []RelabelConfig     `json:"metricRelabelings,omitempty"`
+
+	// Settings that need Secrets or files in the cluster, which Ignored reports
+	TLSConfig         map[string]any `json:"tlsConfig,omitempty"`
+	BasicAuth         map[string]any `json:"basicAuth,omitempty"`
+	Authorization     map[string]any `json:"authorization,omitempty"`
+	OAuth2            map[string]any `json:"oauth2,omitempty"`
+	BearerTokenSecret map[string]any `json:"bearerTokenSecret,omitempty"`
+	BearerTokenFile   string         `json:"bearerTokenFile,omitempty"`
+}
+
+// RelabelConfig is a Prometheus relabeling rule
+type RelabelConfig struct {
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	Separator    *string  `json:"separator,omitempty"`
+	TargetLabel  string   `json:"targetLabel,omitempty"`
+	Regex        string   `json:"regex,omitempty"`
+	Modulus      uint64   `json:"modulus,omitempty"`
+	Replacement  *string  `json:"replacement,omitempty"`
+	Action       string   `json:"action,omitempty"`
+}
+
+// FromUnstructured reads a ServiceMonitor or PodMonitor
+func FromUnstructured(obj map[string]any) (*Monitor, error) {
+	m := &Monitor{}
+	meta, _ := obj["metadata"].(map[string]any)
+	m.Kind, _ = obj["kind"].(string)
+	m.Namespace, _ = meta["namespace"].(string)
+	m.Name, _ = meta["name"].(string)
+	if m.Kind != ServiceMonitorKind && m.Kind != PodMonitorKind {
+		return nil, fmt.Errorf("unsupported kind %q", m.Kind)
+	}
+
+	spec, _ := obj["spec"].(map[string]any)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &m.Spec); err != nil {
+		return nil, fmt.Errorf("invalid %s spec: %w", m.Kind, err)
+	}
+	return m, nil
+}
+
+// Options configure the generated Alloy
+type Options struct {
+	// APIServer is the address of the Kubernetes API server the collectors
+	// discover targets with. Empty uses the in-cluster configuration of the
+	// collectors.
+	APIServer string
+	// KubeconfigFile is the path of a kubeconfig file on the collectors to
+	// discover targets with, instead of APIServer
+	KubeconfigFile string
+	// BearerTokenFile is the path of a file on the collectors holding the
+	// token to authenticate to APIServer with
+	BearerTokenFile string
+	// CAFile is the path of a file on the collectors holding the CA
+	// certificate of APIServer
+	CAFile string
+
+	// RemoteWriteURL is where scraped metrics are sent
+	RemoteWriteURL string
+	// RemoteWriteUsername is the basic auth username of the remote write endpoint
+	RemoteWriteUsername string
+	// RemoteWritePasswordEnv is the environment variable of the collectors
+	// holding the basic auth password of the remote write endpoint
+	RemoteWritePasswordEnv string
+}
+
+// remoteWriteLabel is the label of the prometheus.remote_write every monitor forwards to
+const remoteWriteLabel = "monitors"
+
+// Check reports why Alloy cannot be generated from a monitor, if it cannot
+func Check(m *Monitor) error {
+	if len(m.endpoints()) == 0 {
+		return fmt.Errorf("%s has no endpoints", m.Kind)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&m.Spec.Selector); err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+	return nil
+}
+
+// Generate returns the formatted Alloy configuration scraping the targets of
+// the monitors: for each monitor, one discovery.kubernetes component and a
+// discovery.relabel, prometheus.scrape and optional prometheus.relabel per
+// endpoint, all forwarding to one shared prometheus.remote_write
+func Generate(ms []*Monitor, opts Options) (string, error) {
+	if len(ms) == 0 {
+		return "", fmt.Errorf("no monitors")
+	}
+	w := &writer{}
+	for _, m := range ms {
+		if err := Check(m); err != nil {
+			return "", fmt.Errorf("%s %s/%s: %w", m.Kind, m.Namespace, m.Name, err)
+		}
+		writeMonitor(w, m, opts)
+		w.line("")
+	}
+
+	w.block("prometheus.remote_write", remoteWriteLabel, func() {
+		w.block("endpoint", "", func() {
+			w.attr("url", quote(opts.RemoteWriteURL))
+			if opts.RemoteWriteUsername != "" || opts.RemoteWritePasswordEnv != "" {
+				w.block("basic_auth", "", func() {
+					if opts.RemoteWriteUsername != "" {
+						w.attr("username", quote(opts.RemoteWriteUsername))
+					}
+					if opts.RemoteWritePasswordEnv != "" {
+						w.attr("password", "sys.env("+quote(opts.RemoteWritePasswordEnv)+")")
+					}
+				})
+			}
+		})
+	})
+
+	// Aligns the attributes the way alloy fmt does
+	return alloy.Format(w.String())
+}
+
+// Ignored returns the paths of the endpoint settings of a monitor that are
+// left out of the generated Alloy, such as endpoints[0].tlsConfig
+func Ignored(m *Monitor) []string {
+	field := "endpoints"
+	if m.Kind == PodMonitorKind {
+		field = "podMetricsEndpoints"
+	}
+	var ignored []string
+	for i, ep := range m.endpoints() {
+		for _, setting := range []struct {
+			name string
+			set  bool
+		}{
+			{"tlsConfig", len(ep.TLSConfig) > 0},
+			{"basicAuth", len(ep.BasicAuth) > 0},
+			{"authorization", len(ep.Authorization) > 0},
+			{"oauth2", len(ep.OAuth2) > 0},
+			{"bearerTokenSecret", len(ep.BearerTokenSecret) > 0},
+			{"bearerTokenFile", ep.BearerTokenFile != ""},
+		} {
+			if setting.set {
+				ignored = append(ignored, fmt.Sprintf("%s[%d].%s", field, i, setting.name))
+			}
+		}
+	}
+	return ignored
+}
+
+// endpoints returns the endpoints of a ServiceMonitor or the
+// podMetricsEndpoints of a PodMonitor
+func (m *Monitor) endpoints() []Endpoint {
+	if m.Kind == PodMonitorKind {
+		return m.Spec.PodMetricsEndpoints
+	}
+	return m.Spec.Endpoints
+}
+
+// writeMonitor writes the components scraping the targets of a checked monitor
+func writeMonitor(w *writer, m *Monitor, opts Options) {
+	endpoints := m.endpoints()
+	role, selectorRole := "endpoints", "service"
+	if m.Kind == PodMonitorKind {
+		role, selectorRole = "pod", "pod"
+	}
+	selector, _ := metav1.LabelSelectorAsSelector(&m.Spec.Selector)
+
+	kind := strings.ToLower(m.Kind[:1]) + m.Kind[1:]
+	id := componentID(m)
+	remoteWrite := "prometheus.remote_write." + remoteWriteLabel + ".receiver"
+
+	w.block("discovery.kubernetes", id, func() {
+		w.attr("role", quote(role))
+		if opts.APIServer != "" {
+			w.attr("api_server", quote(opts.APIServer))
+		}
+		if opts.KubeconfigFile != "" {
+			w.attr("kubeconfig_file", quote(opts.KubeconfigFile))
+		}
+		if opts.BearerTokenFile != "" {
+			w.attr("bearer_token_file", quote(opts.BearerTokenFile))
+		}
+		if opts.CAFile != "" {
+			w.block("tls_config", "", func() {
+				w.attr("ca_file", quote(opts.CAFile))
+			})
+		}
+		if !m.Spec.NamespaceSelector.Any {
+			names := m.Spec.NamespaceSelector.MatchNames
+			if len(names) == 0 {
+				names = []string{m.Namespace}
+			}
+			w.block("namespaces", "", func() {
+				w.attr("names", quoteList(names))
+			})
+		}
+		if !selector.Empty() {
+			w.block("selectors", "", func() {
+				w.attr("role", quote(selectorRole))
+				w.attr("label", quote(selector.String()))
+			})
+		}
+		if m.Spec.AttachMetadata != nil && m.Spec.AttachMetadata.Node != nil && *m.Spec.AttachMetadata.Node {
+			w.block("attach_metadata", "", func() {
+				w.attr("node", "true")
+			})
+		}
+	})
+
+	for i, ep := range endpoints {
+		epID := fmt.Sprintf("%s_%d", id, i)
+		w.line("")
+		w.block("discovery.relabel", epID, func() {
+			w.attr("targets", "discovery.kubernetes."+id+".targets")
+			for _, rule := range targetRelabelings(m, ep) {
+				w.rule(rule)
+			}
+		})
+
+		forwardTo := remoteWrite
+		if len(ep.MetricRelabelings) > 0 {
+			forwardTo = "prometheus.relabel." + epID + ".receiver"
+		}
+		w.line("")
+		w.block("prometheus.scrape", epID, func() {
+			w.attr("targets", "discovery.relabel."+epID+".output")
+			w.attr("forward_to", "["+forwardTo+"]")
+			w.attr("job_name", quote(fmt.Sprintf("%s/%s/%s/%d", kind, m.Namespace, m.Name, i)))
+			if ep.Interval != "" {
+				w.attr("scrape_interval", quote(ep.Interval))
+			}
+			if ep.ScrapeTimeout != "" {
+				w.attr("scrape_timeout", quote(ep.ScrapeTimeout))
+			}
+			if ep.Path != "" {
+				w.attr("metrics_path", quote(ep.Path))
+			}
+			if ep.Scheme != "" {
+				w.attr("scheme", quote(strings.ToLower(ep.Scheme)))
+			}
+			if ep.HonorLabels {
+				w.attr("honor_labels", "true")
+			}
+			if ep.HonorTimestamps != nil {
+				w.attr("honor_timestamps", strconv.FormatBool(*ep.HonorTimestamps))
+			}
+			if m.Spec.SampleLimit != nil {
+				w.attr("sample_limit", strconv.FormatUint(*m.Spec.SampleLimit, 10))
+			}
+			if len(ep.Params) > 0 {
+				w.attr("params", params(ep.Params))
+			}
+		})
+
+		if len(ep.MetricRelabelings) > 0 {
+			w.line("")
+			w.block("prometheus.relabel", epID, func() {
+				w.attr("forward_to", "["+remoteWrite+"]")
+				for _, rule := range ep.MetricRelabelings {
+					w.rule(rule)
+				}
+			})
+		}
+	}
+}
+
+// targetRelabelings returns the rules Prometheus Operator would generate to
+// select the endpoint's targets and label them, followed by the endpoint's own
+func targetRelabelings(m *Monitor, ep Endpoint) []RelabelConfig {
+	var rules []RelabelConfig
+	keep := func(label, value string) {
+		rules = append(rules, RelabelConfig{SourceLabels: []string{label}, Regex: value, Action: "keep"})
+	}
+	replace := func(label, target string) {
+		rules = append(rules, RelabelConfig{SourceLabels: []string{label}, TargetLabel: target})
+	}
+	// copyLabel copies an object label to the target, if the object has it
+	copyLabel := func(prefix, label, target string) {
+		rules = append(rules, RelabelConfig{
+			SourceLabels: []string{prefix + sanitizeLabel(label)}, Regex: "(.+)", TargetLabel: target,
+		})
+	}
+
+	if m.Kind == PodMonitorKind {
+		rules = append(rules, RelabelConfig{
+			SourceLabels: []string{"__meta_kubernetes_pod_phase"}, Regex: "(Failed|Succeeded)", Action: "drop",
+		})
+		if ep.Port != "" {
+			keep("__meta_kubernetes_pod_container_port_name", ep.Port)
+		}
+	} else if ep.Port != "" {
+		keep("__meta_kubernetes_endpoint_port_name", ep.Port)
+	}
+	if ep.TargetPort != nil {
+		if ep.TargetPort.Type == intstr.Int {
+			keep("__meta_kubernetes_pod_container_port_number", ep.TargetPort.String())
+		} else {
+			keep("__meta_kubernetes_pod_container_port_name", ep.TargetPort.StrVal)
+		}
+	}
+
+	replace("__meta_kubernetes_namespace", "namespace")
+	replace("__meta_kubernetes_pod_name", "pod")
+	replace("__meta_kubernetes_pod_container_name", "container")
+	if m.Kind == PodMonitorKind {
+		for _, label := range m.Spec.PodTargetLabels {
+			copyLabel("__meta_kubernetes_pod_label_", label, sanitizeLabel(label))
+		}
+		job := fmt.Sprintf("%s/%s", m.Namespace, m.Name)
+		rules = append(rules, RelabelConfig{TargetLabel: "job", Replacement: &job})
+		if m.Spec.JobLabel != "" {
+			copyLabel("__meta_kubernetes_pod_label_", m.Spec.JobLabel, "job")
+		}
+	} else {
+		replace("__meta_kubernetes_service_name", "service")
+		for _, label := range m.Spec.TargetLabels {
+			copyLabel("__meta_kubernetes_service_label_", label, sanitizeLabel(label))
+		}
+		for _, label := range m.Spec.PodTargetLabels {
+			copyLabel("__meta_kubernetes_pod_label_", label, sanitizeLabel(label))
+		}
+		replace("__meta_kubernetes_service_name", "job")
+		if m.Spec.JobLabel != "" {
+			copyLabel("__meta_kubernetes_service_label_", m.Spec.JobLabel, "job")
+		}
+	}
+	if ep.Port != "" {
+		endpoint := ep.Port
+		rules = append(rules, RelabelConfig{TargetLabel: "endpoint", Replacement: &endpoint})
+	}
+
+	return append(rules, ep.Relabelings...)
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// componentID returns the label of the components of a monitor. Names with
+// characters sanitizeLabel replaces, such as a-b/c and a/b-c, are suffixed with
+// a hash of the kind, namespace and name so their labels stay unique.
+func componentID(m *Monitor) string {
+	id := strings.ToLower(m.Kind) + "_" + m.Namespace + "_" + m.Name
+	if sanitized := sanitizeLabel(id); sanitized != id {
+		sum := sha256.Sum256([]byte(m.Kind + "/" + m.Namespace + "/" + m.Name))
+		return sanitized + "_" + hex.EncodeToString(sum[:])[:8]
+	}
+	return id
+}
+
+// sanitizeLabel replaces the characters Prometheus does not allow in label
+// names, which Alloy does not allow in component references either
+func sanitizeLabel(label string) string {
+	return invalidLabelChars.ReplaceAllString(label, "_")
+}
+
+// writer builds Alloy configuration with tab indentation
+type writer struct {
+	b      strings.Builder
+	indent int
+}
+
+func (w *writer) line(s string) {
+	if s != "" {
+		w.b.WriteString(strings.Repeat("\t", w.indent))
+		w.b.WriteString(s)
+	}
+	w.b.WriteByte('\n')
+}
+
+func (w *writer) attr(name, value string) {
+	w.line(name + " = " + value)
+}
+
+func (w *writer) block(name, label string, body func()) {
+	header := name
+	if label != "" {
+		header += " " + quote(label)
+	}
+	w.line(header + " {")
+	w.indent++
+	body()
+	w.indent--
+	w.line("}")
+}
+
+// rule writes a relabeling rule block
+func (w *writer) rule(r RelabelConfig) {
+	w.block("rule", "", func() {
+		if len(r.SourceLabels) > 0 {
+			w.attr("source_labels", quoteList(r.SourceLabels))
+		}
+		if r.Separator != nil {
+			w.attr("separator", quote(*r.Separator))
+		}
+		if r.Regex != "" {
+			w.attr("regex", quote(r.Regex))
+		}
+		if r.Modulus != 0 {
+			w.attr("modulus", strconv.FormatUint(r.Modulus, 10))
+		}
+		if r.TargetLabel != "" {
+			w.attr("target_label", quote(r.TargetLabel))
+		}
+		if r.Replacement != nil {
+			w.attr("replacement", quote(*r.Replacement))
+		}
+		if r.Action != "" {
+			w.attr("action", quote(strings.ToLower(r.Action)))
+		}
+	})
+}
+
+func (w *writer) String() string {
+	return w.b.String()
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// params renders scrape parameters as an Alloy object of string lists
+func params(values map[string][]string) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	fields := make([]string, len(keys))
+	for i, k := range keys {
+		fields[i] = quote(k) + " = " + quoteList(values[k])
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
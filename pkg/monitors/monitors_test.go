@@ -0,0 +1,246 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitors
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/grafana/fleet-management-operator/pkg/alloy"
+)
+
+func load(t *testing.T, manifest string) *Monitor {
+	t.Helper()
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil {
+		t.Fatal(err)
+	}
+	m, err := FromUnstructured(obj)
+	if err != nil {
+		t.Fatalf("FromUnstructured() error = %v", err)
+	}
+	return m
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name: "ServiceMonitor",
+			manifest: `
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: api
+  namespace: shop
+spec:
+  jobLabel: app.kubernetes.io/name
+  selector:
+    matchLabels:
+      app: api
+  endpoints:
+  - port: metrics
+    interval: 30s
+    path: /stats
+    metricRelabelings:
+    - sourceLabels: [__name__]
+      regex: go_.*
+      action: Drop
+`,
+			want: []string{
+				`discovery.kubernetes "servicemonitor_shop_api" {`,
+				`role = "endpoints"`,
+				`names = ["shop"]`,
+				`label = "app=api"`,
+				`source_labels = ["__meta_kubernetes_endpoint_port_name"]`,
+				`source_labels = ["__meta_kubernetes_service_label_app_kubernetes_io_name"]`,
+				`job_name        = "serviceMonitor/shop/api/0"`,
+				`scrape_interval = "30s"`,
+				`forward_to      = [prometheus.relabel.servicemonitor_shop_api_0.receiver]`,
+				`action        = "drop"`,
+				`url = "https://prom.example.com/api/v1/write"`,
+				`password = sys.env("PROM_PASSWORD")`,
+			},
+		},
+		{
+			name: "PodMonitor in any namespace",
+			manifest: `
+apiVersion: monitoring.coreos.com/v1
+kind: PodMonitor
+metadata:
+  name: workers
+  namespace: jobs
+spec:
+  namespaceSelector:
+    any: true
+  selector:
+    matchExpressions:
+    - key: tier
+      operator: In
+      values: [batch]
+  podMetricsEndpoints:
+  - targetPort: 9090
+    honorLabels: true
+`,
+			want: []string{
+				`role = "pod"`,
+				`label = "tier in (batch)"`,
+				`regex         = "(Failed|Succeeded)"`,
+				`source_labels = ["__meta_kubernetes_pod_container_port_number"]`,
+				`regex         = "9090"`,
+				`replacement  = "jobs/workers"`,
+				`job_name     = "podMonitor/jobs/workers/0"`,
+				`forward_to   = [prometheus.remote_write.monitors.receiver]`,
+				`honor_labels = true`,
+			},
+		},
+	}
+
+	opts := Options{
+		RemoteWriteURL:         "https://prom.example.com/api/v1/write",
+		RemoteWriteUsername:    "1234",
+		RemoteWritePasswordEnv: "PROM_PASSWORD",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents, err := Generate([]*Monitor{load(t, tt.manifest)}, opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(contents, want) {
+					t.Errorf("Generate() is missing %q:\n%s", want, contents)
+				}
+			}
+			if strings.Contains(tt.manifest, "any: true") && strings.Contains(contents, "namespaces {") {
+				t.Errorf("Generate() restricts namespaces:\n%s", contents)
+			}
+			if _, err := alloy.Parse(contents); err != nil {
+				t.Errorf("Generate() returned invalid Alloy: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateWithoutEndpoints(t *testing.T) {
+	m := load(t, `
+kind: ServiceMonitor
+metadata:
+  name: empty
+  namespace: default
+spec:
+  selector: {}
+`)
+	if err := Check(m); err == nil {
+		t.Error("Check() of a ServiceMonitor without endpoints succeeded")
+	}
+	if _, err := Generate([]*Monitor{m}, Options{RemoteWriteURL: "http://prom"}); err == nil {
+		t.Error("Generate() of a ServiceMonitor without endpoints succeeded")
+	}
+}
+
+func TestGenerateKeepsComponentsUnique(t *testing.T) {
+	monitors := []*Monitor{
+		{Kind: ServiceMonitorKind, Namespace: "a-b", Name: "c", Spec: MonitorSpec{Endpoints: []Endpoint{{Port: "metrics"}}}},
+		{Kind: ServiceMonitorKind, Namespace: "a", Name: "b-c", Spec: MonitorSpec{Endpoints: []Endpoint{{Port: "metrics"}}}},
+	}
+	if a, b := componentID(monitors[0]), componentID(monitors[1]); a == b {
+		t.Errorf("componentID() = %q for both a-b/c and a/b-c", a)
+	}
+	if got := componentID(&Monitor{Kind: PodMonitorKind, Namespace: "shop", Name: "api"}); got != "podmonitor_shop_api" {
+		t.Errorf("componentID() = %q, want podmonitor_shop_api", got)
+	}
+
+	contents, err := Generate(monitors, Options{RemoteWriteURL: "http://prom"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := alloy.Parse(contents); err != nil {
+		t.Errorf("Generate() returned invalid Alloy: %v", err)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	m := load(t, `
+kind: PodMonitor
+metadata:
+  name: api
+  namespace: shop
+spec:
+  selector: {}
+  podMetricsEndpoints:
+  - port: metrics
+  - port: https
+    tlsConfig:
+      insecureSkipVerify: true
+    basicAuth:
+      username:
+        name: creds
+        key: user
+`)
+	got := Ignored(m)
+	want := []string{"podMetricsEndpoints[1].tlsConfig", "podMetricsEndpoints[1].basicAuth"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Ignored() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateSharesRemoteWrite(t *testing.T) {
+	monitor := func(kind, name string) *Monitor {
+		return &Monitor{Kind: kind, Namespace: "shop", Name: name, Spec: MonitorSpec{
+			Endpoints:           []Endpoint{{Port: "metrics"}},
+			PodMetricsEndpoints: []Endpoint{{Port: "metrics"}},
+		}}
+	}
+	opts := Options{
+		APIServer:       "https://kubernetes.example.com:6443",
+		BearerTokenFile: "/var/run/secrets/prod/token",
+		CAFile:          "/var/run/secrets/prod/ca.crt",
+		RemoteWriteURL:  "https://prom.example.com/api/v1/write",
+	}
+
+	contents, err := Generate([]*Monitor{
+		monitor(ServiceMonitorKind, "api"), monitor(PodMonitorKind, "api"), monitor(ServiceMonitorKind, "web"),
+	}, opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if n := strings.Count(contents, `prometheus.remote_write "monitors"`); n != 1 {
+		t.Errorf("Generate() has %d prometheus.remote_write components, want 1:\n%s", n, contents)
+	}
+	for _, want := range []string{
+		`discovery.kubernetes "servicemonitor_shop_api" {`,
+		`discovery.kubernetes "podmonitor_shop_api" {`,
+		`discovery.kubernetes "servicemonitor_shop_web" {`,
+		`api_server        = "https://kubernetes.example.com:6443"`,
+		`bearer_token_file = "/var/run/secrets/prod/token"`,
+		`ca_file = "/var/run/secrets/prod/ca.crt"`,
+	} {
+		if !strings.Contains(contents, want) {
+			t.Errorf("Generate() is missing %q:\n%s", want, contents)
+		}
+	}
+	if _, err := alloy.Parse(contents); err != nil {
+		t.Errorf("Generate() returned invalid Alloy: %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming derives Fleet Management pipeline names from Pipeline resources.
+//
+// Fleet Management pipeline names must be valid Alloy identifiers, while
+// Kubernetes names usually contain hyphens and dots. Names generated by the
+// NamespacedName and Template strategies are therefore sanitized
+// deterministically: invalid characters become underscores, and names that
+// were changed or truncated get a hash suffix so they cannot collide. The MetadataName strategy uses metadata.name
+// as is so that the remote names of existing Pipelines never change.
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+const (
+	// MaxLength is the maximum length of a generated pipeline name
+	MaxLength = 63
+
+	// hashLength is the number of hex characters of the hash suffix added to truncated names
+	hashLength = 8
+
+	// separator joins name parts. Hyphens are not valid in Alloy identifiers.
+	separator = "_"
+)
+
+// identifierPattern matches valid Alloy identifiers
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Affix controls how remote pipeline names are qualified so that
+// several clusters or namespaces can share one Fleet Management stack
+type Affix string
+
+const (
+	// AffixNone uses the pipeline name as is
+	AffixNone Affix = ""
+
+	// AffixClusterPrefix prepends the cluster name
+	AffixClusterPrefix Affix = "ClusterPrefix"
+
+	// AffixClusterSuffix appends the cluster name
+	AffixClusterSuffix Affix = "ClusterSuffix"
+
+	// AffixNamespacePrefix prepends the Pipeline's namespace
+	AffixNamespacePrefix Affix = "NamespacePrefix"
+
+	// AffixNamespaceSuffix appends the Pipeline's namespace
+	AffixNamespaceSuffix Affix = "NamespaceSuffix"
+)
+
+// ParseAffix validates an Affix given on the command line
+func ParseAffix(s string) (Affix, error) {
+	switch a := Affix(s); a {
+	case AffixNone, AffixClusterPrefix, AffixClusterSuffix, AffixNamespacePrefix, AffixNamespaceSuffix:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown pipeline name affix %q", s)
+	}
+}
+
+// ParseStrategy validates a NamingStrategy given on the command line
+func ParseStrategy(s string) (fleetmanagementv1alpha1.NamingStrategy, error) {
+	switch st := fleetmanagementv1alpha1.NamingStrategy(s); st {
+	case fleetmanagementv1alpha1.NamingStrategyMetadataName,
+		fleetmanagementv1alpha1.NamingStrategyNamespacedName,
+		fleetmanagementv1alpha1.NamingStrategyTemplate:
+		return st, nil
+	default:
+		return "", fmt.Errorf("unknown naming strategy %q", s)
+	}
+}
+
+// TemplateData is passed to name templates
+type TemplateData struct {
+	Name      string
+	Namespace string
+	Cluster   string
+	Labels    map[string]string
+}
+
+// Resolver computes remote pipeline names using the operator-wide defaults
+type Resolver struct {
+	// ClusterName is the name of the cluster the operator runs in
+	ClusterName string
+
+	// Affix optionally qualifies names with the cluster or namespace.
+	// It is not applied to names produced by the Template strategy.
+	Affix Affix
+
+	// DefaultStrategy is used for Pipelines without spec.namingStrategy.
+	// An empty value means MetadataName.
+	DefaultStrategy fleetmanagementv1alpha1.NamingStrategy
+
+	// DefaultTemplate is used by the Template strategy when spec.nameTemplate is empty
+	DefaultTemplate string
+}
+
+// RemoteName returns the name of the pipeline in Fleet Management.
+// An explicit spec.name is used verbatim apart from the affix. Debug pipelines
+// get a suffix derived from their UID so that each one is unique.
+func (r *Resolver) RemoteName(pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	name, err := r.remoteName(pipeline)
+	if err != nil || pipeline.Spec.Debug == nil {
+		return name, err
+	}
+	return Sanitize(name + separator + debugSuffix(pipeline)), nil
+}
+
+// debugSuffix returns the suffix added to the remote name of a debug pipeline
+func debugSuffix(pipeline *fleetmanagementv1alpha1.Pipeline) string {
+	uid := strings.ReplaceAll(string(pipeline.UID), "-", "")
+	if len(uid) > hashLength {
+		uid = uid[:hashLength]
+	}
+	return "debug" + separator + uid
+}
+
+// remoteName returns the remote name of a pipeline before any debug suffix
+func (r *Resolver) remoteName(pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	if pipeline.Spec.Name != "" {
+		return r.qualify(pipeline, pipeline.Spec.Name), nil
+	}
+
+	strategy := pipeline.Spec.NamingStrategy
+	if strategy == "" {
+		strategy = r.DefaultStrategy
+	}
+
+	switch strategy {
+	case "", fleetmanagementv1alpha1.NamingStrategyMetadataName:
+		// Not sanitized: Pipelines synced before naming strategies existed
+		// must keep their remote name
+		return r.qualify(pipeline, pipeline.Name), nil
+
+	case fleetmanagementv1alpha1.NamingStrategyNamespacedName:
+		return Sanitize(r.qualify(pipeline, pipeline.Namespace+separator+pipeline.Name)), nil
+
+	case fleetmanagementv1alpha1.NamingStrategyTemplate:
+		name, err := r.render(pipeline)
+		if err != nil {
+			return "", err
+		}
+		return Sanitize(name), nil
+
+	default:
+		return "", fmt.Errorf("unknown naming strategy %q", strategy)
+	}
+}
+
+// qualify adds the configured affix to name
+func (r *Resolver) qualify(pipeline *fleetmanagementv1alpha1.Pipeline, name string) string {
+	switch r.Affix {
+	case AffixClusterPrefix:
+		return Sanitize(r.ClusterName) + separator + name
+	case AffixClusterSuffix:
+		return name + separator + Sanitize(r.ClusterName)
+	case AffixNamespacePrefix:
+		return Sanitize(pipeline.Namespace) + separator + name
+	case AffixNamespaceSuffix:
+		return name + separator + Sanitize(pipeline.Namespace)
+	default:
+		return name
+	}
+}
+
+// render executes the name template of a Template strategy pipeline
+func (r *Resolver) render(pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	text := pipeline.Spec.NameTemplate
+	if text == "" {
+		text = r.DefaultTemplate
+	}
+	if text == "" {
+		return "", fmt.Errorf("naming strategy Template requires spec.nameTemplate or a default name template")
+	}
+
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid name template: %w", err)
+	}
+
+	var b strings.Builder
+	err = tmpl.Execute(&b, TemplateData{
+		Name:      pipeline.Name,
+		Namespace: pipeline.Namespace,
+		Cluster:   r.ClusterName,
+		Labels:    pipeline.Labels,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render name template: %w", err)
+	}
+
+	name := strings.TrimSpace(b.String())
+	if name == "" {
+		return "", fmt.Errorf("name template rendered an empty name")
+	}
+	return name, nil
+}
+
+// Sanitize turns s into a valid Alloy identifier. Characters other than
+// letters, digits and underscores become underscores and a leading digit is
+// prefixed with an underscore. Names changed that way are suffixed with a hash
+// of the original value, so team-a/x and team/a-x stay distinct, and names
+// longer than MaxLength are truncated before the suffix. Valid identifiers of
+// at most MaxLength characters are returned as is.
+func Sanitize(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	if name == s && len(name) <= MaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(s))
+	name = name[:min(len(name), MaxLength-hashLength-len(separator))]
+	return name + separator + hex.EncodeToString(sum[:])[:hashLength]
+}
+
+// ValidateIdentifier returns an error if name is not a valid Alloy identifier
+func ValidateIdentifier(name string) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("%q is not a valid Alloy identifier: use letters, digits and underscores, not starting with a digit", name)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"pipeline_sample", "pipeline_sample"},
+		{"my-pipeline", "my_pipeline_35a7604f"},
+		{"team.a/metrics-v2", "team_a_metrics_v2_13c9cc26"},
+		{"1st-pipeline", "_1st_pipeline_026216cc"},
+		{"", "__e3b0c442"},
+	}
+	for _, tt := range tests {
+		if got := Sanitize(tt.in); got != tt.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeKeepsChangedNamesDistinct(t *testing.T) {
+	if got := Sanitize("pipeline_sample"); got != "pipeline_sample" {
+		t.Errorf("Sanitize() changed the valid identifier pipeline_sample to %q", got)
+	}
+
+	resolver := Resolver{DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyNamespacedName}
+	a, err := resolver.RemoteName(&fleetmanagementv1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := resolver.RemoteName(&fleetmanagementv1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "a-x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Errorf("team-a/x and team/a-x have the same remote name %q", a)
+	}
+	if Sanitize("a-b") == Sanitize("a.b") {
+		t.Errorf("a-b and a.b sanitized to the same value %q", Sanitize("a-b"))
+	}
+}
+
+func TestSanitizeTruncatesWithHash(t *testing.T) {
+	long := strings.Repeat("a", 100)
+	got := Sanitize(long)
+	if len(got) != MaxLength {
+		t.Fatalf("len(Sanitize(long)) = %d, want %d", len(got), MaxLength)
+	}
+	if got != Sanitize(long) {
+		t.Errorf("Sanitize is not deterministic")
+	}
+	if other := Sanitize(long + "b"); other == got {
+		t.Errorf("distinct long names sanitized to the same value %q", got)
+	}
+	if err := ValidateIdentifier(got); err != nil {
+		t.Errorf("truncated name is not a valid identifier: %v", err)
+	}
+}
+
+func TestRemoteName(t *testing.T) {
+	pipeline := func(spec fleetmanagementv1alpha1.PipelineSpec) *fleetmanagementv1alpha1.Pipeline {
+		return &fleetmanagementv1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "node-metrics",
+				Namespace: "team-a",
+				Labels:    map[string]string{"team": "a"},
+				UID:       "5f2c8a1e-93b4-4d0e-a7c1-0b6e2d9f4a31",
+			},
+			Spec: spec,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		resolver Resolver
+		spec     fleetmanagementv1alpha1.PipelineSpec
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "explicit name is used verbatim",
+			spec: fleetmanagementv1alpha1.PipelineSpec{Name: "custom"},
+			want: "custom",
+		},
+		{
+			name: "metadata name by default",
+			want: "node-metrics",
+		},
+		{
+			name:     "namespaced name from operator default",
+			resolver: Resolver{DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyNamespacedName},
+			want:     "team_a_node_metrics_50b46115",
+		},
+		{
+			name:     "spec strategy overrides operator default",
+			resolver: Resolver{DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyNamespacedName},
+			spec:     fleetmanagementv1alpha1.PipelineSpec{NamingStrategy: fleetmanagementv1alpha1.NamingStrategyMetadataName},
+			want:     "node-metrics",
+		},
+		{
+			name:     "template",
+			resolver: Resolver{ClusterName: "prod-eu"},
+			spec: fleetmanagementv1alpha1.PipelineSpec{
+				NamingStrategy: fleetmanagementv1alpha1.NamingStrategyTemplate,
+				NameTemplate:   "{{ .Cluster }}-{{ .Labels.team }}-{{ .Name }}",
+			},
+			want: "prod_eu_a_node_metrics_5820fbca",
+		},
+		{
+			name: "default template",
+			resolver: Resolver{
+				DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyTemplate,
+				DefaultTemplate: "{{ .Namespace }}.{{ .Name }}",
+			},
+			want: "team_a_node_metrics_31d4038e",
+		},
+		{
+			name:     "template with missing key",
+			resolver: Resolver{DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyTemplate},
+			spec:     fleetmanagementv1alpha1.PipelineSpec{NameTemplate: "{{ .Labels.missing }}"},
+			wantErr:  true,
+		},
+		{
+			name:     "template without text",
+			resolver: Resolver{DefaultStrategy: fleetmanagementv1alpha1.NamingStrategyTemplate},
+			wantErr:  true,
+		},
+		{
+			name:     "cluster prefix",
+			resolver: Resolver{ClusterName: "prod-eu", Affix: AffixClusterPrefix},
+			want:     "prod_eu_3312b695_node-metrics",
+		},
+		{
+			name: "debug pipeline is made unique",
+			spec: fleetmanagementv1alpha1.PipelineSpec{Debug: &fleetmanagementv1alpha1.DebugTarget{CollectorID: "host-1"}},
+			want: "node_metrics_debug_5f2c8a1e_9a900fca",
+		},
+		{
+			name:     "namespace suffix on explicit name",
+			resolver: Resolver{Affix: AffixNamespaceSuffix},
+			spec:     fleetmanagementv1alpha1.PipelineSpec{Name: "custom"},
+			want:     "custom_team_a_96c2886c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolver.RemoteName(pipeline(tt.spec))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoteName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RemoteName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelcol
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "go.yaml.in/yaml/v3"
+)
+
+func TestFormat(t *testing.T) {
+	contents := `receivers:
+    otlp:
+        protocols: {grpc: {endpoint: "0.0.0.0:4317"}}
+exporters:
+  # Primary backend
+  otlphttp/primary:
+    endpoint: 'https://otlp.example.com'
+    headers:
+      version: "1"
+      X-Scope-OrgID: ${env:TENANT}
+service:
+  pipelines:
+    traces: {receivers: [otlp], exporters: ["otlphttp/primary"]}
+`
+	want := `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  # Primary backend
+  otlphttp/primary:
+    endpoint: https://otlp.example.com
+    headers:
+      version: "1"
+      X-Scope-OrgID: ${env:TENANT}
+service:
+  pipelines:
+    traces:
+      receivers:
+        - otlp
+      exporters:
+        - otlphttp/primary
+`
+	got, err := Format(contents)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+
+	var before, after any
+	if err := yaml.Unmarshal([]byte(contents), &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(got), &after); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("Format() changed the configuration:\n%v\n%v", before, after)
+	}
+
+	again, err := Format(got)
+	if err != nil {
+		t.Fatalf("Format() of formatted contents error = %v", err)
+	}
+	if again != got {
+		t.Errorf("Format() is not idempotent:\n%s", again)
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	if _, err := Format("receivers: [otlp"); err == nil {
+		t.Error("Format() of invalid YAML succeeded")
+	}
+}
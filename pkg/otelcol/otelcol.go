@@ -0,0 +1,88 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otelcol lists the components of OpenTelemetry Collector configuration and formats it.
+package otelcol
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v3"
+)
+
+// Kinds are the top-level sections holding components
+var Kinds = []string{"receivers", "processors", "exporters", "connectors", "extensions"}
+
+// Component is a configured OpenTelemetry Collector component
+type Component struct {
+	// Kind is the section of the component, e.g. "exporters"
+	Kind string
+	// ID is the component ID, e.g. "otlphttp/primary"
+	ID string
+	// Line is the 1-based line number of the component ID
+	Line int
+}
+
+// Type returns the component type, the ID without its name, e.g. "otlphttp"
+func (c Component) Type() string {
+	t, _, _ := strings.Cut(c.ID, "/")
+	return t
+}
+
+// String returns the kind and ID, e.g. "exporters.otlphttp/primary"
+func (c Component) String() string {
+	return c.Kind + "." + c.ID
+}
+
+// Parse returns the components of an OpenTelemetry Collector configuration in
+// the order of Kinds, then of the configuration
+func Parse(contents string) ([]Component, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(contents), &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping", root.Line)
+	}
+
+	var components []Component
+	for _, kind := range Kinds {
+		section := lookup(root, kind)
+		if section == nil || section.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(section.Content); i += 2 {
+			key := section.Content[i]
+			components = append(components, Component{Kind: kind, ID: key.Value, Line: key.Line})
+		}
+	}
+	return components, nil
+}
+
+// lookup returns the value of key in a mapping node
+func lookup(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
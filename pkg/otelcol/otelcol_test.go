@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otelcol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	contents := `receivers:
+  otlp:
+    protocols:
+      grpc: {}
+processors:
+  batch:
+exporters:
+  otlphttp/primary:
+    endpoint: https://otlp.example.com
+  debug:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlphttp/primary]
+`
+	got, err := Parse(contents)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []Component{
+		{Kind: "receivers", ID: "otlp", Line: 2},
+		{Kind: "processors", ID: "batch", Line: 6},
+		{Kind: "exporters", ID: "otlphttp/primary", Line: 8},
+		{Kind: "exporters", ID: "debug", Line: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+	if got[2].Type() != "otlphttp" || got[2].String() != "exporters.otlphttp/primary" {
+		t.Errorf("Type() = %q, String() = %q", got[2].Type(), got[2].String())
+	}
+
+	if _, err := Parse("- a\n- b"); err == nil {
+		t.Error("Parse() accepted a list")
+	}
+	if _, err := Parse("receivers: [\n"); err == nil {
+		t.Error("Parse() accepted invalid YAML")
+	}
+	if got, err := Parse(""); err != nil || got != nil {
+		t.Errorf("Parse(\"\") = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pipelinetemplate renders PipelineTemplate contents for a Pipeline.
+//
+// Parameter values are checked against the template's parameter schema
+// before rendering: unknown parameters, missing required parameters and
+// values of the wrong type are reported with their field path.
+package pipelinetemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// PipelineData exposes the Pipeline being rendered to templates
+type PipelineData struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+}
+
+// Data is passed to template contents
+type Data struct {
+	Params   map[string]any
+	Pipeline PipelineData
+}
+
+// funcs are the functions available to template contents
+var funcs = template.FuncMap{
+	// quote renders a string literal valid in Alloy and YAML
+	"quote": strconv.Quote,
+	"join":  strings.Join,
+}
+
+// Parse parses template contents
+func Parse(contents string) (*template.Template, error) {
+	return template.New("contents").Option("missingkey=error").Funcs(funcs).Parse(contents)
+}
+
+// Validate validates a PipelineTemplate spec: the contents must parse,
+// parameter names must be unique and defaults must match their type
+func Validate(spec *fleetmanagementv1alpha1.PipelineTemplateSpec, specPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if _, err := Parse(spec.Contents); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("contents"), "<template>", err.Error()))
+	}
+
+	seen := make(map[string]bool, len(spec.Parameters))
+	for i, param := range spec.Parameters {
+		path := specPath.Child("parameters").Index(i)
+		if seen[param.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), param.Name))
+		}
+		seen[param.Name] = true
+
+		if param.Default != nil {
+			if _, err := decode(param.Type, param.Default); err != nil {
+				errs = append(errs, field.Invalid(path.Child("default"), string(param.Default.Raw), err.Error()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// Params checks the Pipeline's parameters against the template's schema and
+// returns the values passed to the template, with defaults applied
+func Params(tmpl *fleetmanagementv1alpha1.PipelineTemplateSpec, values map[string]apiextensionsv1.JSON, paramsPath *field.Path) (map[string]any, field.ErrorList) {
+	var errs field.ErrorList
+	params := make(map[string]any, len(tmpl.Parameters))
+
+	declared := make(map[string]bool, len(tmpl.Parameters))
+	for _, param := range tmpl.Parameters {
+		declared[param.Name] = true
+
+		raw, ok := values[param.Name]
+		value := &raw
+		if !ok {
+			value = param.Default
+		}
+		if value == nil {
+			if param.Required {
+				errs = append(errs, field.Required(paramsPath.Key(param.Name), "parameter is required by the template"))
+			}
+			params[param.Name] = zero(param.Type)
+			continue
+		}
+
+		v, err := decode(param.Type, value)
+		if err != nil {
+			errs = append(errs, field.Invalid(paramsPath.Key(param.Name), string(value.Raw), err.Error()))
+			continue
+		}
+		params[param.Name] = v
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if !declared[name] {
+			errs = append(errs, field.NotSupported(paramsPath.Key(name), name, parameterNames(tmpl)))
+		}
+	}
+
+	return params, errs
+}
+
+// Render renders the template's contents for the pipeline
+func Render(tmpl *fleetmanagementv1alpha1.PipelineTemplate, pipeline *fleetmanagementv1alpha1.Pipeline) (string, error) {
+	params, errs := Params(&tmpl.Spec, pipeline.Spec.Parameters, field.NewPath("spec", "parameters"))
+	if len(errs) > 0 {
+		return "", errs.ToAggregate()
+	}
+
+	t, err := Parse(tmpl.Spec.Contents)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", tmpl.Name, err)
+	}
+
+	var b strings.Builder
+	err = t.Execute(&b, Data{
+		Params: params,
+		Pipeline: PipelineData{
+			Name:      pipeline.Name,
+			Namespace: pipeline.Namespace,
+			Labels:    pipeline.Labels,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", tmpl.Name, err)
+	}
+
+	if strings.TrimSpace(b.String()) == "" {
+		return "", fmt.Errorf("template %s rendered empty contents", tmpl.Name)
+	}
+	return b.String(), nil
+}
+
+// decode unmarshals a JSON value and checks it against the parameter type
+func decode(t fleetmanagementv1alpha1.ParameterType, value *apiextensionsv1.JSON) (any, error) {
+	var err error
+	switch t {
+	case "", fleetmanagementv1alpha1.ParameterTypeString:
+		var s string
+		if err = json.Unmarshal(value.Raw, &s); err == nil {
+			return s, nil
+		}
+	case fleetmanagementv1alpha1.ParameterTypeInteger:
+		var i int64
+		if err = json.Unmarshal(value.Raw, &i); err == nil {
+			return i, nil
+		}
+	case fleetmanagementv1alpha1.ParameterTypeBoolean:
+		var b bool
+		if err = json.Unmarshal(value.Raw, &b); err == nil {
+			return b, nil
+		}
+	case fleetmanagementv1alpha1.ParameterTypeStringList:
+		var l []string
+		if err = json.Unmarshal(value.Raw, &l); err == nil {
+			return l, nil
+		}
+	case fleetmanagementv1alpha1.ParameterTypeStringMap:
+		var m map[string]string
+		if err = json.Unmarshal(value.Raw, &m); err == nil {
+			return m, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown parameter type %q", t)
+	}
+	return nil, fmt.Errorf("value must be of type %s", typeName(t))
+}
+
+// zero returns the value of an unset optional parameter
+func zero(t fleetmanagementv1alpha1.ParameterType) any {
+	switch t {
+	case fleetmanagementv1alpha1.ParameterTypeInteger:
+		return int64(0)
+	case fleetmanagementv1alpha1.ParameterTypeBoolean:
+		return false
+	case fleetmanagementv1alpha1.ParameterTypeStringList:
+		return []string{}
+	case fleetmanagementv1alpha1.ParameterTypeStringMap:
+		return map[string]string{}
+	default:
+		return ""
+	}
+}
+
+func typeName(t fleetmanagementv1alpha1.ParameterType) string {
+	if t == "" {
+		return string(fleetmanagementv1alpha1.ParameterTypeString)
+	}
+	return string(t)
+}
+
+func parameterNames(tmpl *fleetmanagementv1alpha1.PipelineTemplateSpec) []string {
+	names := make([]string, 0, len(tmpl.Parameters))
+	for _, param := range tmpl.Parameters {
+		names = append(names, param.Name)
+	}
+	return names
+}
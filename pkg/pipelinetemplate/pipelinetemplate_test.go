@@ -0,0 +1,153 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinetemplate
+
+import (
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func raw(s string) *apiextensionsv1.JSON {
+	return &apiextensionsv1.JSON{Raw: []byte(s)}
+}
+
+func scrapeTemplate() *fleetmanagementv1alpha1.PipelineTemplate {
+	return &fleetmanagementv1alpha1.PipelineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "scrape", Namespace: "team-a"},
+		Spec: fleetmanagementv1alpha1.PipelineTemplateSpec{
+			Contents: `prometheus.remote_write "{{ .Pipeline.Name }}" {
+  endpoint { url = {{ quote .Params.url }} }
+  external_labels = { {{ range $k, $v := .Params.labels }}{{ quote $k }} = {{ quote $v }}, {{ end }}}
+}
+// interval {{ .Params.interval }}s, debug {{ .Params.debug }}
+// targets {{ join .Params.targets "," }}`,
+			Parameters: []fleetmanagementv1alpha1.TemplateParameter{
+				{Name: "url", Type: fleetmanagementv1alpha1.ParameterTypeString, Required: true},
+				{Name: "interval", Type: fleetmanagementv1alpha1.ParameterTypeInteger, Default: raw("60")},
+				{Name: "debug", Type: fleetmanagementv1alpha1.ParameterTypeBoolean},
+				{Name: "targets", Type: fleetmanagementv1alpha1.ParameterTypeStringList},
+				{Name: "labels", Type: fleetmanagementv1alpha1.ParameterTypeStringMap},
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	pipeline := &fleetmanagementv1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "team-a"},
+		Spec: fleetmanagementv1alpha1.PipelineSpec{
+			Parameters: map[string]apiextensionsv1.JSON{
+				"url":     *raw(`"https://prom.example.com/push"`),
+				"targets": *raw(`["a:9090","b:9090"]`),
+				"labels":  *raw(`{"team":"a"}`),
+			},
+		},
+	}
+
+	got, err := Render(scrapeTemplate(), pipeline)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		`prometheus.remote_write "metrics"`,
+		`url = "https://prom.example.com/push"`,
+		`"team" = "a"`,
+		`interval 60s, debug false`,
+		`targets a:9090,b:9090`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]apiextensionsv1.JSON
+		wantErr string
+	}{
+		{
+			name:    "missing required parameter",
+			values:  map[string]apiextensionsv1.JSON{},
+			wantErr: "spec.parameters[url]: Required value",
+		},
+		{
+			name: "wrong type",
+			values: map[string]apiextensionsv1.JSON{
+				"url":      *raw(`"x"`),
+				"interval": *raw(`"60"`),
+			},
+			wantErr: "spec.parameters[interval]: Invalid value",
+		},
+		{
+			name: "unknown parameter",
+			values: map[string]apiextensionsv1.JSON{
+				"url":   *raw(`"x"`),
+				"extra": *raw(`1`),
+			},
+			wantErr: "spec.parameters[extra]: Unsupported value",
+		},
+		{
+			name:   "valid",
+			values: map[string]apiextensionsv1.JSON{"url": *raw(`"x"`), "debug": *raw(`true`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := Params(&scrapeTemplate().Spec, tt.values, field.NewPath("spec", "parameters"))
+			err := errs.ToAggregate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Params() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Params() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	spec := scrapeTemplate().Spec
+	if errs := Validate(&spec, field.NewPath("spec")); len(errs) > 0 {
+		t.Fatalf("Validate() = %v, want no errors", errs)
+	}
+
+	spec.Contents = "{{ .Params.url "
+	spec.Parameters = append(spec.Parameters,
+		fleetmanagementv1alpha1.TemplateParameter{Name: "url"},
+		fleetmanagementv1alpha1.TemplateParameter{Name: "count", Type: fleetmanagementv1alpha1.ParameterTypeInteger, Default: raw(`"ten"`)},
+	)
+	errs := Validate(&spec, field.NewPath("spec"))
+	if len(errs) != 3 {
+		t.Fatalf("Validate() = %v, want 3 errors", errs)
+	}
+	for i, want := range []string{"spec.contents", "spec.parameters[5].name", "spec.parameters[6].default"} {
+		if errs[i].Field != want {
+			t.Errorf("error %d field = %q, want %q", i, errs[i].Field, want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/alloy"
+	"github.com/grafana/fleet-management-operator/pkg/otelcol"
+)
+
+// alloyStructuralBlocks are the blocks of Alloy modules that are not components
+var alloyStructuralBlocks = []string{"declare", "argument", "export"}
+
+// otelKinds maps the OpenTelemetry Collector sections to the component kinds
+// that policies restrict
+var otelKinds = map[string]string{
+	"receivers":  "receiver",
+	"processors": "processor",
+	"exporters":  "exporter",
+}
+
+// component is a component found in contents
+type component struct {
+	// kind is the component kind in violations, e.g. "component" or "receiver"
+	kind string
+	// name is checked against the lists
+	name string
+	// desc names the block in violations, e.g. local.file "secrets"
+	desc string
+	line int
+}
+
+// hasComponentLists reports whether the policy restricts the components of
+// the config type
+func hasComponentLists(spec *fleetmanagementv1alpha1.PipelinePolicySpec, configType fleetmanagementv1alpha1.ConfigType) bool {
+	if configType == fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector {
+		return spec.OTelReceivers != nil || spec.OTelProcessors != nil || spec.OTelExporters != nil
+	}
+	return spec.AlloyComponents != nil
+}
+
+// CheckContents returns the components of contents denied by the policies
+// selecting the pipeline. contents are the spec.contents of the pipeline or
+// the contents rendered from its template, fragments and modules.
+func CheckContents(policies []Policy, pipeline *fleetmanagementv1alpha1.Pipeline, contents string) field.ErrorList {
+	var allErrs field.ErrorList
+	contentsPath := field.NewPath("spec", "contents")
+
+	var components []component
+	var parsed bool
+	for _, p := range policies {
+		if !hasComponentLists(p.Spec, pipeline.Spec.ConfigType) {
+			continue
+		}
+		if selected, err := selects(p.Spec, pipeline); err != nil || !selected {
+			// Invalid selectors are reported by Check
+			continue
+		}
+
+		if !parsed {
+			var err error
+			components, err = parseComponents(pipeline.Spec.ConfigType, contents)
+			if err != nil {
+				return field.ErrorList{field.Forbidden(contentsPath,
+					fmt.Sprintf("components cannot be checked against %s: %v", p.Name, err))}
+			}
+			parsed = true
+		}
+
+		for _, c := range components {
+			lists := componentLists(p.Spec, pipeline.Spec.ConfigType, c.kind)
+			if lists == nil {
+				continue
+			}
+			if reason := checkLists(lists, c.name); reason != "" {
+				allErrs = append(allErrs, field.Forbidden(contentsPath,
+					fmt.Sprintf("%s %s %s %s at line %d", p.Name, reason, c.kind, c.desc, c.line)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// componentLists returns the lists of the policy for a kind of component
+func componentLists(spec *fleetmanagementv1alpha1.PipelinePolicySpec, configType fleetmanagementv1alpha1.ConfigType, kind string) *fleetmanagementv1alpha1.ComponentLists {
+	if configType != fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector {
+		return spec.AlloyComponents
+	}
+	switch kind {
+	case "receiver":
+		return spec.OTelReceivers
+	case "processor":
+		return spec.OTelProcessors
+	case "exporter":
+		return spec.OTelExporters
+	}
+	return nil
+}
+
+// checkLists returns why the lists reject name, or an empty string
+func checkLists(lists *fleetmanagementv1alpha1.ComponentLists, name string) string {
+	if len(lists.Allowed) > 0 && !matchesAny(lists.Allowed, name) {
+		return "does not allow"
+	}
+	if matchesAny(lists.Denied, name) {
+		return "denies"
+	}
+	return ""
+}
+
+func matchesAny(patterns []string, name string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// parseComponents lists the components of Alloy or OpenTelemetry Collector contents
+func parseComponents(configType fleetmanagementv1alpha1.ConfigType, contents string) ([]component, error) {
+	if configType == fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector {
+		parsed, err := otelcol.Parse(contents)
+		if err != nil {
+			return nil, err
+		}
+		components := make([]component, 0, len(parsed))
+		for _, c := range parsed {
+			if kind, ok := otelKinds[c.Kind]; ok {
+				components = append(components, component{kind: kind, name: c.Type(), desc: c.ID, line: c.Line})
+			}
+		}
+		return components, nil
+	}
+
+	blocks, err := alloy.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+	var components []component
+	var walk func(blocks []alloy.Block)
+	walk = func(blocks []alloy.Block) {
+		for _, b := range blocks {
+			if b.Name == "declare" {
+				walk(b.Blocks)
+			}
+			if slices.Contains(alloyStructuralBlocks, b.Name) {
+				continue
+			}
+			desc := b.Name
+			if b.Label != "" {
+				desc += fmt.Sprintf(" %q", b.Label)
+			}
+			components = append(components, component{kind: "component", name: b.Name, desc: desc, line: b.Line})
+		}
+	}
+	walk(blocks)
+	return components, nil
+}
+
+// validateComponentLists checks the patterns of component lists
+func validateComponentLists(lists *fleetmanagementv1alpha1.ComponentLists, listsPath *field.Path) field.ErrorList {
+	if lists == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	check := func(name string, patterns []string) {
+		for i, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				allErrs = append(allErrs, field.Invalid(listsPath.Child(name).Index(i), pattern, "must be a valid glob pattern"))
+			}
+		}
+	}
+	check("allowed", lists.Allowed)
+	check("denied", lists.Denied)
+	return allErrs
+}
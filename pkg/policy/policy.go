@@ -0,0 +1,180 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy checks Pipelines against PipelinePolicies and
+// ClusterPipelinePolicies restricting their matchers, config type, source and
+// the components of their contents.
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+)
+
+// NamespacePlaceholder is replaced by the Pipeline namespace in required matchers
+const NamespacePlaceholder = "${namespace}"
+
+// Policy is a PipelinePolicy or ClusterPipelinePolicy
+type Policy struct {
+	// Name identifies the policy in violations, e.g. "PipelinePolicy team-a/tenancy"
+	Name string
+	Spec *fleetmanagementv1alpha1.PipelinePolicySpec
+}
+
+// FromPolicies gathers the PipelinePolicies and ClusterPipelinePolicies in
+// a single list, sorted by name within each kind
+func FromPolicies(namespaced []fleetmanagementv1alpha1.PipelinePolicy, cluster []fleetmanagementv1alpha1.ClusterPipelinePolicy) []Policy {
+	policies := make([]Policy, 0, len(namespaced)+len(cluster))
+	for i := range cluster {
+		p := &cluster[i]
+		policies = append(policies, Policy{Name: "ClusterPipelinePolicy " + p.Name, Spec: &p.Spec})
+	}
+	for i := range namespaced {
+		p := &namespaced[i]
+		policies = append(policies, Policy{Name: "PipelinePolicy " + p.Namespace + "/" + p.Name, Spec: &p.Spec})
+	}
+	slices.SortStableFunc(policies, func(a, b Policy) int { return strings.Compare(a.Name, b.Name) })
+	return policies
+}
+
+// Validate checks a policy spec
+func Validate(spec *fleetmanagementv1alpha1.PipelinePolicySpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.PipelineSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.PipelineSelector,
+			metav1validation.LabelSelectorValidationOptions{}, path.Child("pipelineSelector"))...)
+	}
+	for i, s := range spec.RequiredMatchers {
+		if _, err := matchers.Parse(expand(s, "namespace")); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("requiredMatchers").Index(i), s, err.Error()))
+		}
+	}
+	for i, key := range spec.ForbiddenMatcherKeys {
+		if strings.TrimSpace(key) == "" {
+			allErrs = append(allErrs, field.Required(path.Child("forbiddenMatcherKeys").Index(i), "must not be empty"))
+		}
+	}
+	allErrs = append(allErrs, validateComponentLists(spec.AlloyComponents, path.Child("alloyComponents"))...)
+	allErrs = append(allErrs, validateComponentLists(spec.OTelReceivers, path.Child("otelReceivers"))...)
+	allErrs = append(allErrs, validateComponentLists(spec.OTelProcessors, path.Child("otelProcessors"))...)
+	allErrs = append(allErrs, validateComponentLists(spec.OTelExporters, path.Child("otelExporters"))...)
+	return allErrs
+}
+
+// Check returns the violations of the pipeline against the policies selecting it
+func Check(policies []Policy, pipeline *fleetmanagementv1alpha1.Pipeline) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, p := range policies {
+		selected, err := selects(p.Spec, pipeline)
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(field.NewPath("spec"), fmt.Errorf("%s: %w", p.Name, err)))
+			continue
+		}
+		if selected {
+			allErrs = append(allErrs, check(p, pipeline)...)
+		}
+	}
+	return allErrs
+}
+
+// selects reports whether the policy applies to the pipeline
+func selects(spec *fleetmanagementv1alpha1.PipelinePolicySpec, pipeline *fleetmanagementv1alpha1.Pipeline) (bool, error) {
+	if spec.PipelineSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(spec.PipelineSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid pipelineSelector: %w", err)
+	}
+	return selector.Matches(labels.Set(pipeline.Labels)), nil
+}
+
+// check returns the violations of the pipeline against one policy
+func check(p Policy, pipeline *fleetmanagementv1alpha1.Pipeline) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	spec := &pipeline.Spec
+
+	// Invalid matchers are reported by matcher validation
+	ms, _ := matchers.FromSpec(spec, specPath)
+	present := make(map[string]bool, len(ms))
+	for _, m := range ms {
+		present[m.String()] = true
+	}
+
+	required := make(map[string]bool, len(p.Spec.RequiredMatchers))
+	for _, s := range p.Spec.RequiredMatchers {
+		m, err := matchers.Parse(expand(s, pipeline.Namespace))
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(specPath.Child("matchers"),
+				fmt.Errorf("%s: invalid required matcher %q: %w", p.Name, s, err)))
+			continue
+		}
+		required[m.String()] = true
+		if !present[m.String()] {
+			allErrs = append(allErrs, field.Required(specPath.Child("matchers"),
+				fmt.Sprintf("%s requires the matcher %s", p.Name, m)))
+		}
+	}
+
+	for _, m := range ms {
+		if slices.Contains(p.Spec.ForbiddenMatcherKeys, m.Name) && !required[m.String()] {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("matchers"),
+				fmt.Sprintf("%s forbids matching on %s, found %s", p.Name, m.Name, m)))
+		}
+	}
+
+	if limit := p.Spec.MaxMatchers; limit != nil {
+		if count := len(spec.Matchers) + len(spec.StructuredMatchers); count > int(*limit) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("matchers"),
+				fmt.Sprintf("%s allows at most %d matchers, found %d", p.Name, *limit, count)))
+		}
+	}
+
+	configType := spec.ConfigType
+	if configType == "" {
+		configType = fleetmanagementv1alpha1.ConfigTypeAlloy
+	}
+	if len(p.Spec.AllowedConfigTypes) > 0 && !slices.Contains(p.Spec.AllowedConfigTypes, configType) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("configType"),
+			fmt.Sprintf("%s allows config types %v, not %s", p.Name, p.Spec.AllowedConfigTypes, configType)))
+	}
+
+	sourceType := fleetmanagementv1alpha1.SourceTypeKubernetes
+	if spec.Source != nil && spec.Source.Type != "" {
+		sourceType = spec.Source.Type
+	}
+	if len(p.Spec.AllowedSourceTypes) > 0 && !slices.Contains(p.Spec.AllowedSourceTypes, sourceType) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("source", "type"),
+			fmt.Sprintf("%s allows source types %v, not %s", p.Name, p.Spec.AllowedSourceTypes, sourceType)))
+	}
+
+	return allErrs
+}
+
+// expand replaces the namespace placeholder in a required matcher
+func expand(s, namespace string) string {
+	return strings.ReplaceAll(s, NamespacePlaceholder, namespace)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    fleetmanagementv1alpha1.PipelinePolicySpec
+		wantErr bool
+	}{
+		{"empty", fleetmanagementv1alpha1.PipelinePolicySpec{}, false},
+		{"placeholder", fleetmanagementv1alpha1.PipelinePolicySpec{RequiredMatchers: []string{"team=${namespace}"}}, false},
+		{"invalid matcher", fleetmanagementv1alpha1.PipelinePolicySpec{RequiredMatchers: []string{"team"}}, true},
+		{"empty forbidden key", fleetmanagementv1alpha1.PipelinePolicySpec{ForbiddenMatcherKeys: []string{" "}}, true},
+		{"invalid component pattern", fleetmanagementv1alpha1.PipelinePolicySpec{
+			AlloyComponents: &fleetmanagementv1alpha1.ComponentLists{Denied: []string{"local.["}},
+		}, true},
+		{"invalid selector", fleetmanagementv1alpha1.PipelinePolicySpec{PipelineSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Bogus"}},
+		}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(&tt.spec, field.NewPath("spec"))
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	maxMatchers := int32(2)
+	tenancy := Policy{Name: "PipelinePolicy team-a/tenancy", Spec: &fleetmanagementv1alpha1.PipelinePolicySpec{
+		RequiredMatchers:     []string{"team=${namespace}"},
+		ForbiddenMatcherKeys: []string{"team", "collector.ID"},
+		AllowedConfigTypes:   []fleetmanagementv1alpha1.ConfigType{fleetmanagementv1alpha1.ConfigTypeAlloy},
+		MaxMatchers:          &maxMatchers,
+	}}
+	sources := Policy{Name: "ClusterPipelinePolicy sources", Spec: &fleetmanagementv1alpha1.PipelinePolicySpec{
+		PipelineSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+		AllowedSourceTypes: []fleetmanagementv1alpha1.SourceType{fleetmanagementv1alpha1.SourceTypeGit},
+	}}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		spec   fleetmanagementv1alpha1.PipelineSpec
+		want   []string
+	}{
+		{
+			name: "compliant",
+			spec: fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"team=team-a", "env=prod"}},
+		},
+		{
+			name: "missing required matcher",
+			spec: fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"env=prod"}},
+			want: []string{"requires the matcher team=team-a"},
+		},
+		{
+			name: "forbidden key",
+			spec: fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"team=team-a", "team=team-b"}},
+			want: []string{"forbids matching on team, found team=team-b"},
+		},
+		{
+			name: "too many matchers",
+			spec: fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"team=team-a", "env=prod", "region=eu"}},
+			want: []string{"allows at most 2 matchers, found 3"},
+		},
+		{
+			name: "config type",
+			spec: fleetmanagementv1alpha1.PipelineSpec{
+				Matchers:   []string{"team=team-a"},
+				ConfigType: fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector,
+			},
+			want: []string{"allows config types [Alloy]"},
+		},
+		{
+			name:   "source type of selected pipeline",
+			labels: map[string]string{"tier": "critical"},
+			spec:   fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"team=team-a"}},
+			want:   []string{"ClusterPipelinePolicy sources allows source types [Git], not Kubernetes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "team-a", Labels: tt.labels},
+				Spec:       tt.spec,
+			}
+			errs := Check([]Policy{sources, tenancy}, pipeline)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Check() = %v, want %d violations", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, errs[i].Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestFromPolicies(t *testing.T) {
+	policies := FromPolicies(
+		[]fleetmanagementv1alpha1.PipelinePolicy{{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns"}}},
+		[]fleetmanagementv1alpha1.ClusterPipelinePolicy{{ObjectMeta: metav1.ObjectMeta{Name: "a"}}},
+	)
+	if len(policies) != 2 || policies[0].Name != "ClusterPipelinePolicy a" || policies[1].Name != "PipelinePolicy ns/b" {
+		t.Errorf("FromPolicies() = %v", policies)
+	}
+}
+
+func TestCheckContents(t *testing.T) {
+	tenancy := Policy{Name: "PipelinePolicy team-a/components", Spec: &fleetmanagementv1alpha1.PipelinePolicySpec{
+		AlloyComponents: &fleetmanagementv1alpha1.ComponentLists{Denied: []string{"local.file", "remote.*"}},
+		OTelReceivers:   &fleetmanagementv1alpha1.ComponentLists{Allowed: []string{"otlp"}},
+		OTelExporters:   &fleetmanagementv1alpha1.ComponentLists{Denied: []string{"debug"}},
+	}}
+	restricted := Policy{Name: "ClusterPipelinePolicy allow-list", Spec: &fleetmanagementv1alpha1.PipelinePolicySpec{
+		PipelineSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "restricted"}},
+		AlloyComponents:  &fleetmanagementv1alpha1.ComponentLists{Allowed: []string{"prometheus.*", "logging"}},
+	}}
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		configType fleetmanagementv1alpha1.ConfigType
+		contents   string
+		want       []string
+	}{
+		{
+			name:     "allowed alloy components",
+			contents: "logging {\n  level = \"info\"\n}\nprometheus.exporter.self \"alloy\" { }\n",
+		},
+		{
+			name:     "denied alloy components",
+			contents: "local.file \"token\" {\n  filename = \"/token\"\n}\n\nremote.http \"config\" {\n  url = \"https://example.com\"\n}\n",
+			want: []string{
+				`PipelinePolicy team-a/components denies component local.file "token" at line 1`,
+				`PipelinePolicy team-a/components denies component remote.http "config" at line 5`,
+			},
+		},
+		{
+			name:     "component in a declare block",
+			contents: "declare \"reader\" {\n  argument \"path\" { }\n\n  local.file \"f\" {\n    filename = argument.path.value\n  }\n}\n",
+			want:     []string{`denies component local.file "f" at line 4`},
+		},
+		{
+			name:     "allow list of a selected pipeline",
+			labels:   map[string]string{"tier": "restricted"},
+			contents: "prometheus.scrape \"pods\" { }\ndiscovery.kubernetes \"pods\" {\n  role = \"pod\"\n}\n",
+			want:     []string{`ClusterPipelinePolicy allow-list does not allow component discovery.kubernetes "pods" at line 2`},
+		},
+		{
+			name:       "otel components",
+			configType: fleetmanagementv1alpha1.ConfigTypeOpenTelemetryCollector,
+			contents:   "receivers:\n  otlp: {}\n  filelog/app: {}\nprocessors:\n  batch: {}\nexporters:\n  debug: {}\n",
+			want: []string{
+				"does not allow receiver filelog/app at line 3",
+				"denies exporter debug at line 7",
+			},
+		},
+		{
+			name:     "unparsable contents",
+			contents: "local.file \"token\" {\n",
+			want:     []string{"components cannot be checked"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := &fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "team-a", Labels: tt.labels},
+				Spec:       fleetmanagementv1alpha1.PipelineSpec{ConfigType: tt.configType},
+			}
+			errs := CheckContents([]Policy{restricted, tenancy}, pipeline, tt.contents)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("CheckContents() = %v, want %d violations", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, errs[i].Error(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota computes the Pipeline usage of a namespace and checks it
+// against PipelineQuotas.
+package quota
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// Usage returns the usage of the pipelines
+func Usage(pipelines []fleetmanagementv1alpha1.Pipeline) fleetmanagementv1alpha1.PipelineQuotaUsage {
+	var usage fleetmanagementv1alpha1.PipelineQuotaUsage
+	var bytes int64
+	for i := range pipelines {
+		p := &pipelines[i]
+		usage.Pipelines++
+		if p.Spec.Enabled {
+			usage.EnabledPipelines++
+		}
+		bytes += ContentsBytes(p)
+	}
+	usage.ContentsBytes = *resource.NewQuantity(bytes, resource.BinarySI)
+	return usage
+}
+
+// ContentsBytes returns the size counted for the contents of a pipeline: the
+// larger of spec.contents and the contents last rendered for it, as recorded
+// in status. Contents rendered from templates, fragments and modules are only
+// known once the pipeline has been reconciled.
+func ContentsBytes(pipeline *fleetmanagementv1alpha1.Pipeline) int64 {
+	return max(int64(len(pipeline.Spec.Contents)), pipeline.Status.ContentsBytes)
+}
+
+// Replace returns the usage of the pipelines, with the pipeline named name
+// replaced by pipeline. A nil pipeline removes it.
+func Replace(pipelines []fleetmanagementv1alpha1.Pipeline, name string, pipeline *fleetmanagementv1alpha1.Pipeline) fleetmanagementv1alpha1.PipelineQuotaUsage {
+	replaced := make([]fleetmanagementv1alpha1.Pipeline, 0, len(pipelines)+1)
+	for i := range pipelines {
+		if pipelines[i].Name != name {
+			replaced = append(replaced, pipelines[i])
+		}
+	}
+	if pipeline != nil {
+		replaced = append(replaced, *pipeline)
+	}
+	return Usage(replaced)
+}
+
+// Check returns the limits of the quota exceeded by a change from the before
+// to the after usage. Usage already over a limit may stay or decrease, so
+// Pipelines can still be updated after a limit is lowered.
+func Check(quota *fleetmanagementv1alpha1.PipelineQuota, before, after fleetmanagementv1alpha1.PipelineQuotaUsage) field.ErrorList {
+	var allErrs field.ErrorList
+	hard := &quota.Spec.Hard
+	exceeded := func(resourceName, used, limit string) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata", "namespace"),
+			fmt.Sprintf("exceeded PipelineQuota %q: %s would be %s, limited to %s", quota.Name, resourceName, used, limit)))
+	}
+
+	if hard.Pipelines != nil && after.Pipelines > *hard.Pipelines && after.Pipelines > before.Pipelines {
+		exceeded("pipelines", fmt.Sprint(after.Pipelines), fmt.Sprint(*hard.Pipelines))
+	}
+	if hard.EnabledPipelines != nil && after.EnabledPipelines > *hard.EnabledPipelines &&
+		after.EnabledPipelines > before.EnabledPipelines {
+		exceeded("enabledPipelines", fmt.Sprint(after.EnabledPipelines), fmt.Sprint(*hard.EnabledPipelines))
+	}
+	if hard.ContentsBytes != nil && after.ContentsBytes.Cmp(*hard.ContentsBytes) > 0 &&
+		after.ContentsBytes.Cmp(before.ContentsBytes) > 0 {
+		exceeded("contentsBytes", after.ContentsBytes.String(), hard.ContentsBytes.String())
+	}
+	return allErrs
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func pipeline(name string, enabled bool, contents string) fleetmanagementv1alpha1.Pipeline {
+	return fleetmanagementv1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       fleetmanagementv1alpha1.PipelineSpec{Enabled: enabled, Contents: contents},
+	}
+}
+
+func TestUsage(t *testing.T) {
+	usage := Usage([]fleetmanagementv1alpha1.Pipeline{
+		pipeline("a", true, "1234"),
+		pipeline("b", false, "12"),
+	})
+	if usage.Pipelines != 2 || usage.EnabledPipelines != 1 || usage.ContentsBytes.Value() != 6 {
+		t.Errorf("Usage() = %+v", usage)
+	}
+
+	existing := []fleetmanagementv1alpha1.Pipeline{pipeline("a", true, "1234")}
+	updated := pipeline("a", false, "1")
+	if usage := Replace(existing, "a", &updated); usage.Pipelines != 1 || usage.EnabledPipelines != 0 || usage.ContentsBytes.Value() != 1 {
+		t.Errorf("Replace() = %+v", usage)
+	}
+	if usage := Replace(existing, "a", nil); usage.Pipelines != 0 {
+		t.Errorf("Replace(nil) = %+v", usage)
+	}
+}
+
+func TestUsageCountsRenderedContents(t *testing.T) {
+	templated := pipeline("a", true, "")
+	templated.Status.ContentsBytes = 100
+	if usage := Usage([]fleetmanagementv1alpha1.Pipeline{templated, pipeline("b", true, "12")}); usage.ContentsBytes.Value() != 102 {
+		t.Errorf("Usage() counted %s bytes, want 102", usage.ContentsBytes.String())
+	}
+
+	grown := pipeline("a", true, "12345")
+	grown.Status.ContentsBytes = 2
+	if bytes := ContentsBytes(&grown); bytes != 5 {
+		t.Errorf("ContentsBytes() = %d, want the 5 bytes of spec.contents not yet rendered", bytes)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	pipelines, enabled := int64(2), int64(1)
+	bytes := resource.MustParse("10")
+	quota := &fleetmanagementv1alpha1.PipelineQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: fleetmanagementv1alpha1.PipelineQuotaSpec{Hard: fleetmanagementv1alpha1.PipelineQuotaLimits{
+			Pipelines: &pipelines, EnabledPipelines: &enabled, ContentsBytes: &bytes,
+		}},
+	}
+
+	existing := []fleetmanagementv1alpha1.Pipeline{pipeline("a", true, "12345"), pipeline("b", false, "")}
+	overLimit := append(existing, pipeline("c", true, ""))
+	tests := []struct {
+		name     string
+		existing []fleetmanagementv1alpha1.Pipeline
+		pipeline fleetmanagementv1alpha1.Pipeline
+		want     []string
+	}{
+		{"update within limits", existing, pipeline("b", false, "12345"), nil},
+		{"too many pipelines", existing, pipeline("c", false, ""), []string{"pipelines would be 3, limited to 2"}},
+		{"too many enabled pipelines", existing, pipeline("b", true, ""), []string{"enabledPipelines would be 2, limited to 1"}},
+		{"contents too large", existing, pipeline("b", false, "123456"), []string{"contentsBytes would be 11, limited to 10"}},
+		{"shrinking while over limits", overLimit, pipeline("a", true, "1"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Check(quota, Usage(tt.existing), Replace(tt.existing, tt.pipeline.Name, &tt.pipeline))
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Check() = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i].Error(), want)
+				}
+			}
+		})
+	}
+}
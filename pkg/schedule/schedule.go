@@ -0,0 +1,182 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule computes whether a Pipeline is enabled at a given time
+// from spec.enabled, spec.schedule and spec.expiresAt, and when that changes.
+package schedule
+
+import (
+	"fmt"
+	"time"
+	// Time zones must resolve in minimal container images
+	_ "time/tzdata"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+// MaxWindowDuration is the longest allowed schedule window
+const MaxWindowDuration = 31 * 24 * time.Hour
+
+// Reasons explaining a State
+const (
+	ReasonDisabled      = "Disabled"
+	ReasonUnscheduled   = "Unscheduled"
+	ReasonInWindow      = "InWindow"
+	ReasonOutsideWindow = "OutsideWindow"
+	ReasonExpired       = "Expired"
+)
+
+// State is the effective enablement of a pipeline at a point in time
+type State struct {
+	// Enabled is the value sent to Fleet Management
+	Enabled bool
+	// Reason is one of the Reason constants
+	Reason string
+	// Next is when Enabled changes next, or the zero time if it never does
+	Next time.Time
+}
+
+type window struct {
+	cron     *Cron
+	duration time.Duration
+}
+
+// Validate checks the schedule of a Pipeline spec
+func Validate(spec *fleetmanagementv1alpha1.PipelineSpec, path *field.Path) field.ErrorList {
+	if spec.Schedule == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	schedulePath := path.Child("schedule")
+	if _, err := time.LoadLocation(spec.Schedule.TimeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(schedulePath.Child("timeZone"), spec.Schedule.TimeZone, err.Error()))
+	}
+	for i, w := range spec.Schedule.Windows {
+		windowPath := schedulePath.Child("windows").Index(i)
+		if _, err := ParseCron(w.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("start"), w.Start, err.Error()))
+		}
+		if w.Duration.Duration <= 0 || w.Duration.Duration > MaxWindowDuration {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("duration"), w.Duration.String(),
+				fmt.Sprintf("must be positive and at most %s", MaxWindowDuration)))
+		}
+	}
+	return allErrs
+}
+
+// Evaluate returns the effective enablement of a pipeline at now
+func Evaluate(spec *fleetmanagementv1alpha1.PipelineSpec, now time.Time) (State, error) {
+	if !spec.Enabled {
+		return State{Enabled: false, Reason: ReasonDisabled}, nil
+	}
+
+	var expiresAt time.Time
+	if spec.ExpiresAt != nil {
+		expiresAt = spec.ExpiresAt.Time
+		if !now.Before(expiresAt) {
+			return State{Enabled: false, Reason: ReasonExpired}, nil
+		}
+	}
+
+	if spec.Schedule == nil {
+		return State{Enabled: true, Reason: ReasonUnscheduled, Next: expiresAt}, nil
+	}
+
+	windows, loc, err := parse(spec.Schedule)
+	if err != nil {
+		return State{}, err
+	}
+	now = now.In(loc)
+
+	end := activeUntil(windows, now)
+	if end.IsZero() {
+		start := nextStart(windows, now)
+		if !expiresAt.IsZero() && !start.Before(expiresAt) {
+			start = time.Time{}
+		}
+		return State{Enabled: false, Reason: ReasonOutsideWindow, Next: start}, nil
+	}
+
+	if !expiresAt.IsZero() && expiresAt.Before(end) {
+		end = expiresAt
+	}
+	return State{Enabled: true, Reason: ReasonInWindow, Next: end}, nil
+}
+
+func parse(s *fleetmanagementv1alpha1.PipelineSchedule) ([]window, *time.Location, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule time zone: %w", err)
+	}
+	windows := make([]window, 0, len(s.Windows))
+	for _, w := range s.Windows {
+		c, err := ParseCron(w.Start)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid schedule window: %w", err)
+		}
+		windows = append(windows, window{cron: c, duration: w.Duration.Duration})
+	}
+	return windows, loc, nil
+}
+
+// activeUntil returns when the windows active at now end, following windows
+// that start before the previous one ends, or the zero time when no window is active
+func activeUntil(windows []window, now time.Time) time.Time {
+	var end time.Time
+	for _, w := range windows {
+		if w.duration <= 0 {
+			continue
+		}
+		for t := w.cron.Next(now.Add(-w.duration)); !t.IsZero() && !t.After(now); t = w.cron.Next(t) {
+			if e := t.Add(w.duration); e.After(now) && e.After(end) {
+				end = e
+			}
+		}
+	}
+	if end.IsZero() {
+		return end
+	}
+
+	// Overlapping windows keep the pipeline enabled. Windows that do not close
+	// within the search horizon are re-evaluated at its end.
+	horizon := now.Add(2 * MaxWindowDuration)
+	for extended := true; extended && end.Before(horizon); {
+		extended = false
+		for _, w := range windows {
+			for t := w.cron.Next(now); !t.IsZero() && !t.After(end) && end.Before(horizon); t = w.cron.Next(t) {
+				if e := t.Add(w.duration); e.After(end) {
+					end = e
+					extended = true
+				}
+			}
+		}
+	}
+	return end
+}
+
+// nextStart returns the first window start after now, or the zero time
+func nextStart(windows []window, now time.Time) time.Time {
+	var next time.Time
+	for _, w := range windows {
+		if t := w.cron.Next(now); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"0 9 * * MON-FRI", "2026-10-16T10:00:00Z", "2026-10-19T09:00:00Z"},
+		{"*/15 * * * *", "2026-10-16T10:07:30Z", "2026-10-16T10:15:00Z"},
+		{"30 2 1 jan,jul *", "2026-10-16T10:00:00Z", "2027-01-01T02:30:00Z"},
+		{"0 0 13 * 5", "2026-10-16T10:00:00Z", "2026-10-23T00:00:00Z"},
+		{"0 12 * * 7", "2026-10-16T10:00:00Z", "2026-10-18T12:00:00Z"},
+		{"0 0 30 2 *", "2026-10-16T10:00:00Z", "0001-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := c.Next(mustTime(t, tt.from)); !got.Equal(mustTime(t, tt.want)) {
+				t.Errorf("Next() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "0 9 * * FUNDAY"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func businessHours(tz string) *fleetmanagementv1alpha1.PipelineSpec {
+	return &fleetmanagementv1alpha1.PipelineSpec{
+		Enabled: true,
+		Schedule: &fleetmanagementv1alpha1.PipelineSchedule{
+			TimeZone: tz,
+			Windows: []fleetmanagementv1alpha1.ScheduleWindow{
+				{Start: "0 9 * * MON-FRI", Duration: metav1.Duration{Duration: 8 * time.Hour}},
+			},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	expired := businessHours("")
+	expired.ExpiresAt = &metav1.Time{Time: mustTime(t, "2026-10-16T12:00:00Z")}
+
+	overlapping := businessHours("")
+	overlapping.Schedule.Windows = append(overlapping.Schedule.Windows,
+		fleetmanagementv1alpha1.ScheduleWindow{Start: "0 16 * * FRI", Duration: metav1.Duration{Duration: 4 * time.Hour}})
+
+	disabled := businessHours("")
+	disabled.Enabled = false
+
+	tests := []struct {
+		name       string
+		spec       *fleetmanagementv1alpha1.PipelineSpec
+		now        string
+		wantOn     bool
+		wantReason string
+		wantNext   string
+	}{
+		{"in window", businessHours(""), "2026-10-16T10:00:00Z", true, ReasonInWindow, "2026-10-16T17:00:00Z"},
+		{"weekend", businessHours(""), "2026-10-17T10:00:00Z", false, ReasonOutsideWindow, "2026-10-19T09:00:00Z"},
+		{"time zone", businessHours("America/New_York"), "2026-10-16T10:00:00Z", false, ReasonOutsideWindow, "2026-10-16T13:00:00Z"},
+		{"expires inside window", expired, "2026-10-16T10:00:00Z", true, ReasonInWindow, "2026-10-16T12:00:00Z"},
+		{"expired", expired, "2026-10-16T12:00:00Z", false, ReasonExpired, ""},
+		{"overlapping windows", overlapping, "2026-10-16T10:00:00Z", true, ReasonInWindow, "2026-10-16T20:00:00Z"},
+		{"disabled", disabled, "2026-10-16T10:00:00Z", false, ReasonDisabled, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.spec, mustTime(t, tt.now))
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got.Enabled != tt.wantOn || got.Reason != tt.wantReason {
+				t.Errorf("Evaluate() = %v/%s, want %v/%s", got.Enabled, got.Reason, tt.wantOn, tt.wantReason)
+			}
+			if tt.wantNext == "" {
+				if !got.Next.IsZero() {
+					t.Errorf("Evaluate() next = %v, want none", got.Next)
+				}
+			} else if !got.Next.Equal(mustTime(t, tt.wantNext)) {
+				t.Errorf("Evaluate() next = %v, want %s", got.Next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	spec := businessHours("Mars/Olympus_Mons")
+	spec.Schedule.Windows = append(spec.Schedule.Windows,
+		fleetmanagementv1alpha1.ScheduleWindow{Start: "0 25 * * *", Duration: metav1.Duration{}})
+
+	errs := Validate(spec, field.NewPath("spec"))
+	want := []string{"spec.schedule.timeZone", "spec.schedule.windows[1].start", "spec.schedule.windows[1].duration"}
+	if len(errs) != len(want) {
+		t.Fatalf("Validate() = %v, want %d errors", errs, len(want))
+	}
+	for i, w := range want {
+		if errs[i].Field != w {
+			t.Errorf("error %d field = %q, want %q", i, errs[i].Field, w)
+		}
+	}
+}
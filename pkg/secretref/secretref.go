@@ -0,0 +1,121 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretref substitutes Secret values into pipeline contents.
+//
+// A reference has the form ${secret:<secret-name>/<key>}. Writing $${secret:...}
+// produces the reference text literally. References belong in double-quoted
+// strings: values are escaped for them by the EscapeFunc of the config type,
+// so a value cannot end the string or add configuration of its own.
+// Substituted values are secrets: they are returned so callers can redact them
+// from errors, and never appear in errors returned by this package.
+package secretref
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var referencePattern = regexp.MustCompile(`\$?\$\{secret:([a-z0-9]([-a-z0-9.]*[a-z0-9])?)/([-._a-zA-Z0-9]+)\}`)
+
+// Reference is a key of a Secret used in contents
+type Reference struct {
+	Secret string
+	Key    string
+}
+
+func (r Reference) String() string {
+	return r.Secret + "/" + r.Key
+}
+
+// LookupFunc returns the value of a Secret key and whether it exists
+type LookupFunc func(ref Reference) (string, bool)
+
+// EscapeFunc escapes a value for a double-quoted string of a config type
+type EscapeFunc func(value string) string
+
+// AlloyString escapes a value for an Alloy string literal
+func AlloyString(value string) string {
+	quoted := strconv.Quote(value)
+	return quoted[1 : len(quoted)-1]
+}
+
+// YAMLString escapes a value for a double-quoted YAML scalar of an
+// OpenTelemetry Collector configuration. Go escape sequences are valid in
+// YAML, and $ is doubled since the collector expands ${...} itself.
+func YAMLString(value string) string {
+	return strings.ReplaceAll(AlloyString(value), "$", "$$")
+}
+
+// References returns the Secret references in contents, without duplicates,
+// in the order they first appear
+func References(contents string) []Reference {
+	var refs []Reference
+	seen := map[Reference]bool{}
+	for _, m := range referencePattern.FindAllStringSubmatch(contents, -1) {
+		if strings.HasPrefix(m[0], "$$") {
+			continue
+		}
+		ref := Reference{Secret: m[1], Key: m[3]}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Substitute replaces every reference in contents with its value, escaped by
+// escape. It returns the values substituted, escaped and as is, so they can be
+// redacted from later errors.
+func Substitute(contents string, lookup LookupFunc, escape EscapeFunc) (string, []string, error) {
+	var values []string
+	var missing []string
+	out := referencePattern.ReplaceAllStringFunc(contents, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		m := referencePattern.FindStringSubmatch(match)
+		ref := Reference{Secret: m[1], Key: m[3]}
+		value, ok := lookup(ref)
+		if !ok {
+			missing = append(missing, ref.String())
+			return match
+		}
+		escaped := escape(value)
+		if escaped != value {
+			values = append(values, escaped)
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+		return escaped
+	})
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("unresolved Secret references: %s", strings.Join(missing, ", "))
+	}
+	return out, values, nil
+}
+
+// Redact replaces every occurrence of values in s
+func Redact(s string, values []string) string {
+	for _, value := range values {
+		s = strings.ReplaceAll(s, value, "[REDACTED]")
+	}
+	return s
+}
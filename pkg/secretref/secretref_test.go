@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretref
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func lookup(data map[string]map[string]string) LookupFunc {
+	return func(ref Reference) (string, bool) {
+		value, ok := data[ref.Secret][ref.Key]
+		return value, ok
+	}
+}
+
+func TestReferences(t *testing.T) {
+	contents := `url = "${secret:prom/url}"
+password = "${secret:prom/password}"
+again = "${secret:prom/url}"
+literal = "$${secret:prom/token}"`
+
+	want := []Reference{{Secret: "prom", Key: "url"}, {Secret: "prom", Key: "password"}}
+	if got := References(contents); !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %v, want %v", got, want)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	data := map[string]map[string]string{
+		"prom": {"url": "https://prom.example.com", "password": "s3cr3t"},
+	}
+
+	got, values, err := Substitute(`url = "${secret:prom/url}", password = "${secret:prom/password}", raw = "$${secret:prom/url}"`, lookup(data), AlloyString)
+	if err != nil {
+		t.Fatalf("Substitute() error = %v", err)
+	}
+	if want := `url = "https://prom.example.com", password = "s3cr3t", raw = "${secret:prom/url}"`; got != want {
+		t.Errorf("Substitute() = %q, want %q", got, want)
+	}
+	if want := []string{"https://prom.example.com", "s3cr3t"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Substitute() values = %v, want %v", values, want)
+	}
+
+	_, _, err = Substitute(`${secret:prom/password} ${secret:prom/token} ${secret:other/key}`, lookup(data), AlloyString)
+	if err == nil || err.Error() != "unresolved Secret references: prom/token, other/key" {
+		t.Errorf("Substitute() error = %v", err)
+	}
+	if strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("Substitute() error %q contains a secret value", err)
+	}
+}
+
+func TestSubstituteEscapesValues(t *testing.T) {
+	data := map[string]map[string]string{
+		"prom": {"password": "p\"w}\\\nforward_to = [evil]", "token": "${env:HOME}"},
+	}
+
+	tests := []struct {
+		name   string
+		escape EscapeFunc
+		want   string
+	}{
+		{"Alloy", AlloyString, `password = "p\"w}\\\nforward_to = [evil]", token = "${env:HOME}"`},
+		{"YAML", YAMLString, `password = "p\"w}\\\nforward_to = [evil]", token = "$${env:HOME}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, values, err := Substitute(`password = "${secret:prom/password}", token = "${secret:prom/token}"`, lookup(data), tt.escape)
+			if err != nil {
+				t.Fatalf("Substitute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Substitute() = %q, want %q", got, tt.want)
+			}
+			if redacted := Redact(got, values); strings.Contains(redacted, "evil") {
+				t.Errorf("Redact() = %q, want escaped values redacted", redacted)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got := Redact(`line 2: unexpected "s3cr3t" near "https://prom.example.com"`, []string{"s3cr3t", "https://prom.example.com"})
+	if want := `line 2: unexpected "[REDACTED]" near "[REDACTED]"`; got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulate previews which collectors a pipeline change would affect.
+//
+// A Selector describes which collectors receive a pipeline: its matchers and
+// whether it is enabled. Run evaluates the current and desired selectors
+// against a collector list and reports the collectors that would gain or
+// lose the pipeline.
+package simulate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+	"github.com/grafana/fleet-management-operator/pkg/matchers"
+)
+
+// Selector selects the collectors a pipeline is delivered to.
+// A nil Selector selects no collectors, e.g. for a pipeline that does not exist yet.
+type Selector struct {
+	Matchers matchers.Matchers
+	Enabled  bool
+}
+
+// Selects reports whether the collector receives the pipeline
+func (s *Selector) Selects(c *fleetclient.Collector) bool {
+	if s == nil || !s.Enabled {
+		return false
+	}
+	return s.Matchers.Matches(c.Attributes())
+}
+
+// FromPipeline returns the selector of a Pipeline resource
+func FromPipeline(pipeline *fleetmanagementv1alpha1.Pipeline) (*Selector, error) {
+	ms, errs := matchers.FromSpec(&pipeline.Spec, field.NewPath("spec"))
+	if len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	return &Selector{Matchers: ms, Enabled: pipeline.Spec.Enabled}, nil
+}
+
+// FromRemote returns the selector of a pipeline in Fleet Management.
+// A nil pipeline returns a nil Selector.
+func FromRemote(pipeline *fleetclient.Pipeline) (*Selector, error) {
+	if pipeline == nil {
+		return nil, nil
+	}
+	ms, err := matchers.ParseAll(pipeline.Matchers)
+	if err != nil {
+		return nil, fmt.Errorf("remote pipeline %q has invalid matchers: %w", pipeline.Name, err)
+	}
+	return &Selector{Matchers: ms, Enabled: pipeline.Enabled}, nil
+}
+
+// Result is the outcome of a simulation. Each list is sorted by collector ID.
+type Result struct {
+	// Gained are collectors that would start receiving the pipeline
+	Gained []*fleetclient.Collector
+
+	// Lost are collectors that would stop receiving the pipeline
+	Lost []*fleetclient.Collector
+
+	// Kept are collectors that receive the pipeline before and after the change
+	Kept []*fleetclient.Collector
+
+	// Inactive is the number of collectors skipped because they are marked inactive
+	Inactive int
+}
+
+// Changed reports whether the change affects any collector
+func (r *Result) Changed() bool {
+	return len(r.Gained) > 0 || len(r.Lost) > 0
+}
+
+// Run compares the collectors selected by current and desired.
+// Collectors marked inactive are skipped.
+func Run(current, desired *Selector, collectors []*fleetclient.Collector) *Result {
+	result := &Result{}
+	for _, c := range collectors {
+		if c.MarkedInactiveAt != nil {
+			result.Inactive++
+			continue
+		}
+
+		before, after := current.Selects(c), desired.Selects(c)
+		switch {
+		case before && after:
+			result.Kept = append(result.Kept, c)
+		case after:
+			result.Gained = append(result.Gained, c)
+		case before:
+			result.Lost = append(result.Lost, c)
+		}
+	}
+
+	for _, list := range [][]*fleetclient.Collector{result.Gained, result.Lost, result.Kept} {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	return result
+}
+
+// LoadCollectors reads a collector list from YAML or JSON. The document is
+// either a list of collectors or a ListCollectors response, using the Fleet
+// Management field names:
+//
+//	collectors:
+//	  - id: alloy-node-1
+//	    localAttributes:
+//	      env: prod
+//	    remoteAttributes:
+//	      team: platform
+func LoadCollectors(r io.Reader) ([]*fleetclient.Collector, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collectors: %w", err)
+	}
+
+	var collectors []*fleetclient.Collector
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '-' || trimmed[0] == '[') {
+		if err := yaml.UnmarshalStrict(data, &collectors); err != nil {
+			return nil, fmt.Errorf("failed to parse collectors: %w", err)
+		}
+	} else {
+		var resp fleetclient.ListCollectorsResponse
+		if err := yaml.UnmarshalStrict(data, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse collectors: %w", err)
+		}
+		collectors = resp.Collectors
+	}
+
+	for i, c := range collectors {
+		if c == nil || c.ID == "" {
+			return nil, fmt.Errorf("collector %d has no id", i)
+		}
+	}
+	return collectors, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/fleetclient"
+)
+
+func ids(collectors []*fleetclient.Collector) []string {
+	var out []string
+	for _, c := range collectors {
+		out = append(out, c.ID)
+	}
+	return out
+}
+
+func TestRun(t *testing.T) {
+	inactive := time.Now()
+	collectors := []*fleetclient.Collector{
+		{ID: "eu-1", RemoteAttributes: map[string]string{"region": "eu", "env": "prod"}},
+		{ID: "us-2", RemoteAttributes: map[string]string{"region": "us", "env": "prod"}},
+		{ID: "us-1", RemoteAttributes: map[string]string{"region": "us", "env": "prod"}},
+		{ID: "us-dev", RemoteAttributes: map[string]string{"region": "us", "env": "dev"}},
+		{ID: "us-old", RemoteAttributes: map[string]string{"region": "us", "env": "prod"}, MarkedInactiveAt: &inactive},
+	}
+
+	current, err := FromRemote(&fleetclient.Pipeline{Matchers: []string{"env=prod"}, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := FromPipeline(&fleetmanagementv1alpha1.Pipeline{
+		Spec: fleetmanagementv1alpha1.PipelineSpec{
+			Matchers: []string{"region=us"},
+			Enabled:  true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := Run(current, desired, collectors)
+	if got, want := ids(result.Gained), []string{"us-dev"}; !slices.Equal(got, want) {
+		t.Errorf("Gained = %v, want %v", got, want)
+	}
+	if got, want := ids(result.Lost), []string{"eu-1"}; !slices.Equal(got, want) {
+		t.Errorf("Lost = %v, want %v", got, want)
+	}
+	if got, want := ids(result.Kept), []string{"us-1", "us-2"}; !slices.Equal(got, want) {
+		t.Errorf("Kept = %v, want %v", got, want)
+	}
+	if result.Inactive != 1 {
+		t.Errorf("Inactive = %d, want 1", result.Inactive)
+	}
+	if !result.Changed() {
+		t.Errorf("Changed() = false, want true")
+	}
+}
+
+func TestRunNewAndDisabledPipelines(t *testing.T) {
+	collectors := []*fleetclient.Collector{{ID: "a"}, {ID: "b"}}
+	enabled := &Selector{Enabled: true}
+	disabled := &Selector{Enabled: false}
+
+	if got := ids(Run(nil, enabled, collectors).Gained); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("new pipeline Gained = %v, want [a b]", got)
+	}
+	if got := ids(Run(enabled, disabled, collectors).Lost); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("disabled pipeline Lost = %v, want [a b]", got)
+	}
+	if Run(disabled, disabled, collectors).Changed() {
+		t.Errorf("disabled pipeline should not change")
+	}
+}
+
+func TestFromPipelineRejectsInvalidMatchers(t *testing.T) {
+	_, err := FromPipeline(&fleetmanagementv1alpha1.Pipeline{
+		Spec: fleetmanagementv1alpha1.PipelineSpec{Matchers: []string{"env"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "spec.matchers[0]") {
+		t.Errorf("FromPipeline() error = %v, want error for spec.matchers[0]", err)
+	}
+}
+
+func TestLoadCollectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "yaml list",
+			in: `
+- id: a
+  localAttributes:
+    env: prod
+- id: b
+`,
+			want: []string{"a", "b"},
+		},
+		{
+			name: "list collectors response",
+			in:   `{"collectors": [{"id": "a", "remoteAttributes": {"env": "prod"}}]}`,
+			want: []string{"a"},
+		},
+		{
+			name:    "missing id",
+			in:      `- localAttributes: {env: prod}`,
+			wantErr: true,
+		},
+		{
+			name: "unknown field",
+			in: `- id: a
+  attrs: {env: prod}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadCollectors(strings.NewReader(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadCollectors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(ids(got), tt.want) {
+				t.Errorf("LoadCollectors() = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+}
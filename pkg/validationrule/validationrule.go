@@ -0,0 +1,208 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validationrule evaluates the CEL expressions of PipelineValidationRules
+// against Pipelines.
+//
+// Expressions see three variables: object, the Pipeline, oldObject, the
+// Pipeline before an update or null on create, and contents, its
+// spec.contents. Besides the CEL standard library and string extensions, they
+// can call:
+//
+//	lines(string) list(string)           the lines of contents
+//	alloyBlocks(string) list(string)     the names of all Alloy blocks, nested ones included
+//	alloyComponents(string) list(string) the name and label of top-level Alloy blocks, e.g. "prometheus.remote_write.default"
+//	otelComponents(string) list(string)  the OpenTelemetry Collector components, e.g. "exporters.otlphttp/primary"
+//
+// The Alloy and OpenTelemetry helpers fail on contents that cannot be parsed.
+package validationrule
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+	"github.com/grafana/fleet-management-operator/pkg/alloy"
+	"github.com/grafana/fleet-management-operator/pkg/otelcol"
+)
+
+// costLimit bounds the work done by one evaluation
+const costLimit = 1_000_000
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	// programs caches compiled expressions by their source
+	programs sync.Map
+)
+
+// newEnv declares the variables and helper functions of rule expressions
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		cel.Variable("contents", cel.StringType),
+		ext.Strings(),
+		stringListFunction("lines", func(s string) ([]string, error) {
+			return strings.Split(s, "\n"), nil
+		}),
+		stringListFunction("alloyBlocks", func(s string) ([]string, error) {
+			blocks, err := alloy.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			alloy.Walk(blocks, func(b alloy.Block) { names = append(names, b.Name) })
+			return names, nil
+		}),
+		stringListFunction("alloyComponents", func(s string) ([]string, error) {
+			blocks, err := alloy.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]string, len(blocks))
+			for i, b := range blocks {
+				ids[i] = b.ID()
+			}
+			return ids, nil
+		}),
+		stringListFunction("otelComponents", func(s string) ([]string, error) {
+			components, err := otelcol.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]string, len(components))
+			for i, c := range components {
+				ids[i] = c.String()
+			}
+			return ids, nil
+		}),
+	)
+}
+
+// stringListFunction declares a global function from string to list(string)
+func stringListFunction(name string, fn func(string) ([]string, error)) cel.EnvOption {
+	return cel.Function(name,
+		cel.Overload(name+"_string", []*cel.Type{cel.StringType}, cel.ListType(cel.StringType),
+			cel.UnaryBinding(func(arg ref.Val) ref.Val {
+				s, ok := arg.(types.String)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(arg)
+				}
+				out, err := fn(string(s))
+				if err != nil {
+					return types.NewErr("%s: %v", name, err)
+				}
+				return types.NewStringList(types.DefaultTypeAdapter, out)
+			})))
+}
+
+// Compile checks an expression and returns its program. Programs are cached
+// by expression.
+func Compile(expression string) (cel.Program, error) {
+	if prg, ok := programs.Load(expression); ok {
+		return prg.(cel.Program), nil
+	}
+
+	envOnce.Do(func() { env, envErr = newEnv() })
+	if envErr != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", envErr)
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, not %s", t)
+	}
+	prg, err := env.Program(ast, cel.CostLimit(costLimit), cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, err
+	}
+	programs.Store(expression, prg)
+	return prg, nil
+}
+
+// Applies reports whether the rule selects the pipeline
+func Applies(rule *fleetmanagementv1alpha1.PipelineValidationRule, pipeline *fleetmanagementv1alpha1.Pipeline) (bool, error) {
+	spec := &rule.Spec
+	if len(spec.Namespaces) > 0 && !slices.Contains(spec.Namespaces, pipeline.Namespace) {
+		return false, nil
+	}
+	if spec.PipelineSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(spec.PipelineSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid pipelineSelector: %w", err)
+	}
+	return selector.Matches(labels.Set(pipeline.Labels)), nil
+}
+
+// Evaluate reports whether the pipeline passes the rule. oldPipeline is nil on
+// create.
+func Evaluate(ctx context.Context, rule *fleetmanagementv1alpha1.PipelineValidationRule, pipeline, oldPipeline *fleetmanagementv1alpha1.Pipeline) (bool, error) {
+	prg, err := Compile(rule.Spec.Expression)
+	if err != nil {
+		return false, err
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pipeline)
+	if err != nil {
+		return false, err
+	}
+	var oldObject any
+	if oldPipeline != nil {
+		if oldObject, err = runtime.DefaultUnstructuredConverter.ToUnstructured(oldPipeline); err != nil {
+			return false, err
+		}
+	}
+
+	out, _, err := prg.ContextEval(ctx, map[string]any{
+		"object":    object,
+		"oldObject": oldObject,
+		"contents":  pipeline.Spec.Contents,
+	})
+	if err != nil {
+		return false, err
+	}
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %s, not a bool", out.Type().TypeName())
+	}
+	return passed, nil
+}
+
+// Message returns the message reported for a pipeline failing the rule
+func Message(rule *fleetmanagementv1alpha1.PipelineValidationRule) string {
+	if rule.Spec.Message != "" {
+		return fmt.Sprintf("PipelineValidationRule %s: %s", rule.Name, rule.Spec.Message)
+	}
+	return fmt.Sprintf("PipelineValidationRule %s: failed %s", rule.Name, rule.Spec.Expression)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validationrule
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetmanagementv1alpha1 "github.com/grafana/fleet-management-operator/api/v1alpha1"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{`object.spec.enabled`, false},
+		{`contents.contains("external_labels")`, false},
+		{`"prometheus.remote_write" in alloyBlocks(contents)`, false},
+		{`size(lines(contents)) < 100`, false},
+		{`contents`, true},
+		{`object.spec.enabled &&`, true},
+		{`unknown(contents)`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if _, err := Compile(tt.expression); (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	alloyContents := `prometheus.remote_write "default" {
+  endpoint {
+    url = "https://prom.example.com"
+  }
+  external_labels = { cluster = "prod" }
+}
+`
+	tests := []struct {
+		name       string
+		expression string
+		pipeline   fleetmanagementv1alpha1.Pipeline
+		old        *fleetmanagementv1alpha1.Pipeline
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name:       "external labels with cluster",
+			expression: `contents.matches("external_labels\\s*=\\s*\\{[^}]*cluster")`,
+			pipeline:   fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{Contents: alloyContents}},
+			want:       true,
+		},
+		{
+			name:       "disabled pipeline without owner",
+			expression: `object.spec.enabled || ("annotations" in object.metadata && "owner" in object.metadata.annotations)`,
+			pipeline:   fleetmanagementv1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "metrics"}},
+			want:       false,
+		},
+		{
+			name:       "disabled pipeline with owner",
+			expression: `object.spec.enabled || ("annotations" in object.metadata && "owner" in object.metadata.annotations)`,
+			pipeline: fleetmanagementv1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"owner": "team-a"}},
+			},
+			want: true,
+		},
+		{
+			name:       "alloy components",
+			expression: `alloyComponents(contents) == ["prometheus.remote_write.default"] && "endpoint" in alloyBlocks(contents)`,
+			pipeline:   fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{Contents: alloyContents}},
+			want:       true,
+		},
+		{
+			name:       "otel components",
+			expression: `!("exporters.debug" in otelComponents(contents))`,
+			pipeline: fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{
+				Contents: "exporters:\n  debug: {}\n",
+			}},
+			want: false,
+		},
+		{
+			name:       "old object on update",
+			expression: `oldObject == null || oldObject.spec.configType == object.spec.configType`,
+			pipeline:   fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{ConfigType: "Alloy"}},
+			old:        &fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{ConfigType: "OpenTelemetryCollector"}},
+			want:       false,
+		},
+		{
+			name:       "unparsable contents",
+			expression: `size(alloyBlocks(contents)) > 0`,
+			pipeline:   fleetmanagementv1alpha1.Pipeline{Spec: fleetmanagementv1alpha1.PipelineSpec{Contents: "logging {"}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &fleetmanagementv1alpha1.PipelineValidationRule{
+				Spec: fleetmanagementv1alpha1.PipelineValidationRuleSpec{Expression: tt.expression},
+			}
+			got, err := Evaluate(context.Background(), rule, &tt.pipeline, tt.old)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplies(t *testing.T) {
+	rule := &fleetmanagementv1alpha1.PipelineValidationRule{
+		Spec: fleetmanagementv1alpha1.PipelineValidationRuleSpec{
+			Namespaces:       []string{"prod"},
+			PipelineSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+		},
+	}
+	tests := []struct {
+		namespace string
+		labels    map[string]string
+		want      bool
+	}{
+		{"prod", map[string]string{"tier": "critical"}, true},
+		{"prod", nil, false},
+		{"dev", map[string]string{"tier": "critical"}, false},
+	}
+	for _, tt := range tests {
+		pipeline := &fleetmanagementv1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace, Labels: tt.labels},
+		}
+		got, err := Applies(rule, pipeline)
+		if err != nil {
+			t.Fatalf("Applies() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Applies(%s, %v) = %v, want %v", tt.namespace, tt.labels, got, tt.want)
+		}
+	}
+}